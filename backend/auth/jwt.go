@@ -0,0 +1,134 @@
+package auth
+
+import (
+	"errors"
+	"fleetify-backend/models"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	TokenTypeAccess  = "access"
+	TokenTypeRefresh = "refresh"
+)
+
+var (
+	jwtSecret  []byte
+	accessTTL  = 15 * time.Minute
+	refreshTTL = 7 * 24 * time.Hour
+)
+
+// Claims isi payload JWT yang dikeluarkan oleh /api/auth
+type Claims struct {
+	UserID     uint   `json:"uid"`
+	Username   string `json:"username"`
+	Role       string `json:"role"`
+	EmployeeID string `json:"employee_id,omitempty"`
+	TokenType  string `json:"typ"`
+	jwt.RegisteredClaims
+}
+
+type TokenPair struct {
+	AccessToken  string `json:"access_token"`
+	RefreshToken string `json:"refresh_token"`
+	TokenType    string `json:"token_type"`
+	ExpiresIn    int64  `json:"expires_in"` // detik
+}
+
+// LoadConfig membaca JWT_SECRET, JWT_ACCESS_TTL dan JWT_REFRESH_TTL dari env
+func LoadConfig() error {
+	secret := os.Getenv("JWT_SECRET")
+	if secret == "" {
+		return errors.New("JWT_SECRET is not set")
+	}
+	jwtSecret = []byte(secret)
+
+	if v := os.Getenv("JWT_ACCESS_TTL"); v != "" {
+		ttl, err := parseTTL(v)
+		if err != nil {
+			return fmt.Errorf("invalid JWT_ACCESS_TTL: %w", err)
+		}
+		accessTTL = ttl
+	}
+	if v := os.Getenv("JWT_REFRESH_TTL"); v != "" {
+		ttl, err := parseTTL(v)
+		if err != nil {
+			return fmt.Errorf("invalid JWT_REFRESH_TTL: %w", err)
+		}
+		refreshTTL = ttl
+	}
+	return nil
+}
+
+// parseTTL menerima format time.Duration ("15m", "168h") atau angka detik
+func parseTTL(v string) (time.Duration, error) {
+	if secs, err := strconv.Atoi(v); err == nil {
+		return time.Duration(secs) * time.Second, nil
+	}
+	return time.ParseDuration(v)
+}
+
+// GenerateTokenPair membuat access token dan refresh token untuk user
+func GenerateTokenPair(user models.User) (TokenPair, error) {
+	access, err := signToken(user, TokenTypeAccess, accessTTL)
+	if err != nil {
+		return TokenPair{}, err
+	}
+	refresh, err := signToken(user, TokenTypeRefresh, refreshTTL)
+	if err != nil {
+		return TokenPair{}, err
+	}
+	return TokenPair{
+		AccessToken:  access,
+		RefreshToken: refresh,
+		TokenType:    "Bearer",
+		ExpiresIn:    int64(accessTTL.Seconds()),
+	}, nil
+}
+
+func signToken(user models.User, tokenType string, ttl time.Duration) (string, error) {
+	if len(jwtSecret) == 0 {
+		return "", errors.New("JWT_SECRET is not set")
+	}
+	employeeID := ""
+	if user.EmployeeID != nil {
+		employeeID = *user.EmployeeID
+	}
+
+	now := time.Now()
+	claims := Claims{
+		UserID:     user.ID,
+		Username:   user.Username,
+		Role:       user.Role,
+		EmployeeID: employeeID,
+		TokenType:  tokenType,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   strconv.FormatUint(uint64(user.ID), 10),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(now.Add(ttl)),
+		},
+	}
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(jwtSecret)
+}
+
+// ParseToken memvalidasi signature, masa berlaku dan jenis token
+func ParseToken(tokenStr string, expectedType string) (*Claims, error) {
+	if len(jwtSecret) == 0 {
+		return nil, errors.New("JWT_SECRET is not set")
+	}
+	claims := &Claims{}
+	_, err := jwt.ParseWithClaims(tokenStr, claims, func(t *jwt.Token) (interface{}, error) {
+		return jwtSecret, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithExpirationRequired())
+	if err != nil {
+		return nil, err
+	}
+	if claims.TokenType != expectedType {
+		return nil, fmt.Errorf("unexpected token type %q", claims.TokenType)
+	}
+	return claims, nil
+}
@@ -0,0 +1,308 @@
+package main
+
+import (
+	"flag"
+	"fleetify-backend/config"
+	"fleetify-backend/idgen"
+	"fleetify-backend/jobs"
+	"fleetify-backend/migrations"
+	"fleetify-backend/models"
+	"fleetify-backend/repositories"
+	"fleetify-backend/schedule"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"time"
+)
+
+// runCommand menjalankan subcommand CLI lalu keluar
+func runCommand(name string, args []string) {
+	switch name {
+	case "migrate":
+		runMigrate(args)
+	case "backfill-ids":
+		runBackfillIDs()
+	case "recompute-attendance":
+		runRecomputeAttendance(args)
+	case "detect-absences":
+		runDetectAbsences(args)
+	case "auto-close":
+		runAutoClose(args)
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n", name)
+		fmt.Fprintln(os.Stderr, "usage: fleetify-backend [migrate up|down|status | backfill-ids | recompute-attendance | detect-absences | auto-close]")
+		os.Exit(2)
+	}
+}
+
+// runMigrate: migrate up [-steps N] | down [-steps N] | status
+func runMigrate(args []string) {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, "usage: fleetify-backend migrate up|down|status [-steps N]")
+		os.Exit(2)
+	}
+
+	fs := flag.NewFlagSet("migrate "+args[0], flag.ExitOnError)
+	steps := fs.Int("steps", 0, "number of migrations to apply (up: default all, down: default 1)")
+	fs.Parse(args[1:])
+
+	switch args[0] {
+	case "up":
+		applied, err := migrations.Up(config.DB, *steps)
+		for _, m := range applied {
+			log.Printf("applied %04d_%s", m.Version, m.Name)
+		}
+		if err != nil {
+			log.Fatal(err)
+		}
+		if len(applied) == 0 {
+			log.Println("no pending migrations")
+		}
+	case "down":
+		reverted, err := migrations.Down(config.DB, *steps)
+		for _, m := range reverted {
+			log.Printf("reverted %04d_%s", m.Version, m.Name)
+		}
+		if err != nil {
+			log.Fatal(err)
+		}
+		if len(reverted) == 0 {
+			log.Println("nothing to revert")
+		}
+	case "status":
+		rows, err := migrations.Status(config.DB)
+		if err != nil {
+			log.Fatal(err)
+		}
+		for _, row := range rows {
+			status := "pending"
+			if row.AppliedAt != nil {
+				status = "applied " + row.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Printf("%04d_%-40s %s\n", row.Version, row.Name, status)
+		}
+	default:
+		fmt.Fprintf(os.Stderr, "unknown migrate action %q, expected up, down or status\n", args[0])
+		os.Exit(2)
+	}
+}
+
+// runBackfillIDs menyamakan counter id_sequences dengan kode EMP/ATT yang sudah ada
+func runBackfillIDs() {
+	repos := repositories.NewGormRepositories(config.DB)
+
+	targets := []struct {
+		format idgen.Format
+		codes  func(prefix string) ([]string, error)
+	}{
+		{idgen.Employee, repos.Employees.CodesWithPrefix},
+		{idgen.Attendance, repos.Attendances.CodesWithPrefix},
+	}
+	for _, target := range targets {
+		maxes, err := idgen.Backfill(repos.Sequences, target.format, target.codes)
+		if err != nil {
+			log.Fatalf("backfill %s: %v", target.format.Name, err)
+		}
+		if len(maxes) == 0 {
+			log.Printf("%s: no existing %s codes", target.format.Name, target.format.Prefix)
+		}
+		for period, n := range maxes {
+			if period == "" {
+				period = "-"
+			}
+			log.Printf("%s: period %s counter >= %d", target.format.Name, period, n)
+		}
+	}
+}
+
+// runRecomputeAttendance menerapkan ulang aturan jam kerja yang berlaku sekarang ke
+// attendance yang sudah tercatat (business_date, status, menit, snapshot aturan)
+func runRecomputeAttendance(args []string) {
+	fs := flag.NewFlagSet("recompute-attendance", flag.ExitOnError)
+	from := fs.String("from", "", "first business date to recompute (YYYY-MM-DD)")
+	to := fs.String("to", "", "last business date to recompute (YYYY-MM-DD)")
+	employeeID := fs.String("employee", "", "only recompute this employee_id")
+	dryRun := fs.Bool("dry-run", false, "report changes without saving them")
+	fs.Parse(args)
+
+	for _, date := range []string{*from, *to} {
+		if _, err := time.Parse(schedule.DateLayout, date); date != "" && err != nil {
+			log.Fatalf("invalid date %q, expected YYYY-MM-DD", date)
+		}
+	}
+
+	repos := repositories.NewGormRepositories(config.DB)
+	histories, err := repos.Attendances.FindHistories(repositories.HistoryFilter{
+		DateFrom:   *from,
+		DateTo:     *to,
+		EmployeeID: *employeeID,
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+	holidays, err := repos.Holidays.FindAll(repositories.HolidayFilter{DateFrom: *from, DateTo: *to})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// Business date dihitung ulang sekali per attendance. Semua perubahan disimpan
+	// dalam satu transaksi, gagal di tengah berarti tidak ada yang berubah.
+	attendances := map[string]*models.Attendance{}
+	attendanceChanged, historyChanged := 0, 0
+	err = repos.Transaction(func(tx repositories.Repositories) error {
+		for i := range histories {
+			history := &histories[i]
+			attendance, ok := attendances[history.AttendanceID]
+			if !ok {
+				att := history.Attendance
+				attendance = &att
+				attendances[history.AttendanceID] = attendance
+
+				businessDate := schedule.BusinessDate(history.Employee, attendance.ClockIn).Format(schedule.DateLayout)
+				if businessDate != attendance.BusinessDate {
+					log.Printf("%s: business_date %q -> %q", attendance.AttendanceID, attendance.BusinessDate, businessDate)
+					attendance.BusinessDate = businessDate
+					attendanceChanged++
+					if !*dryRun {
+						if err := tx.Attendances.Update(attendance); err != nil {
+							return fmt.Errorf("update %s: %w", attendance.AttendanceID, err)
+						}
+					}
+				}
+			}
+
+			// Clock out auto-close yang belum di-review tetap berstatus auto_closed,
+			// baris koreksi (type 5) tidak punya status
+			if history.AutoClosed && history.ReviewStatus == models.ReviewPending {
+				continue
+			}
+			if !history.AttendanceType.IsPunch() {
+				continue
+			}
+			before := *history
+			schedule.Apply(history, history.Employee, *attendance, holidays)
+			if before.Status == history.Status && before.MinutesLate == history.MinutesLate &&
+				before.MinutesEarly == history.MinutesEarly && before.RuleSnapshot == history.RuleSnapshot &&
+				before.Description == history.Description {
+				continue
+			}
+			log.Printf("%s history %d: %q -> %q", history.AttendanceID, history.ID, before.Status, history.Status)
+			historyChanged++
+			if !*dryRun {
+				if err := tx.Attendances.UpdateHistory(history); err != nil {
+					return fmt.Errorf("update history %d: %w", history.ID, err)
+				}
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	suffix := ""
+	if *dryRun {
+		suffix = " (dry run, nothing saved)"
+	}
+	log.Printf("checked %d histories: %d attendances and %d histories changed%s",
+		len(histories), attendanceChanged, historyChanged, suffix)
+}
+
+// runDetectAbsences mencatat absen untuk satu tanggal (-date, default kemarin)
+// atau rentang -from - -to, sama dengan job harian di server
+func runDetectAbsences(args []string) {
+	fs := flag.NewFlagSet("detect-absences", flag.ExitOnError)
+	date := fs.String("date", "", "business date to check (YYYY-MM-DD, default yesterday)")
+	from := fs.String("from", "", "first business date of a range (YYYY-MM-DD)")
+	to := fs.String("to", "", "last business date of a range (YYYY-MM-DD, default yesterday)")
+	dryRun := fs.Bool("dry-run", false, "report absences without saving them")
+	fs.Parse(args)
+
+	yesterday := time.Now().AddDate(0, 0, -1).Format(schedule.DateLayout)
+	if *date == "" && *from == "" {
+		*date = yesterday
+	}
+	if *date != "" {
+		*from, *to = *date, *date
+	}
+	if *to == "" {
+		*to = yesterday
+	}
+
+	start, errFrom := time.Parse(schedule.DateLayout, *from)
+	end, errTo := time.Parse(schedule.DateLayout, *to)
+	if errFrom != nil || errTo != nil {
+		log.Fatal("invalid date, expected YYYY-MM-DD")
+	}
+	if *to > yesterday {
+		log.Fatalf("cannot detect absences for %s, only past dates can be checked", *to)
+	}
+
+	repos := repositories.NewGormRepositories(config.DB)
+	for day := start; !day.After(end); day = day.AddDate(0, 0, 1) {
+		result, err := jobs.DetectAbsences(repos, day.Format(schedule.DateLayout), *dryRun)
+		if err != nil {
+			log.Fatal(err)
+		}
+		logAbsenceResult(result, *dryRun)
+	}
+}
+
+func logAbsenceResult(result jobs.AbsenceResult, dryRun bool) {
+	suffix := ""
+	if dryRun {
+		suffix = " (dry run, nothing saved)"
+	}
+	log.Printf("%s: checked %d employees, %d absent, %d already recorded%s",
+		result.Date, result.Checked, len(result.Absent), result.Existing, suffix)
+	for _, employeeID := range result.Absent {
+		log.Printf("  absent: %s", employeeID)
+	}
+}
+
+// runAutoClose menutup attendance yang lupa clock out, sama dengan job di server
+func runAutoClose(args []string) {
+	defaultAfter, err := autoCloseAfter()
+	if err != nil {
+		log.Fatal(err)
+	}
+	fs := flag.NewFlagSet("auto-close", flag.ExitOnError)
+	after := fs.Duration("after", defaultAfter, "close attendances this long after the shift end time")
+	dryRun := fs.Bool("dry-run", false, "report attendances without closing them")
+	fs.Parse(args)
+
+	repos := repositories.NewGormRepositories(config.DB)
+	result, err := jobs.AutoCloseAttendances(repos, time.Now(), *after, *dryRun)
+	if err != nil {
+		log.Fatal(err)
+	}
+	logAutoCloseResult(result, *dryRun)
+}
+
+// autoCloseAfter batas auto-close setelah jam pulang shift dari AUTO_CLOSE_AFTER_HOURS (default 4)
+func autoCloseAfter() (time.Duration, error) {
+	value := os.Getenv("AUTO_CLOSE_AFTER_HOURS")
+	if value == "" {
+		return 4 * time.Hour, nil
+	}
+	hours, err := strconv.ParseFloat(value, 64)
+	if err != nil || hours < 0 {
+		return 0, fmt.Errorf("invalid AUTO_CLOSE_AFTER_HOURS %q, expected number of hours", value)
+	}
+	return time.Duration(hours * float64(time.Hour)), nil
+}
+
+func logAutoCloseResult(result jobs.AutoCloseResult, dryRun bool) {
+	suffix := ""
+	if dryRun {
+		suffix = " (dry run, nothing saved)"
+	}
+	log.Printf("auto-close: %d open attendances, %d closed%s", result.Checked, len(result.Closed), suffix)
+	for _, attendanceID := range result.Closed {
+		log.Printf("  closed: %s", attendanceID)
+	}
+	for _, attendanceID := range result.Skipped {
+		log.Printf("  skipped: %s (clock in after the shift end, clock out manually)", attendanceID)
+	}
+}
@@ -5,8 +5,10 @@ import (
 	"log"
 	"os"
 
+	"github.com/glebarez/sqlite"
 	"github.com/joho/godotenv"
 	"gorm.io/driver/mysql"
+	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
 
@@ -19,19 +21,13 @@ func ConnectDB() {
 		log.Fatal("Error loading .env file")
 	}
 
-	// Ambil variabel dari .env
-	user := os.Getenv("DB_USER")
-	pass := os.Getenv("DB_PASS")
-	host := os.Getenv("DB_HOST")
-	port := os.Getenv("DB_PORT")
-	name := os.Getenv("DB_NAME")
-
-	// Format DSN MySQL
-	dsn := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?charset=utf8mb4&parseTime=True&loc=Local",
-		user, pass, host, port, name)
+	dialector, err := openDialector(os.Getenv("DB_DRIVER"))
+	if err != nil {
+		log.Fatal(err)
+	}
 
 	// Koneksi ke database pakai GORM
-	database, err := gorm.Open(mysql.Open(dsn), &gorm.Config{})
+	database, err := gorm.Open(dialector, &gorm.Config{})
 	if err != nil {
 		log.Fatal("Failed to connect to database: ", err)
 	}
@@ -39,3 +35,38 @@ func ConnectDB() {
 	DB = database
 	fmt.Println("Database connected successfully!")
 }
+
+// openDialector memilih driver GORM berdasarkan DB_DRIVER (default mysql)
+func openDialector(driver string) (gorm.Dialector, error) {
+	// Ambil variabel dari .env
+	user := os.Getenv("DB_USER")
+	pass := os.Getenv("DB_PASS")
+	host := os.Getenv("DB_HOST")
+	port := os.Getenv("DB_PORT")
+	name := os.Getenv("DB_NAME")
+
+	switch driver {
+	case "", "mysql":
+		// Format DSN MySQL
+		dsn := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?charset=utf8mb4&parseTime=True&loc=Local",
+			user, pass, host, port, name)
+		return mysql.Open(dsn), nil
+	case "postgres":
+		sslMode := os.Getenv("DB_SSLMODE")
+		if sslMode == "" {
+			sslMode = "disable"
+		}
+		dsn := fmt.Sprintf("host=%s user=%s password=%s dbname=%s port=%s sslmode=%s",
+			host, user, pass, name, port, sslMode)
+		return postgres.Open(dsn), nil
+	case "sqlite":
+		// DB_NAME = path file database, contoh fleetify.db (":memory:" untuk sementara)
+		if name == "" {
+			name = "fleetify.db"
+		}
+		dsn := name + "?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)"
+		return sqlite.Open(dsn), nil
+	default:
+		return nil, fmt.Errorf("unsupported DB_DRIVER %q, expected mysql, postgres or sqlite", driver)
+	}
+}
@@ -1,131 +1,376 @@
 package controllers
 
 import (
-	"fleetify-backend/config"
+	"encoding/json"
+	"errors"
+	"fleetify-backend/idgen"
+	"fleetify-backend/middlewares"
 	"fleetify-backend/models"
+	"fleetify-backend/repositories"
+	"fleetify-backend/schedule"
 	"fmt"
 	"net/http"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
+type AttendanceController struct {
+	attendances repositories.AttendanceRepository
+	employees   repositories.EmployeeRepository
+	leaves      repositories.LeaveRepository
+	holidays    repositories.HolidayRepository
+	transactor  repositories.Transactor
+}
+
+func NewAttendanceController(attendances repositories.AttendanceRepository, employees repositories.EmployeeRepository, leaves repositories.LeaveRepository, holidays repositories.HolidayRepository, transactor repositories.Transactor) *AttendanceController {
+	return &AttendanceController{attendances: attendances, employees: employees, leaves: leaves, holidays: holidays, transactor: transactor}
+}
+
+// errAttendanceOpen membatalkan transaksi clock in kalau masih ada attendance terbuka
+var errAttendanceOpen = errors.New("attendance already open")
+
+// Error sentinel untuk membatalkan transaksi istirahat
+var (
+	errAttendanceClosed = errors.New("attendance already closed")
+	errBreakOpen        = errors.New("break already open")
+	errNoOpenBreak      = errors.New("no open break")
+	errBreakOrder       = errors.New("break out of order")
+)
+
+// Error sentinel untuk membatalkan transaksi clock out
+var (
+	errClockOutBeforeClockIn = errors.New("clock out before clock in")
+	errBreakNotEnded         = errors.New("break not ended")
+)
+
+// Kode error 409 untuk urutan punch yang tidak valid
+const (
+	CodeAttendanceAlreadyOpen   = "attendance_already_open"
+	CodeAttendanceAlreadyClosed = "attendance_already_closed"
+	CodeClockOutBeforeClockIn   = "clock_out_before_clock_in"
+	CodeBreakAlreadyOpen        = "break_already_open" // mulai istirahat saat masih istirahat
+	CodeNoOpenBreak             = "no_open_break"      // selesai istirahat tanpa mulai istirahat
+	CodeBreakOutOfOrder         = "break_out_of_order" // sebelum clock in / istirahat sebelumnya, clock out sebelum istirahat selesai
+	CodeBreakNotEnded           = "break_not_ended"    // clock out saat masih istirahat
+)
+
+// CodeInvalidAttendanceType kode error 400 untuk ?type= yang tidak dikenal
+const CodeInvalidAttendanceType = "invalid_attendance_type"
+
+// Kode error 422 untuk employee yang tidak bisa absen
+const (
+	CodeEmployeeNotFound = "employee_not_found"
+	CodeEmployeeInactive = "employee_inactive"
+)
+
 type AttendanceResp struct {
 	ID           uint       `json:"id"`
 	EmployeeID   string     `json:"employee_id"`
 	AttendanceID string     `json:"attendance_id"`
 	ClockIn      time.Time  `json:"clock_in"`
 	ClockOut     *time.Time `json:"clock_out"`
+	BusinessDate string     `json:"business_date"`
+}
+
+// BreakResp semua istirahat di satu attendance
+type BreakResp struct {
+	AttendanceID string           `json:"attendance_id"`
+	EmployeeID   string           `json:"employee_id"`
+	OnBreak      bool             `json:"on_break"`
+	Breaks       []schedule.Break `json:"breaks"`
 }
 
 type AttendanceLogResp struct {
-	ID             uint   `json:"id"`
-	EmployeeID     string `json:"employee_id"`
-	AttendanceID   string `json:"attendance_id"`
-	Name           string `json:"name"`
-	DateAttendance string `json:"date_attendance"`
-	AttendanceType int    `json:"attendance_type"`
-	Description    string `json:"description"`
-	Department     string `json:"department"`
-	ClockIn        string `json:"clock_in"`
-	ClockOut       string `json:"clock_out"`
+	ID             uint                       `json:"id"`
+	EmployeeID     string                     `json:"employee_id"`
+	AttendanceID   string                     `json:"attendance_id"`
+	Name           string                     `json:"name"`
+	DateAttendance string                     `json:"date_attendance"`
+	BusinessDate   string                     `json:"business_date"`
+	AttendanceType models.AttendanceEventType `json:"attendance_type"`
+	Description    string                     `json:"description"`
+	Status         string                     `json:"status"`
+	MinutesLate    int                        `json:"minutes_late"`
+	MinutesEarly   int                        `json:"minutes_early"`
+	Department     string                     `json:"department"`
+	ClockIn        string                     `json:"clock_in"`
+	ClockOut       string                     `json:"clock_out"`
+	Schedule       schedule.Rule              `json:"schedule"`
+	LeaveRequestID *uint                      `json:"leave_request_id,omitempty"`
+	AbsenceID      *uint                      `json:"absence_id,omitempty"`
+	AutoClosed     bool                       `json:"auto_closed,omitempty"`
+	ReviewStatus   string                     `json:"review_status,omitempty"`
+	// Baris koreksi: jam sebelum / sesudah dan user yang meng-approve
+	CorrectionID *uint           `json:"correction_id,omitempty"`
+	OldValue     json.RawMessage `json:"old_value,omitempty"`
+	NewValue     json.RawMessage `json:"new_value,omitempty"`
+	ApprovedBy   *uint           `json:"approved_by,omitempty"`
+}
+
+func (ctrl *AttendanceController) GetAttendanceLogs(c *gin.Context) {
+	filter, ok := ctrl.logFilter(c)
+	if !ok {
+		return
+	}
+
+	// Ambil data
+	histories, err := ctrl.attendances.FindHistories(filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal Server Error"})
+		return
+	}
+	holidays, err := holidayCalendar(ctrl.holidays, filter.DateFrom, filter.DateTo)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal Server Error"})
+		return
+	}
+
+	// Bentuk response
+	var logs []AttendanceLogResp
+	for _, history := range histories {
+		logs = append(logs, toAttendanceLogResp(history, holidays))
+	}
+
+	// Cuti yang sudah di-approve tampil per hari kerja, bukan sebagai hari kosong
+	if includesType(filter.Types, models.AttendanceTypeLeave) {
+		leaves, err := ctrl.leaves.FindAll(repositories.LeaveFilter{
+			Statuses:     []string{models.LeaveStatusApproved},
+			DepartmentID: filter.DepartmentID,
+			DateFrom:     filter.DateFrom,
+			DateTo:       filter.DateTo,
+		})
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal Server Error"})
+			return
+		}
+		for _, leave := range leaves {
+			logs = append(logs, leaveLogs(leave, filter.DateFrom, filter.DateTo, holidays)...)
+		}
+	}
+
+	// Hari kerja tanpa attendance yang sudah dicatat job deteksi absen
+	if includesType(filter.Types, models.AttendanceTypeAbsent) {
+		absences, err := ctrl.attendances.FindAbsences(filter)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal Server Error"})
+			return
+		}
+		for _, absence := range absences {
+			logs = append(logs, absenceLog(absence))
+		}
+	}
+	sort.SliceStable(logs, func(i, j int) bool { return logs[i].DateAttendance < logs[j].DateAttendance })
+
+	c.JSON(http.StatusOK, gin.H{"data": logs})
 }
 
-func GetAttendanceLogs(c *gin.Context) {
+// logFilter membaca ?date=, ?department_id= dan ?type=, manager dibatasi ke department sendiri.
+// false kalau response error sudah dikirim.
+func (ctrl *AttendanceController) logFilter(c *gin.Context) (repositories.HistoryFilter, bool) {
 	dateParam := c.Query("date")
 	departmentParam := c.Query("department_id")
 
-	var histories []models.AttendanceHistory
-	db := config.DB.
-		Preload("Employee").
-		Preload("Employee.Department").
-		Preload("Attendance")
+	var filter repositories.HistoryFilter
 
-	// Filter tanggal (YYYY-MM-DD)
+	// Filter tanggal bisnis (YYYY-MM-DD), shift malam ikut tanggal mulai shift
 	if dateParam != "" {
-		if t, err := time.Parse("2006-01-02", dateParam); err == nil {
-			start := t
-			end := t.Add(24 * time.Hour)
-			db = db.Where("date_attendance >= ? AND date_attendance < ?", start, end)
+		if t, err := time.Parse(schedule.DateLayout, dateParam); err == nil {
+			filter.DateFrom = t.Format(schedule.DateLayout)
+			filter.DateTo = filter.DateFrom
 		}
 	}
 
 	// Filter department
 	if departmentParam != "" {
-		db = db.Joins("JOIN employees ON attendance_histories.employee_id = employees.employee_id").
-			Where("employees.department_id = ?", departmentParam)
+		departmentID, err := strconv.ParseUint(departmentParam, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid format for department_id"})
+			return filter, false
+		}
+		id := uint(departmentID)
+		filter.DepartmentID = &id
 	}
 
-	// Ambil data
-	if err := db.Find(&histories).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal Server Error"})
-		return
+	// Filter jenis event, bisa beberapa: ?type=clock_in,clock_out
+	if typeParam := c.Query("type"); typeParam != "" {
+		for _, name := range strings.Split(typeParam, ",") {
+			attendanceType, err := models.ParseAttendanceEventType(name)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{
+					"error":   err.Error(),
+					"code":    CodeInvalidAttendanceType,
+					"allowed": models.AttendanceEventTypes(),
+				})
+				return filter, false
+			}
+			filter.Types = append(filter.Types, attendanceType)
+		}
 	}
 
-	// Bentuk response
-	var logs []AttendanceLogResp
-	for _, history := range histories {
-		attendance := history.Attendance
-
-		clockIn := ""
-		clockOut := ""
-		if !attendance.ClockIn.IsZero() {
-			clockIn = attendance.ClockIn.Format("15:04:05")
+	// Manager hanya boleh melihat log department sendiri
+	if user := middlewares.CurrentUser(c); user != nil && user.Role == models.RoleManager {
+		manager, err := ctrl.employees.FindByEmployeeID(user.EmployeeID)
+		if user.EmployeeID == "" || err != nil {
+			middlewares.Forbid(c, middlewares.ReasonNoEmployeeLinked, "Your account is not linked to an employee")
+			return filter, false
 		}
-		if attendance.ClockOut != nil {
-			clockOut = attendance.ClockOut.Format("15:04:05")
+		if filter.DepartmentID != nil && *filter.DepartmentID != manager.DepartmentID {
+			middlewares.Forbid(c, middlewares.ReasonNotOwnDepartment, "You can only view attendance logs of your own department")
+			return filter, false
 		}
+		filter.DepartmentID = &manager.DepartmentID
+	}
+	return filter, true
+}
 
-		empName := history.Employee.Name
-		if empName == "" {
-			empName = history.EmployeeID
-		}
-		deptName := history.Employee.Department.DepartmentName
-		if deptName == "" {
-			deptName = "-"
-		}
+// includesType true kalau filter ?type= kosong atau memuat attendanceType
+func includesType(types []models.AttendanceEventType, attendanceType models.AttendanceEventType) bool {
+	return len(types) == 0 || slices.Contains(types, attendanceType)
+}
+
+// toAttendanceLogResp satu baris log dari history clock in / clock out
+func toAttendanceLogResp(history models.AttendanceHistory, holidays schedule.Calendar) AttendanceLogResp {
+	attendance := history.Attendance
+
+	clockIn := ""
+	clockOut := ""
+	if !attendance.ClockIn.IsZero() {
+		clockIn = attendance.ClockIn.Format("15:04:05")
+	}
+	if attendance.ClockOut != nil {
+		clockOut = attendance.ClockOut.Format("15:04:05")
+	}
+
+	empName := history.Employee.Name
+	if empName == "" {
+		empName = history.EmployeeID
+	}
+	deptName := history.Employee.Department.DepartmentName
+	if deptName == "" {
+		deptName = "-"
+	}
 
-		// Tentukan status absensi dengan switch
-		description := history.Description
+	// Status dihitung dan disimpan saat punch dicatat. Data lama yang belum
+	// di-recompute dihitung ulang di sini dengan aturan yang berlaku sekarang.
+	businessDate := schedule.BusinessDateOf(attendance)
+	rule, hasSnapshot := schedule.ParseSnapshot(history.RuleSnapshot)
+	if history.Status == "" || !hasSnapshot {
 		switch history.AttendanceType {
-		case 1: // Clock In
-			maxIn := history.Employee.Department.MaxClockInTime
-			if clockIn != "" && clockIn <= maxIn {
-				description = "On Time (Check-in)"
-			} else if clockIn != "" {
-				description = "Late (Check-in)"
-			}
-		case 2: // Clock Out
-			maxOut := history.Employee.Department.MaxClockOutTime
-			if clockOut != "" && clockOut >= maxOut {
-				description = "On Time (Check-out)"
-			} else if clockOut != "" {
-				description = "Early Leave"
-			}
+		case models.AttendanceTypeClockIn, models.AttendanceTypeClockOut:
+			schedule.Apply(&history, history.Employee, attendance, holidays)
+			rule, _ = schedule.ParseSnapshot(history.RuleSnapshot)
+		case models.AttendanceTypeCorrection, models.AttendanceTypeBreakStart, models.AttendanceTypeBreakEnd:
+			// Koreksi dan istirahat tidak punya status
 		default:
-			description = "Unknown Attendance Type"
+			history.Description = "Unknown Attendance Type"
+		}
+	}
 
+	resp := AttendanceLogResp{
+		ID:             history.ID,
+		EmployeeID:     history.EmployeeID,
+		AttendanceID:   history.AttendanceID,
+		Name:           empName,
+		DateAttendance: history.DateAttendance.Format("2006-01-02 15:04:05"),
+		BusinessDate:   businessDate.Format(schedule.DateLayout),
+		AttendanceType: history.AttendanceType,
+		Description:    history.Description,
+		Status:         history.Status,
+		MinutesLate:    history.MinutesLate,
+		MinutesEarly:   history.MinutesEarly,
+		Department:     deptName,
+		ClockIn:        clockIn,
+		ClockOut:       clockOut,
+		Schedule:       rule,
+		AutoClosed:     history.AutoClosed,
+		ReviewStatus:   history.ReviewStatus,
+	}
+	if history.AttendanceType == models.AttendanceTypeCorrection {
+		resp.CorrectionID = history.CorrectionID
+		resp.ApprovedBy = history.ReviewedBy
+		if json.Valid([]byte(history.OldValue)) {
+			resp.OldValue = json.RawMessage(history.OldValue)
 		}
+		if json.Valid([]byte(history.NewValue)) {
+			resp.NewValue = json.RawMessage(history.NewValue)
+		}
+	}
+	return resp
+}
 
+// leaveLogs satu baris log per hari kerja cuti, dibatasi rentang from - to (kosong = tanpa batas)
+func leaveLogs(leave models.LeaveRequest, from, to string, holidays schedule.Calendar) []AttendanceLogResp {
+	start, end := leave.StartDate, leave.EndDate
+	if from != "" && from > start {
+		start = from
+	}
+	if to != "" && to < end {
+		end = to
+	}
+	startDate, errStart := time.Parse(schedule.DateLayout, start)
+	endDate, errEnd := time.Parse(schedule.DateLayout, end)
+	if errStart != nil || errEnd != nil {
+		return nil
+	}
+
+	deptName := leave.Employee.Department.DepartmentName
+	if deptName == "" {
+		deptName = "-"
+	}
+	leaveID := leave.ID
+
+	var logs []AttendanceLogResp
+	for _, date := range schedule.WorkingDays(leave.Employee, startDate, endDate, holidays) {
 		logs = append(logs, AttendanceLogResp{
-			ID:             history.ID,
-			EmployeeID:     history.EmployeeID,
-			AttendanceID:   history.AttendanceID,
-			Name:           empName,
-			DateAttendance: history.DateAttendance.Format("2006-01-02 15:04:05"),
-			AttendanceType: history.AttendanceType,
-			Description:    description,
+			EmployeeID:     leave.EmployeeID,
+			Name:           leave.Employee.Name,
+			DateAttendance: date.Format("2006-01-02 15:04:05"),
+			BusinessDate:   date.Format(schedule.DateLayout),
+			AttendanceType: models.AttendanceTypeLeave,
+			Description:    fmt.Sprintf("On Leave (%s)", leave.LeaveType.Name),
+			Status:         schedule.StatusOnLeave,
 			Department:     deptName,
-			ClockIn:        clockIn,
-			ClockOut:       clockOut,
+			Schedule:       schedule.Resolve(leave.Employee, date, holidays),
+			LeaveRequestID: &leaveID,
 		})
 	}
+	return logs
+}
 
-	c.JSON(http.StatusOK, gin.H{"data": logs})
+// absenceLog baris log untuk hari kerja yang tidak masuk
+func absenceLog(absence models.AbsenceRecord) AttendanceLogResp {
+	empName := absence.Employee.Name
+	if empName == "" {
+		empName = absence.EmployeeID
+	}
+	deptName := absence.Employee.Department.DepartmentName
+	if deptName == "" {
+		deptName = "-"
+	}
+	rule, _ := schedule.ParseSnapshot(absence.RuleSnapshot)
+	absenceID := absence.ID
+
+	return AttendanceLogResp{
+		EmployeeID:     absence.EmployeeID,
+		Name:           empName,
+		DateAttendance: absence.BusinessDate + " 00:00:00",
+		BusinessDate:   absence.BusinessDate,
+		AttendanceType: models.AttendanceTypeAbsent,
+		Description:    "Absent",
+		Status:         schedule.StatusAbsent,
+		Department:     deptName,
+		Schedule:       rule,
+		AbsenceID:      &absenceID,
+	}
 }
 
 // CreateAttendance
-func CreateAttendance(c *gin.Context) {
+func (ctrl *AttendanceController) CreateAttendance(c *gin.Context) {
 	var input struct {
 		EmployeeID string `form:"employee_id" json:"employee_id"`
 		ClockIn    string `form:"clock_in" json:"clock_in"` // format: 2006-01-02 15:04:05
@@ -136,6 +381,11 @@ func CreateAttendance(c *gin.Context) {
 		return
 	}
 
+	// Employee boleh tidak mengisi employee_id, otomatis dirinya sendiri
+	if user := middlewares.CurrentUser(c); input.EmployeeID == "" && user != nil && !canPunchForOthers(user.Role) {
+		input.EmployeeID = user.EmployeeID
+	}
+
 	if input.EmployeeID == "" {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Employee is required"})
 		return
@@ -144,6 +394,9 @@ func CreateAttendance(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Clock In is required"})
 		return
 	}
+	if !allowedToPunch(c, input.EmployeeID) {
+		return
+	}
 
 	clockInTime, err := time.Parse("2006-01-02 15:04:05", input.ClockIn)
 	if err != nil {
@@ -151,38 +404,107 @@ func CreateAttendance(c *gin.Context) {
 		return
 	}
 
-	// Generate AttendanceID otomatis
-	var lastAttendance models.Attendance
-	if err := config.DB.Order("id desc").First(&lastAttendance).Error; err != nil && err.Error() != "record not found" {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get last attendance"})
+	// Employee harus ada dan masih aktif pada waktu clock in
+	employee, err := ctrl.employees.FindByEmployeeID(input.EmployeeID)
+	if errors.Is(err, repositories.ErrNotFound) {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error":       fmt.Sprintf("Employee %s not found", input.EmployeeID),
+			"code":        CodeEmployeeNotFound,
+			"employee_id": input.EmployeeID,
+		})
 		return
 	}
-	nextID := 1
-	if lastAttendance.ID > 0 {
-		nextID = int(lastAttendance.ID) + 1
+	if err != nil {
+		fmt.Println("DB error:", err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal Server Error"})
+		return
+	}
+	if !employee.IsActiveAt(clockInTime) {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error":         fmt.Sprintf("Employee %s is no longer active", input.EmployeeID),
+			"code":          CodeEmployeeInactive,
+			"employee_id":   input.EmployeeID,
+			"terminated_at": employee.TerminatedAt,
+		})
+		return
 	}
-	attendanceID := fmt.Sprintf("ATT-%03d", nextID)
+
+	// Tanggal bisnis = tanggal mulai shift (clock in lewat tengah malam di shift malam
+	// masuk ke hari sebelumnya)
+	businessDate := schedule.BusinessDate(*employee, clockInTime)
 
 	attendance := models.Attendance{
 		EmployeeID:   input.EmployeeID,
-		AttendanceID: attendanceID,
 		ClockIn:      clockInTime,
 		ClockOut:     nil,
+		BusinessDate: businessDate.Format(schedule.DateLayout),
 	}
-	if err := config.DB.Create(&attendance).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal Server Error"})
+
+	// Clock in di hari libur dicatat sebagai holiday work
+	holidays, err := holidayCalendar(ctrl.holidays, attendance.BusinessDate, attendance.BusinessDate)
+	if err != nil {
+		fmt.Println("DB error:", err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to record attendance"})
 		return
 	}
 
-	// Simpan riwayat absensi
-	history := models.AttendanceHistory{
-		EmployeeID:     input.EmployeeID,
-		AttendanceID:   attendanceID,
-		DateAttendance: clockInTime,
-		AttendanceType: 1,
-		Description:    "On Time (Check-in)",
+	// Counter, attendance dan history ditulis dalam satu transaksi
+	var open *models.Attendance
+	err = ctrl.transactor.Transaction(func(tx repositories.Repositories) error {
+		// Generate AttendanceID otomatis dari counter id_sequences
+		attendanceID, err := idgen.Next(tx.Sequences, idgen.Attendance, clockInTime, tx.Attendances.CodesWithPrefix)
+		if err != nil {
+			return err
+		}
+
+		// Maksimal satu attendance terbuka per employee. Dicek setelah counter terkunci
+		// supaya dua clock in paralel tidak lolos bersamaan.
+		found, err := tx.Attendances.FindOpenByEmployee(input.EmployeeID)
+		if err == nil {
+			open = found
+			return errAttendanceOpen
+		}
+		if !errors.Is(err, repositories.ErrNotFound) {
+			return err
+		}
+
+		attendance.AttendanceID = attendanceID
+		if err := tx.Attendances.Create(&attendance); err != nil {
+			return err
+		}
+
+		// Simpan riwayat absensi beserta status saat ini
+		history := models.AttendanceHistory{
+			EmployeeID:     input.EmployeeID,
+			AttendanceID:   attendanceID,
+			DateAttendance: clockInTime,
+			AttendanceType: models.AttendanceTypeClockIn,
+			Description:    "Check-in",
+		}
+		schedule.Apply(&history, *employee, attendance, holidays)
+		if err := tx.Attendances.CreateHistory(&history); err != nil {
+			return err
+		}
+
+		// Clock in susulan menghapus catatan absen di tanggal bisnis yang sama
+		return tx.Attendances.DeleteAbsences(input.EmployeeID, attendance.BusinessDate, attendance.BusinessDate)
+	})
+	if errors.Is(err, errAttendanceOpen) {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":         "Employee already has an open attendance, clock out first",
+			"code":          CodeAttendanceAlreadyOpen,
+			"attendance_id": open.AttendanceID,
+		})
+		return
+	}
+	if sequenceExhausted(c, err, idgen.Attendance, "ATTENDANCE_ID") {
+		return
+	}
+	if err != nil {
+		fmt.Println("DB error:", err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to record attendance"})
+		return
 	}
-	config.DB.Create(&history)
 
 	resp := AttendanceResp{
 		ID:           attendance.ID,
@@ -190,12 +512,13 @@ func CreateAttendance(c *gin.Context) {
 		AttendanceID: attendance.AttendanceID,
 		ClockIn:      attendance.ClockIn,
 		ClockOut:     attendance.ClockOut,
+		BusinessDate: attendance.BusinessDate,
 	}
 	c.JSON(http.StatusOK, gin.H{"data": resp})
 }
 
 // UpdateAttendance
-func UpdateAttendance(c *gin.Context) {
+func (ctrl *AttendanceController) UpdateAttendance(c *gin.Context) {
 	attendanceID := c.Param("id")
 
 	var input struct {
@@ -215,11 +538,14 @@ func UpdateAttendance(c *gin.Context) {
 	}
 
 	// Cari attendance berdasarkan attendance_id
-	var attendance models.Attendance
-	if err := config.DB.Where("attendance_id = ?", attendanceID).First(&attendance).Error; err != nil {
+	attendance, err := ctrl.attendances.FindByAttendanceID(attendanceID)
+	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Attendance not found"})
 		return
 	}
+	if !allowedToPunch(c, attendance.EmployeeID) {
+		return
+	}
 
 	// Validasi format datetime
 	clockOutTime, err := time.Parse("2006-01-02 15:04:05", input.ClockOut)
@@ -227,23 +553,89 @@ func UpdateAttendance(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid format for clock_out, expected YYYY-MM-DD HH:mm:ss"})
 		return
 	}
-	attendance.ClockOut = &clockOutTime
 
-	// Update DB
-	if err := config.DB.Save(&attendance).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal Server Error"})
+	// Clock out hanya sekali, harus setelah clock in dan setelah istirahat selesai. Dicek
+	// ulang di dalam transaksi (baris attendance dikunci) supaya dua clock out bersamaan
+	// tidak sama-sama lolos.
+	var breaks []schedule.Break
+	err = ctrl.transactor.Transaction(func(tx repositories.Repositories) error {
+		current, err := tx.Attendances.LockByAttendanceID(attendanceID)
+		if err != nil {
+			return err
+		}
+		*attendance = *current
+		if attendance.ClockOut != nil {
+			return errAttendanceClosed
+		}
+		if !clockOutTime.After(attendance.ClockIn) {
+			return errClockOutBeforeClockIn
+		}
+		histories, err := tx.Attendances.FindHistories(repositories.HistoryFilter{AttendanceID: attendance.AttendanceID, Types: breakTypes})
+		if err != nil {
+			return err
+		}
+		breaks = schedule.Breaks(histories)
+		if _, ok := schedule.OpenBreak(breaks); ok {
+			return errBreakNotEnded
+		}
+		if len(breaks) > 0 && !clockOutTime.After(*breaks[len(breaks)-1].End) {
+			return errBreakOrder
+		}
+		attendance.ClockOut = &clockOutTime
+
+		// Simpan riwayat clock out beserta status saat ini, lalu hitung lembur
+		history := models.AttendanceHistory{
+			EmployeeID:     attendance.EmployeeID,
+			AttendanceID:   attendance.AttendanceID,
+			DateAttendance: clockOutTime,
+			AttendanceType: models.AttendanceTypeClockOut,
+			Description:    "Check-out",
+		}
+		ctrl.evaluatePunch(&history, *attendance)
+		if err := tx.Attendances.Update(attendance); err != nil {
+			return err
+		}
+		if err := tx.Attendances.CreateHistory(&history); err != nil {
+			return err
+		}
+		return syncOvertime(tx, *attendance)
+	})
+	switch {
+	case errors.Is(err, errAttendanceClosed):
+		c.JSON(http.StatusConflict, gin.H{
+			"error":     "Attendance already clocked out",
+			"code":      CodeAttendanceAlreadyClosed,
+			"clock_out": attendance.ClockOut,
+		})
+		return
+	case errors.Is(err, errClockOutBeforeClockIn):
+		c.JSON(http.StatusConflict, gin.H{
+			"error":    "clock_out must be after clock_in",
+			"code":     CodeClockOutBeforeClockIn,
+			"clock_in": attendance.ClockIn,
+		})
+		return
+	case errors.Is(err, errBreakNotEnded):
+		open, _ := schedule.OpenBreak(breaks)
+		c.JSON(http.StatusConflict, gin.H{
+			"error":       "End the break before clocking out",
+			"code":        CodeBreakNotEnded,
+			"break_start": open.Start,
+		})
+		return
+	case errors.Is(err, errBreakOrder):
+		c.JSON(http.StatusConflict, gin.H{
+			"error":     "clock_out must be after the last break",
+			"code":      CodeBreakOutOfOrder,
+			"break_end": breaks[len(breaks)-1].End,
+		})
 		return
 	}
-
-	// Simpan riwayat clock out
-	history := models.AttendanceHistory{
-		EmployeeID:     attendance.EmployeeID,
-		AttendanceID:   attendance.AttendanceID,
-		DateAttendance: clockOutTime,
-		AttendanceType: 2,
-		Description:    "On Time (Check-out)",
+	if err != nil {
+		fmt.Println("DB error:", err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to record attendance"})
+		return
 	}
-	config.DB.Create(&history)
 
 	// Response sederhana
 	resp := AttendanceResp{
@@ -252,6 +644,408 @@ func UpdateAttendance(c *gin.Context) {
 		AttendanceID: attendance.AttendanceID,
 		ClockIn:      attendance.ClockIn,
 		ClockOut:     attendance.ClockOut,
+		BusinessDate: attendance.BusinessDate,
 	}
 	c.JSON(http.StatusOK, gin.H{"data": resp})
 }
+
+// evaluatePunch isi status history dengan aturan yang berlaku saat punch
+func (ctrl *AttendanceController) evaluatePunch(history *models.AttendanceHistory, attendance models.Attendance) {
+	employee, holidays, ok := ctrl.punchContext(attendance)
+	if !ok {
+		return
+	}
+	schedule.Apply(history, *employee, attendance, holidays)
+}
+
+// punchContext employee (termasuk yang sudah dihapus) dan hari libur di tanggal bisnis attendance
+func (ctrl *AttendanceController) punchContext(attendance models.Attendance) (*models.Employee, schedule.Calendar, bool) {
+	employee, err := ctrl.employees.WithDeleted().FindByEmployeeID(attendance.EmployeeID)
+	if err != nil {
+		return nil, nil, false
+	}
+	businessDate := schedule.BusinessDateOf(attendance).Format(schedule.DateLayout)
+	holidays, err := holidayCalendar(ctrl.holidays, businessDate, businessDate)
+	if err != nil {
+		fmt.Println("DB error:", err.Error())
+	}
+	return employee, holidays, true
+}
+
+// StartBreak mulai istirahat di attendance yang masih terbuka
+func (ctrl *AttendanceController) StartBreak(c *gin.Context) {
+	ctrl.recordBreak(c, models.AttendanceTypeBreakStart)
+}
+
+// EndBreak selesai istirahat
+func (ctrl *AttendanceController) EndBreak(c *gin.Context) {
+	ctrl.recordBreak(c, models.AttendanceTypeBreakEnd)
+}
+
+// recordBreak catat history mulai / selesai istirahat. Istirahat hanya boleh di dalam
+// attendance yang belum clock out, setelah clock in dan tidak beririsan satu sama lain.
+func (ctrl *AttendanceController) recordBreak(c *gin.Context, attendanceType models.AttendanceEventType) {
+	var input struct {
+		BreakStart string `form:"break_start" json:"break_start"` // format: 2006-01-02 15:04:05
+		BreakEnd   string `form:"break_end" json:"break_end"`     // format: 2006-01-02 15:04:05
+	}
+	if err := c.ShouldBind(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input format"})
+		return
+	}
+
+	field, value, description := "break_start", input.BreakStart, "Break Start"
+	if attendanceType == models.AttendanceTypeBreakEnd {
+		field, value, description = "break_end", input.BreakEnd, "Break End"
+	}
+	if value == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": description + " is required"})
+		return
+	}
+	punch, err := time.Parse("2006-01-02 15:04:05", value)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("invalid format for %s, expected YYYY-MM-DD HH:mm:ss", field)})
+		return
+	}
+
+	attendance, err := ctrl.attendances.FindByAttendanceID(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Attendance not found"})
+		return
+	}
+	if !allowedToPunch(c, attendance.EmployeeID) {
+		return
+	}
+
+	history := models.AttendanceHistory{
+		EmployeeID:     attendance.EmployeeID,
+		AttendanceID:   attendance.AttendanceID,
+		DateAttendance: punch,
+		AttendanceType: attendanceType,
+		Description:    description,
+	}
+	if employee, holidays, ok := ctrl.punchContext(*attendance); ok {
+		history.RuleSnapshot = schedule.Resolve(*employee, schedule.BusinessDateOf(*attendance), holidays).Snapshot()
+	}
+
+	// Urutan istirahat dicek ulang di dalam transaksi bersama insert history
+	var breaks []schedule.Break
+	err = ctrl.transactor.Transaction(func(tx repositories.Repositories) error {
+		current, err := tx.Attendances.LockByAttendanceID(attendance.AttendanceID)
+		if err != nil {
+			return err
+		}
+		*attendance = *current
+		if attendance.ClockOut != nil {
+			return errAttendanceClosed
+		}
+		histories, err := tx.Attendances.FindHistories(repositories.HistoryFilter{AttendanceID: attendance.AttendanceID, Types: breakTypes})
+		if err != nil {
+			return err
+		}
+		breaks = schedule.Breaks(histories)
+
+		open, onBreak := schedule.OpenBreak(breaks)
+		switch {
+		case attendanceType == models.AttendanceTypeBreakStart && onBreak:
+			return errBreakOpen
+		case attendanceType == models.AttendanceTypeBreakStart:
+			if punch.Before(attendance.ClockIn) || (len(breaks) > 0 && punch.Before(*breaks[len(breaks)-1].End)) {
+				return errBreakOrder
+			}
+		case !onBreak:
+			return errNoOpenBreak
+		case !punch.After(open.Start):
+			return errBreakOrder
+		}
+		return tx.Attendances.CreateHistory(&history)
+	})
+	switch {
+	case errors.Is(err, errAttendanceClosed):
+		c.JSON(http.StatusConflict, gin.H{
+			"error":     "Attendance already clocked out",
+			"code":      CodeAttendanceAlreadyClosed,
+			"clock_out": attendance.ClockOut,
+		})
+		return
+	case errors.Is(err, errBreakOpen):
+		open, _ := schedule.OpenBreak(breaks)
+		c.JSON(http.StatusConflict, gin.H{
+			"error":       "Break already started, end it first",
+			"code":        CodeBreakAlreadyOpen,
+			"break_start": open.Start,
+		})
+		return
+	case errors.Is(err, errNoOpenBreak):
+		c.JSON(http.StatusConflict, gin.H{"error": "No break to end, start a break first", "code": CodeNoOpenBreak})
+		return
+	case errors.Is(err, errBreakOrder):
+		message := "break_start must be after clock_in and the previous break"
+		if attendanceType == models.AttendanceTypeBreakEnd {
+			message = "break_end must be after break_start"
+		}
+		c.JSON(http.StatusConflict, gin.H{
+			"error":    message,
+			"code":     CodeBreakOutOfOrder,
+			"clock_in": attendance.ClockIn,
+			"breaks":   breaks,
+		})
+		return
+	case err != nil:
+		fmt.Println("DB error:", err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to record attendance"})
+		return
+	}
+
+	if attendanceType == models.AttendanceTypeBreakStart {
+		breaks = append(breaks, schedule.Break{Start: punch})
+	} else {
+		breaks[len(breaks)-1].End = &punch
+	}
+	_, onBreak := schedule.OpenBreak(breaks)
+	c.JSON(http.StatusOK, gin.H{"data": BreakResp{
+		AttendanceID: attendance.AttendanceID,
+		EmployeeID:   attendance.EmployeeID,
+		OnBreak:      onBreak,
+		Breaks:       breaks,
+	}})
+}
+
+// canPunchForOthers: admin dan HR boleh mencatat absensi employee lain
+func canPunchForOthers(role string) bool {
+	return role == models.RoleAdmin || role == models.RoleHR
+}
+
+// allowedToPunch mengirim 403 kalau manager/employee mencoba absen untuk orang lain
+func allowedToPunch(c *gin.Context, employeeID string) bool {
+	user := middlewares.CurrentUser(c)
+	if user == nil || canPunchForOthers(user.Role) {
+		return true
+	}
+	if user.EmployeeID == "" {
+		middlewares.Forbid(c, middlewares.ReasonNoEmployeeLinked, "Your account is not linked to an employee")
+		return false
+	}
+	if user.EmployeeID != employeeID {
+		middlewares.Forbid(c, middlewares.ReasonNotOwnAttendance, "You can only clock in and out for yourself")
+		return false
+	}
+	return true
+}
+
+// CodeReviewNotPending 409, clock out auto-close sudah di-confirm / dikoreksi
+const CodeReviewNotPending = "review_not_pending"
+
+var errReviewNotPending = errors.New("auto-close review is not pending")
+
+// GetAutoClosedReviews antrian clock out yang ditutup job auto-close,
+// ?status=pending (default) / confirmed / corrected, filter sama dengan log
+func (ctrl *AttendanceController) GetAutoClosedReviews(c *gin.Context) {
+	status := c.DefaultQuery("status", models.ReviewPending)
+	if status != models.ReviewPending && status != models.ReviewConfirmed && status != models.ReviewCorrected {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "status must be pending, confirmed or corrected"})
+		return
+	}
+	filter, ok := ctrl.logFilter(c)
+	if !ok {
+		return
+	}
+	filter.ReviewStatus = status
+
+	histories, err := ctrl.attendances.FindHistories(filter)
+	if err != nil {
+		fmt.Println("DB error:", err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal Server Error"})
+		return
+	}
+	holidays, err := holidayCalendar(ctrl.holidays, filter.DateFrom, filter.DateTo)
+	if err != nil {
+		fmt.Println("DB error:", err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal Server Error"})
+		return
+	}
+	logs := []AttendanceLogResp{}
+	for _, history := range histories {
+		logs = append(logs, toAttendanceLogResp(history, holidays))
+	}
+	c.JSON(http.StatusOK, gin.H{"data": logs})
+}
+
+// ConfirmAutoClosed HR menyatakan jam auto-close sudah benar, status dihitung ulang dari jam tersebut
+func (ctrl *AttendanceController) ConfirmAutoClosed(c *gin.Context) {
+	history, ok := ctrl.findPendingReview(c)
+	if !ok {
+		return
+	}
+	ctrl.resolveReview(c, history, models.ReviewConfirmed, nil)
+}
+
+// CorrectAutoClosed HR mengganti jam clock out hasil auto-close dengan jam sebenarnya
+func (ctrl *AttendanceController) CorrectAutoClosed(c *gin.Context) {
+	history, ok := ctrl.findPendingReview(c)
+	if !ok {
+		return
+	}
+
+	var input struct {
+		ClockOut string `form:"clock_out" json:"clock_out"` // format: 2006-01-02 15:04:05
+	}
+	if err := c.ShouldBind(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input format"})
+		return
+	}
+	if input.ClockOut == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Clock Out is required"})
+		return
+	}
+	clockOutTime, err := time.Parse("2006-01-02 15:04:05", input.ClockOut)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid format for clock_out, expected YYYY-MM-DD HH:mm:ss"})
+		return
+	}
+	ctrl.resolveReview(c, history, models.ReviewCorrected, &clockOutTime)
+}
+
+// correctAutoClosed pindahkan clock out auto-close ke clockOut. Sama seperti clock out biasa,
+// jam baru harus setelah istirahat terakhir selesai; istirahat yang ditutup job auto-close
+// ikut dimajukan kalau jam baru lebih awal (dikembalikan supaya ikut disimpan).
+func correctAutoClosed(tx repositories.Repositories, attendance *models.Attendance, history *models.AttendanceHistory, clockOut time.Time) (*models.AttendanceHistory, []schedule.Break, error) {
+	if !clockOut.After(attendance.ClockIn) {
+		return nil, nil, errClockOutBeforeClockIn
+	}
+	histories, err := tx.Attendances.FindHistories(repositories.HistoryFilter{AttendanceID: attendance.AttendanceID, Types: breakTypes})
+	if err != nil {
+		return nil, nil, err
+	}
+	breaks := schedule.Breaks(histories)
+	var autoBreakEnd *models.AttendanceHistory
+	if len(breaks) > 0 {
+		last := breaks[len(breaks)-1]
+		limit := last.End
+		for i := range histories {
+			end := &histories[i]
+			if end.AttendanceType == models.AttendanceTypeBreakEnd && end.AutoClosed && last.End != nil && end.DateAttendance.Equal(*last.End) {
+				autoBreakEnd = end
+			}
+		}
+		if autoBreakEnd != nil || limit == nil {
+			limit = &last.Start
+		}
+		if !clockOut.After(*limit) {
+			return nil, breaks, errBreakOrder
+		}
+	}
+	if autoBreakEnd == nil || !clockOut.Before(autoBreakEnd.DateAttendance) {
+		autoBreakEnd = nil
+	} else {
+		autoBreakEnd.DateAttendance = clockOut
+	}
+
+	attendance.ClockOut = &clockOut
+	history.DateAttendance = clockOut
+	return autoBreakEnd, breaks, nil
+}
+
+// resolveReview selesaikan review auto-close: attendance dan history-nya dikunci lalu review
+// dicek ulang masih pending (bisa saja sudah di-confirm / dikoreksi lewat pengajuan koreksi),
+// clock out dipindah ke clockOut kalau diisi, status dihitung ulang dan semuanya disimpan
+// dalam satu transaksi.
+func (ctrl *AttendanceController) resolveReview(c *gin.Context, history *models.AttendanceHistory, status string, clockOut *time.Time) {
+	var attendance models.Attendance
+	var breaks []schedule.Break
+	err := ctrl.transactor.Transaction(func(tx repositories.Repositories) error {
+		// Urutan kunci sama dengan applyCorrection: attendance dulu, lalu history
+		current, err := tx.Attendances.LockByAttendanceID(history.AttendanceID)
+		if err != nil {
+			return err
+		}
+		attendance = *current
+		locked, err := tx.Attendances.LockHistoryByID(history.ID)
+		if err != nil {
+			return err
+		}
+		*history = *locked
+		if history.ReviewStatus != models.ReviewPending {
+			return errReviewNotPending
+		}
+
+		var breakEnd *models.AttendanceHistory
+		if clockOut != nil {
+			if breakEnd, breaks, err = correctAutoClosed(tx, &attendance, history, *clockOut); err != nil {
+				return err
+			}
+		}
+		ctrl.evaluatePunch(history, attendance)
+		now := time.Now()
+		history.ReviewStatus = status
+		history.ReviewedAt = &now
+		if user := middlewares.CurrentUser(c); user != nil {
+			history.ReviewedBy = &user.UserID
+		}
+
+		if err := tx.Attendances.Update(&attendance); err != nil {
+			return err
+		}
+		if err := tx.Attendances.UpdateHistory(history); err != nil {
+			return err
+		}
+		if breakEnd != nil {
+			if err := tx.Attendances.UpdateHistory(breakEnd); err != nil {
+				return err
+			}
+		}
+		return syncOvertime(tx, attendance)
+	})
+	switch {
+	case errors.Is(err, errReviewNotPending):
+		c.JSON(http.StatusConflict, gin.H{
+			"error":         fmt.Sprintf("Auto-closed attendance is already %s", history.ReviewStatus),
+			"code":          CodeReviewNotPending,
+			"review_status": history.ReviewStatus,
+		})
+		return
+	case errors.Is(err, errClockOutBeforeClockIn):
+		c.JSON(http.StatusConflict, gin.H{
+			"error":    "clock_out must be after clock_in",
+			"code":     CodeClockOutBeforeClockIn,
+			"clock_in": attendance.ClockIn,
+		})
+		return
+	case errors.Is(err, errBreakOrder):
+		c.JSON(http.StatusConflict, gin.H{
+			"error":  "clock_out must be after the last break",
+			"code":   CodeBreakOutOfOrder,
+			"breaks": breaks,
+		})
+		return
+	case err != nil:
+		fmt.Println("DB error:", err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal Server Error"})
+		return
+	}
+	history.Attendance = attendance
+	c.JSON(http.StatusOK, gin.H{"data": toAttendanceLogResp(*history, nil)})
+}
+
+// findPendingReview history auto-close dari :id yang masih menunggu review
+func (ctrl *AttendanceController) findPendingReview(c *gin.Context) (*models.AttendanceHistory, bool) {
+	id, ok := parseID(c)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Auto-closed attendance not found"})
+		return nil, false
+	}
+	history, err := ctrl.attendances.FindHistoryByID(id)
+	if err != nil || !history.AutoClosed {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Auto-closed attendance not found"})
+		return nil, false
+	}
+	if history.ReviewStatus != models.ReviewPending {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":         fmt.Sprintf("Auto-closed attendance is already %s", history.ReviewStatus),
+			"code":          CodeReviewNotPending,
+			"review_status": history.ReviewStatus,
+		})
+		return nil, false
+	}
+	return history, true
+}
@@ -0,0 +1,318 @@
+package controllers_test
+
+import (
+	"fleetify-backend/jobs"
+	"fleetify-backend/models"
+	"fleetify-backend/repositories"
+	"fleetify-backend/schedule"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestCreateAttendance(t *testing.T) {
+	tests := []struct {
+		name       string
+		role       string
+		employeeID string // employee yang login
+		body       string
+		wantStatus int
+		wantCode   string
+	}{
+		{
+			name:       "employee clocks in for themself",
+			role:       models.RoleEmployee,
+			employeeID: "EMP-001",
+			body:       `{"clock_in":"2026-10-16 08:05:00"}`,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "hr clocks in for another employee",
+			role:       models.RoleHR,
+			body:       `{"employee_id":"EMP-002","clock_in":"2026-10-16 08:00:00"}`,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "employee clocks in for someone else",
+			role:       models.RoleEmployee,
+			employeeID: "EMP-001",
+			body:       `{"employee_id":"EMP-002","clock_in":"2026-10-16 08:00:00"}`,
+			wantStatus: http.StatusForbidden,
+			wantCode:   "not_own_attendance",
+		},
+		{
+			name:       "account without employee",
+			role:       models.RoleEmployee,
+			body:       `{"employee_id":"EMP-001","clock_in":"2026-10-16 08:00:00"}`,
+			wantStatus: http.StatusForbidden,
+			wantCode:   "no_employee_linked",
+		},
+		{
+			name:       "unknown employee",
+			role:       models.RoleHR,
+			body:       `{"employee_id":"EMP-999","clock_in":"2026-10-16 08:00:00"}`,
+			wantStatus: http.StatusUnprocessableEntity,
+			wantCode:   "employee_not_found",
+		},
+		{
+			name:       "invalid clock in",
+			role:       models.RoleHR,
+			body:       `{"employee_id":"EMP-001","clock_in":"16-10-2026 08:00"}`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "missing clock in",
+			role:       models.RoleHR,
+			body:       `{"employee_id":"EMP-001"}`,
+			wantStatus: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := newTestApp(t)
+			status, resp := app.do(t, token(t, tt.role, tt.employeeID), http.MethodPost, "/api/attendance", tt.body)
+			expect(t, status, resp, tt.wantStatus, tt.wantCode)
+		})
+	}
+}
+
+func TestCreateAttendanceAlreadyOpen(t *testing.T) {
+	app := newTestApp(t)
+	hr := token(t, models.RoleHR, "")
+
+	status, resp := app.do(t, hr, http.MethodPost, "/api/attendance", `{"employee_id":"EMP-001","clock_in":"2026-10-16 08:00:00"}`)
+	expect(t, status, resp, http.StatusOK, "")
+	if got := data(t, resp)["business_date"]; got != "2026-10-16" {
+		t.Fatalf("business_date = %v, want 2026-10-16", got)
+	}
+
+	status, resp = app.do(t, hr, http.MethodPost, "/api/attendance", `{"employee_id":"EMP-001","clock_in":"2026-10-16 09:00:00"}`)
+	expect(t, status, resp, http.StatusConflict, "attendance_already_open")
+	if resp["attendance_id"] != "ATT-000001" {
+		t.Fatalf("attendance_id = %v, want ATT-000001", resp["attendance_id"])
+	}
+}
+
+// breakRequest request mulai (POST) / selesai (PUT) istirahat
+type breakRequest struct {
+	method string
+	body   string
+}
+
+func breakStart(at string) breakRequest {
+	return breakRequest{method: http.MethodPost, body: `{"break_start":"` + at + `"}`}
+}
+
+func breakEnd(at string) breakRequest {
+	return breakRequest{method: http.MethodPut, body: `{"break_end":"` + at + `"}`}
+}
+
+func TestClockOut(t *testing.T) {
+	tests := []struct {
+		name       string
+		breaks     []breakRequest
+		clockOut   string
+		wantStatus int
+		wantCode   string
+	}{
+		{name: "after shift", clockOut: "2026-10-16 17:00:00", wantStatus: http.StatusOK},
+		{name: "before clock in", clockOut: "2026-10-16 07:00:00", wantStatus: http.StatusConflict, wantCode: "clock_out_before_clock_in"},
+		{name: "equal to clock in", clockOut: "2026-10-16 08:00:00", wantStatus: http.StatusConflict, wantCode: "clock_out_before_clock_in"},
+		{name: "invalid format", clockOut: "17:00", wantStatus: http.StatusBadRequest},
+		{
+			name:       "break still open",
+			breaks:     []breakRequest{breakStart("2026-10-16 12:00:00")},
+			clockOut:   "2026-10-16 17:00:00",
+			wantStatus: http.StatusConflict,
+			wantCode:   "break_not_ended",
+		},
+		{
+			name:       "before last break ended",
+			breaks:     []breakRequest{breakStart("2026-10-16 12:00:00"), breakEnd("2026-10-16 13:00:00")},
+			clockOut:   "2026-10-16 12:30:00",
+			wantStatus: http.StatusConflict,
+			wantCode:   "break_out_of_order",
+		},
+		{
+			name:       "after break",
+			breaks:     []breakRequest{breakStart("2026-10-16 12:00:00"), breakEnd("2026-10-16 13:00:00")},
+			clockOut:   "2026-10-16 17:00:00",
+			wantStatus: http.StatusOK,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := newTestApp(t)
+			employee := token(t, models.RoleEmployee, "EMP-001")
+			status, resp := app.do(t, employee, http.MethodPost, "/api/attendance", `{"clock_in":"2026-10-16 08:00:00"}`)
+			expect(t, status, resp, http.StatusOK, "")
+
+			for _, request := range tt.breaks {
+				status, resp := app.do(t, employee, request.method, "/api/attendance/ATT-000001/break", request.body)
+				if status != http.StatusOK {
+					t.Fatalf("%s break: status %d, body %v", request.method, status, resp)
+				}
+			}
+
+			status, resp = app.do(t, employee, http.MethodPut, "/api/attendance/ATT-000001", `{"clock_out":"`+tt.clockOut+`"}`)
+			expect(t, status, resp, tt.wantStatus, tt.wantCode)
+		})
+	}
+}
+
+func TestClockOutTwice(t *testing.T) {
+	app := newTestApp(t)
+	hr := token(t, models.RoleHR, "")
+	app.do(t, hr, http.MethodPost, "/api/attendance", `{"employee_id":"EMP-001","clock_in":"2026-10-16 08:00:00"}`)
+
+	status, resp := app.do(t, hr, http.MethodPut, "/api/attendance/ATT-000001", `{"clock_out":"2026-10-16 17:00:00"}`)
+	expect(t, status, resp, http.StatusOK, "")
+
+	status, resp = app.do(t, hr, http.MethodPut, "/api/attendance/ATT-000001", `{"clock_out":"2026-10-16 18:00:00"}`)
+	expect(t, status, resp, http.StatusConflict, "attendance_already_closed")
+
+	attendance, err := app.repos.Attendances.FindByAttendanceID("ATT-000001")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := attendance.ClockOut.Format("15:04:05"); got != "17:00:00" {
+		t.Fatalf("clock_out = %s, want the first clock out 17:00:00", got)
+	}
+}
+
+func TestClockOutPermission(t *testing.T) {
+	app := newTestApp(t)
+	app.do(t, token(t, models.RoleHR, ""), http.MethodPost, "/api/attendance", `{"employee_id":"EMP-001","clock_in":"2026-10-16 08:00:00"}`)
+
+	tests := []struct {
+		name       string
+		token      string
+		path       string
+		wantStatus int
+		wantCode   string
+	}{
+		{name: "other employee", token: token(t, models.RoleEmployee, "EMP-002"), path: "/api/attendance/ATT-000001", wantStatus: http.StatusForbidden, wantCode: "not_own_attendance"},
+		{name: "unknown attendance", token: token(t, models.RoleHR, ""), path: "/api/attendance/ATT-999999", wantStatus: http.StatusNotFound},
+		{name: "without token", path: "/api/attendance/ATT-000001", wantStatus: http.StatusUnauthorized},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, resp := app.do(t, tt.token, http.MethodPut, tt.path, `{"clock_out":"2026-10-16 17:00:00"}`)
+			expect(t, status, resp, tt.wantStatus, tt.wantCode)
+		})
+	}
+}
+
+func TestAttendanceLogsTypeFilter(t *testing.T) {
+	app := newTestApp(t)
+	hr := token(t, models.RoleHR, "")
+	app.do(t, hr, http.MethodPost, "/api/attendance", `{"employee_id":"EMP-001","clock_in":"2026-10-16 08:20:00"}`)
+	app.do(t, hr, http.MethodPut, "/api/attendance/ATT-000001", `{"clock_out":"2026-10-16 17:00:00"}`)
+
+	status, resp := app.do(t, hr, http.MethodGet, "/api/attendance/logs?type=clock_in", "")
+	expect(t, status, resp, http.StatusOK, "")
+	logs, ok := resp["data"].([]any)
+	if !ok || len(logs) != 1 {
+		t.Fatalf("logs = %v, want one clock in", resp["data"])
+	}
+	log := logs[0].(map[string]any)
+	if log["attendance_type"] != "clock_in" || log["status"] != "late" {
+		t.Fatalf("log = %v, want a late clock_in", log)
+	}
+
+	status, resp = app.do(t, hr, http.MethodGet, "/api/attendance/logs?type=coffee", "")
+	expect(t, status, resp, http.StatusBadRequest, "invalid_attendance_type")
+}
+
+// autoClosed attendance EMP-001 (clock in 08:00) dengan istirahat breaks yang ditutup
+// job auto-close di jam pulang shift 17:00, mengembalikan id history review-nya
+func autoClosed(t *testing.T, app *testApp, breaks []breakRequest) string {
+	t.Helper()
+	hr := token(t, models.RoleHR, "")
+	app.do(t, hr, http.MethodPost, "/api/attendance", `{"employee_id":"EMP-001","clock_in":"2026-10-16 08:00:00"}`)
+	for _, request := range breaks {
+		if status, resp := app.do(t, hr, request.method, "/api/attendance/ATT-000001/break", request.body); status != http.StatusOK {
+			t.Fatalf("%s break: status %d, body %v", request.method, status, resp)
+		}
+	}
+	if _, err := jobs.AutoCloseAttendances(app.repos, time.Date(2026, 10, 17, 8, 0, 0, 0, time.UTC), time.Hour, false); err != nil {
+		t.Fatal(err)
+	}
+
+	status, resp := app.do(t, hr, http.MethodGet, "/api/attendance/reviews?from=2026-10-16&to=2026-10-16", "")
+	expect(t, status, resp, http.StatusOK, "")
+	reviews, _ := resp["data"].([]any)
+	if len(reviews) != 1 {
+		t.Fatalf("reviews = %v, want one pending review", resp["data"])
+	}
+	return jsonID(reviews[0].(map[string]any)["id"])
+}
+
+func TestCorrectAutoClosed(t *testing.T) {
+	lunch := []breakRequest{breakStart("2026-10-16 12:00:00"), breakEnd("2026-10-16 13:00:00")}
+	forgotten := []breakRequest{breakStart("2026-10-16 16:00:00")} // ditutup auto-close jam 17:00
+
+	tests := []struct {
+		name         string
+		breaks       []breakRequest
+		clockOut     string
+		wantStatus   int
+		wantCode     string
+		wantBreakEnd string // jam selesai istirahat terakhir setelah koreksi
+	}{
+		{name: "after the last break", breaks: lunch, clockOut: "2026-10-16 16:00:00", wantStatus: http.StatusOK, wantBreakEnd: "13:00:00"},
+		{name: "during the last break", breaks: lunch, clockOut: "2026-10-16 12:30:00", wantStatus: http.StatusConflict, wantCode: "break_out_of_order"},
+		{name: "before clock in", clockOut: "2026-10-16 07:00:00", wantStatus: http.StatusConflict, wantCode: "clock_out_before_clock_in"},
+		{name: "auto-closed break moves with an earlier clock out", breaks: forgotten, clockOut: "2026-10-16 16:30:00", wantStatus: http.StatusOK, wantBreakEnd: "16:30:00"},
+		{name: "auto-closed break stays for a later clock out", breaks: forgotten, clockOut: "2026-10-16 18:00:00", wantStatus: http.StatusOK, wantBreakEnd: "17:00:00"},
+		{name: "before the auto-closed break started", breaks: forgotten, clockOut: "2026-10-16 15:30:00", wantStatus: http.StatusConflict, wantCode: "break_out_of_order"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := newTestApp(t)
+			id := autoClosed(t, app, tt.breaks)
+
+			status, resp := app.do(t, token(t, models.RoleHR, ""), http.MethodPut, "/api/attendance/review/"+id+"/correct", `{"clock_out":"`+tt.clockOut+`"}`)
+			expect(t, status, resp, tt.wantStatus, tt.wantCode)
+			if tt.wantBreakEnd == "" {
+				return
+			}
+			if got := data(t, resp)["review_status"]; got != models.ReviewCorrected {
+				t.Fatalf("review_status = %v, want corrected", got)
+			}
+			histories, err := app.repos.Attendances.FindHistories(repositories.HistoryFilter{
+				AttendanceID: "ATT-000001",
+				Types:        []models.AttendanceEventType{models.AttendanceTypeBreakStart, models.AttendanceTypeBreakEnd},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			breaks := schedule.Breaks(histories)
+			if got := breaks[len(breaks)-1].End.Format("15:04:05"); got != tt.wantBreakEnd {
+				t.Fatalf("last break ends at %s, want %s", got, tt.wantBreakEnd)
+			}
+		})
+	}
+}
+
+func TestResolveAutoClosedTwice(t *testing.T) {
+	app := newTestApp(t)
+	hr := token(t, models.RoleHR, "")
+	id := autoClosed(t, app, nil)
+
+	status, resp := app.do(t, hr, http.MethodPut, "/api/attendance/review/"+id+"/confirm", "")
+	expect(t, status, resp, http.StatusOK, "")
+
+	status, resp = app.do(t, hr, http.MethodPut, "/api/attendance/review/"+id+"/correct", `{"clock_out":"2026-10-16 16:00:00"}`)
+	expect(t, status, resp, http.StatusConflict, "review_not_pending")
+	status, resp = app.do(t, hr, http.MethodPut, "/api/attendance/review/"+id+"/confirm", "")
+	expect(t, status, resp, http.StatusConflict, "review_not_pending")
+
+	attendance, err := app.repos.Attendances.FindByAttendanceID("ATT-000001")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := attendance.ClockOut.Format("15:04:05"); got != "17:00:00" {
+		t.Fatalf("clock_out = %s, want the confirmed 17:00:00", got)
+	}
+}
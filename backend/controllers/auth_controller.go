@@ -0,0 +1,96 @@
+package controllers
+
+import (
+	"fleetify-backend/auth"
+	"fleetify-backend/repositories"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AuthController struct {
+	users repositories.UserRepository
+}
+
+func NewAuthController(users repositories.UserRepository) *AuthController {
+	return &AuthController{users: users}
+}
+
+// Login
+func (ctrl *AuthController) Login(c *gin.Context) {
+	var input struct {
+		Username string `form:"username" json:"username"`
+		Password string `form:"password" json:"password"`
+	}
+
+	if err := c.ShouldBind(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input format"})
+		return
+	}
+
+	// Custom validation
+	if input.Username == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Username is required"})
+		return
+	}
+	if input.Password == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Password is required"})
+		return
+	}
+
+	user, err := ctrl.users.FindByUsername(input.Username)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid username or password"})
+		return
+	}
+	if !auth.CheckPassword(user.PasswordHash, input.Password) {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid username or password"})
+		return
+	}
+
+	tokens, err := auth.GenerateTokenPair(*user)
+	if err != nil {
+		fmt.Println("JWT error:", err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal Server Error"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": tokens})
+}
+
+// RefreshToken
+func (ctrl *AuthController) RefreshToken(c *gin.Context) {
+	var input struct {
+		RefreshToken string `form:"refresh_token" json:"refresh_token"`
+	}
+
+	if err := c.ShouldBind(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input format"})
+		return
+	}
+	if input.RefreshToken == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Refresh Token is required"})
+		return
+	}
+
+	claims, err := auth.ParseToken(input.RefreshToken, auth.TokenTypeRefresh)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired refresh token"})
+		return
+	}
+
+	// Pastikan user masih ada
+	user, err := ctrl.users.FindByID(claims.UserID)
+	if err != nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired refresh token"})
+		return
+	}
+
+	tokens, err := auth.GenerateTokenPair(*user)
+	if err != nil {
+		fmt.Println("JWT error:", err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal Server Error"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": tokens})
+}
@@ -0,0 +1,126 @@
+package controllers_test
+
+import (
+	"encoding/json"
+	"fleetify-backend/auth"
+	"fleetify-backend/models"
+	"fleetify-backend/repositories"
+	"fleetify-backend/routes"
+	"fmt"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestMain(m *testing.M) {
+	os.Setenv("JWT_SECRET", "test-secret")
+	if err := auth.LoadConfig(); err != nil {
+		panic(err)
+	}
+	gin.SetMode(gin.TestMode)
+	os.Exit(m.Run())
+}
+
+// testApp router lengkap di atas repository memory.
+// Data awal: department 1 "IT" (08:00 - 17:00) dengan EMP-001 dan EMP-002,
+// department 2 "Ops" (07:00 - 16:00) dengan EMP-003.
+type testApp struct {
+	router *gin.Engine
+	repos  repositories.Repositories
+}
+
+func newTestApp(t *testing.T) *testApp {
+	t.Helper()
+	repos := repositories.NewMemoryRepositories()
+	departments := []models.Department{
+		{DepartmentName: "IT", MaxClockInTime: "08:00:00", MaxClockOutTime: "17:00:00", SlightlyLateMinutes: 15, LateMinutes: 60},
+		{DepartmentName: "Ops", MaxClockInTime: "07:00:00", MaxClockOutTime: "16:00:00", SlightlyLateMinutes: 15, LateMinutes: 60},
+	}
+	for i := range departments {
+		if err := repos.Departments.Create(&departments[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+	employees := []models.Employee{
+		{EmployeeID: "EMP-001", DepartmentID: 1, Name: "Andi"},
+		{EmployeeID: "EMP-002", DepartmentID: 1, Name: "Budi"},
+		{EmployeeID: "EMP-003", DepartmentID: 2, Name: "Citra"},
+	}
+	for i := range employees {
+		if err := repos.Employees.Create(&employees[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	router := gin.New()
+	routes.RegisterRoutes(router, repos)
+	return &testApp{router: router, repos: repos}
+}
+
+// token access token untuk role, employeeID boleh kosong (akun tanpa employee)
+func token(t *testing.T, role, employeeID string) string {
+	t.Helper()
+	user := models.User{ID: 1, Username: role, Role: role}
+	if employeeID != "" {
+		user.EmployeeID = &employeeID
+	}
+	pair, err := auth.GenerateTokenPair(user)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pair.AccessToken
+}
+
+// do mengirim request (body JSON kalau diawali "{", selain itu form) dan mengembalikan
+// status serta body response yang sudah di-decode
+func (a *testApp) do(t *testing.T, token, method, path, body string) (int, map[string]any) {
+	t.Helper()
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	if strings.HasPrefix(body, "{") {
+		req.Header.Set("Content-Type", "application/json")
+	} else {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	w := httptest.NewRecorder()
+	a.router.ServeHTTP(w, req)
+
+	var resp map[string]any
+	if w.Body.Len() > 0 {
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("%s %s: invalid JSON response %q", method, path, w.Body.String())
+		}
+	}
+	return w.Code, resp
+}
+
+// expect cek status dan (kalau tidak kosong) code error di response
+func expect(t *testing.T, status int, resp map[string]any, wantStatus int, wantCode string) {
+	t.Helper()
+	if status != wantStatus {
+		t.Fatalf("status = %d, want %d (body %v)", status, wantStatus, resp)
+	}
+	if wantCode != "" && resp["code"] != wantCode {
+		t.Fatalf("code = %v, want %s (body %v)", resp["code"], wantCode, resp)
+	}
+}
+
+// data isi field "data" di response
+func data(t *testing.T, resp map[string]any) map[string]any {
+	t.Helper()
+	d, ok := resp["data"].(map[string]any)
+	if !ok {
+		t.Fatalf("response has no data object: %v", resp)
+	}
+	return d
+}
+
+// jsonID id numerik dari response JSON sebagai string untuk path
+func jsonID(value any) string {
+	return fmt.Sprint(value)
+}
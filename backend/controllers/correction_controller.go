@@ -0,0 +1,544 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"fleetify-backend/middlewares"
+	"fleetify-backend/models"
+	"fleetify-backend/repositories"
+	"fleetify-backend/schedule"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CorrectionController struct {
+	corrections repositories.CorrectionRepository
+	attendances repositories.AttendanceRepository
+	employees   repositories.EmployeeRepository
+	transactor  repositories.Transactor
+}
+
+func NewCorrectionController(corrections repositories.CorrectionRepository, attendances repositories.AttendanceRepository, employees repositories.EmployeeRepository, transactor repositories.Transactor) *CorrectionController {
+	return &CorrectionController{corrections: corrections, attendances: attendances, employees: employees, transactor: transactor}
+}
+
+// Kode error pengajuan koreksi
+const (
+	CodeCorrectionPending    = "correction_pending"     // 409, attendance masih punya koreksi pending
+	CodeCorrectionNotPending = "correction_not_pending" // 409, approve / reject koreksi yang sudah diproses
+	CodeCorrectionNoChange   = "correction_no_change"   // 422, jam yang diajukan sama dengan jam sekarang
+	CodeAttendanceNotFound   = "attendance_not_found"   // 422
+)
+
+// Error sentinel untuk membatalkan transaksi koreksi
+var (
+	errCorrectionPending    = errors.New("attendance has a pending correction")
+	errCorrectionNotPending = errors.New("correction is not pending")
+	errClockOutOrder        = errors.New("clock_out must be after clock_in")
+)
+
+type CorrectionResp struct {
+	ID           uint       `json:"id"`
+	AttendanceID string     `json:"attendance_id"`
+	EmployeeID   string     `json:"employee_id"`
+	Name         string     `json:"name"`
+	DepartmentID uint       `json:"department_id"`
+	ClockIn      *time.Time `json:"clock_in"`
+	ClockOut     *time.Time `json:"clock_out"`
+	OldClockIn   time.Time  `json:"old_clock_in"`
+	OldClockOut  *time.Time `json:"old_clock_out"`
+	Reason       string     `json:"reason"`
+	Status       string     `json:"status"`
+	RequestedBy  *uint      `json:"requested_by"`
+	ReviewedBy   *uint      `json:"reviewed_by"`
+	ReviewedAt   *time.Time `json:"reviewed_at"`
+	ReviewNote   string     `json:"review_note"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+func toCorrectionResp(correction models.AttendanceCorrection) CorrectionResp {
+	return CorrectionResp{
+		ID:           correction.ID,
+		AttendanceID: correction.AttendanceID,
+		EmployeeID:   correction.EmployeeID,
+		Name:         correction.Employee.Name,
+		DepartmentID: correction.Employee.DepartmentID,
+		ClockIn:      correction.ClockIn,
+		ClockOut:     correction.ClockOut,
+		OldClockIn:   correction.OldClockIn,
+		OldClockOut:  correction.OldClockOut,
+		Reason:       correction.Reason,
+		Status:       correction.Status,
+		RequestedBy:  correction.RequestedBy,
+		ReviewedBy:   correction.ReviewedBy,
+		ReviewedAt:   correction.ReviewedAt,
+		ReviewNote:   correction.ReviewNote,
+		CreatedAt:    correction.CreatedAt,
+		UpdatedAt:    correction.UpdatedAt,
+	}
+}
+
+// correctedValues jam attendance setelah koreksi diterapkan, field koreksi yang kosong tetap
+func correctedValues(correction models.AttendanceCorrection, attendance models.Attendance) models.CorrectionValues {
+	values := models.CorrectionValues{ClockIn: attendance.ClockIn, ClockOut: attendance.ClockOut}
+	if correction.ClockIn != nil {
+		values.ClockIn = *correction.ClockIn
+	}
+	if correction.ClockOut != nil {
+		values.ClockOut = correction.ClockOut
+	}
+	return values
+}
+
+// GetCorrections daftar pengajuan koreksi. Employee hanya melihat miliknya sendiri,
+// manager hanya department sendiri.
+func (ctrl *CorrectionController) GetCorrections(c *gin.Context) {
+	filter := repositories.CorrectionFilter{
+		EmployeeID:   c.Query("employee_id"),
+		AttendanceID: c.Query("attendance_id"),
+	}
+	if status := c.Query("status"); status != "" {
+		filter.Statuses = []string{status}
+	}
+	if departmentParam := c.Query("department_id"); departmentParam != "" {
+		departmentID, err := strconv.ParseUint(departmentParam, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid format for department_id"})
+			return
+		}
+		id := uint(departmentID)
+		filter.DepartmentID = &id
+	}
+
+	if user := middlewares.CurrentUser(c); user != nil {
+		switch user.Role {
+		case models.RoleEmployee:
+			if user.EmployeeID == "" {
+				middlewares.Forbid(c, middlewares.ReasonNoEmployeeLinked, "Your account is not linked to an employee")
+				return
+			}
+			if filter.EmployeeID != "" && filter.EmployeeID != user.EmployeeID {
+				middlewares.Forbid(c, middlewares.ReasonNotOwnAttendance, "You can only view your own correction requests")
+				return
+			}
+			filter.EmployeeID = user.EmployeeID
+		case models.RoleManager:
+			manager, err := ctrl.employees.FindByEmployeeID(user.EmployeeID)
+			if user.EmployeeID == "" || err != nil {
+				middlewares.Forbid(c, middlewares.ReasonNoEmployeeLinked, "Your account is not linked to an employee")
+				return
+			}
+			if filter.DepartmentID != nil && *filter.DepartmentID != manager.DepartmentID {
+				middlewares.Forbid(c, middlewares.ReasonNotOwnDepartment, "You can only view correction requests of your own department")
+				return
+			}
+			filter.DepartmentID = &manager.DepartmentID
+		}
+	}
+
+	corrections, err := ctrl.corrections.FindAll(filter)
+	if err != nil {
+		fmt.Println("DB error:", err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal Server Error"})
+		return
+	}
+	resp := []CorrectionResp{}
+	for _, correction := range corrections {
+		resp = append(resp, toCorrectionResp(correction))
+	}
+	c.JSON(http.StatusOK, gin.H{"data": resp})
+}
+
+// GetCorrectionDetail
+func (ctrl *CorrectionController) GetCorrectionDetail(c *gin.Context) {
+	correction, ok := ctrl.findCorrection(c)
+	if !ok || !ctrl.allowedToView(c, correction.Employee) {
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": toCorrectionResp(*correction)})
+}
+
+// CreateCorrection mengajukan koreksi jam clock in / clock out. Employee / manager
+// hanya untuk attendance miliknya sendiri.
+func (ctrl *CorrectionController) CreateCorrection(c *gin.Context) {
+	var input struct {
+		AttendanceID string `form:"attendance_id" json:"attendance_id"`
+		ClockIn      string `form:"clock_in" json:"clock_in"`   // format: 2006-01-02 15:04:05, kosong = tidak diubah
+		ClockOut     string `form:"clock_out" json:"clock_out"` // format: 2006-01-02 15:04:05, kosong = tidak diubah
+		Reason       string `form:"reason" json:"reason"`
+	}
+	if err := c.ShouldBind(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input format"})
+		return
+	}
+
+	// Custom validation
+	if input.AttendanceID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Attendance is required"})
+		return
+	}
+	if input.ClockIn == "" && input.ClockOut == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Clock In or Clock Out is required"})
+		return
+	}
+	if input.Reason == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Reason is required"})
+		return
+	}
+
+	correction := models.AttendanceCorrection{
+		AttendanceID: input.AttendanceID,
+		Reason:       input.Reason,
+		Status:       models.CorrectionPending,
+	}
+	if input.ClockIn != "" {
+		clockIn, err := time.Parse("2006-01-02 15:04:05", input.ClockIn)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid format for clock_in, expected YYYY-MM-DD HH:mm:ss"})
+			return
+		}
+		correction.ClockIn = &clockIn
+	}
+	if input.ClockOut != "" {
+		clockOut, err := time.Parse("2006-01-02 15:04:05", input.ClockOut)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid format for clock_out, expected YYYY-MM-DD HH:mm:ss"})
+			return
+		}
+		correction.ClockOut = &clockOut
+	}
+
+	attendance, err := ctrl.attendances.FindByAttendanceID(input.AttendanceID)
+	if errors.Is(err, repositories.ErrNotFound) {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error":         fmt.Sprintf("Attendance %s not found", input.AttendanceID),
+			"code":          CodeAttendanceNotFound,
+			"attendance_id": input.AttendanceID,
+		})
+		return
+	}
+	if err != nil {
+		fmt.Println("DB error:", err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal Server Error"})
+		return
+	}
+	if !allowedToPunch(c, attendance.EmployeeID) {
+		return
+	}
+
+	correction.EmployeeID = attendance.EmployeeID
+	correction.OldClockIn = attendance.ClockIn
+	correction.OldClockOut = attendance.ClockOut
+	if user := middlewares.CurrentUser(c); user != nil {
+		correction.RequestedBy = &user.UserID
+	}
+
+	values := correctedValues(correction, *attendance)
+	if values.ClockOut != nil && !values.ClockOut.After(values.ClockIn) {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":    "clock_out must be after clock_in",
+			"code":     CodeClockOutBeforeClockIn,
+			"clock_in": values.ClockIn,
+		})
+		return
+	}
+	if values.ClockIn.Equal(attendance.ClockIn) && sameTime(values.ClockOut, attendance.ClockOut) {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error": "Requested times are the same as the current attendance",
+			"code":  CodeCorrectionNoChange,
+		})
+		return
+	}
+
+	// Maksimal satu koreksi pending per attendance, dicek di transaksi yang sama dengan insert.
+	// Baris attendance dikunci supaya dua pengajuan bersamaan tidak sama-sama lolos.
+	var pending models.AttendanceCorrection
+	err = ctrl.transactor.Transaction(func(tx repositories.Repositories) error {
+		if _, err := tx.Attendances.LockByAttendanceID(attendance.AttendanceID); err != nil {
+			return err
+		}
+		existing, err := tx.Corrections.FindAll(repositories.CorrectionFilter{
+			AttendanceID: attendance.AttendanceID,
+			Statuses:     []string{models.CorrectionPending},
+		})
+		if err != nil {
+			return err
+		}
+		if len(existing) > 0 {
+			pending = existing[0]
+			return errCorrectionPending
+		}
+		return tx.Corrections.Create(&correction)
+	})
+	if errors.Is(err, errCorrectionPending) {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":         "Attendance already has a pending correction request",
+			"code":          CodeCorrectionPending,
+			"correction_id": pending.ID,
+		})
+		return
+	}
+	if err != nil {
+		fmt.Println("DB error:", err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal Server Error"})
+		return
+	}
+
+	if created, err := ctrl.corrections.FindByID(correction.ID); err == nil {
+		c.JSON(http.StatusOK, gin.H{"data": toCorrectionResp(*created)})
+	} else {
+		c.JSON(http.StatusOK, gin.H{"data": correction})
+	}
+}
+
+// ApproveCorrection (manager department sendiri / HR) menerapkan jam baru ke attendance
+func (ctrl *CorrectionController) ApproveCorrection(c *gin.Context) {
+	ctrl.review(c, models.CorrectionApproved)
+}
+
+// RejectCorrection (manager department sendiri / HR)
+func (ctrl *CorrectionController) RejectCorrection(c *gin.Context) {
+	ctrl.review(c, models.CorrectionRejected)
+}
+
+// review approve / reject koreksi yang masih pending
+func (ctrl *CorrectionController) review(c *gin.Context, status string) {
+	correction, ok := ctrl.findCorrection(c)
+	if !ok || !allowedToReview(c, ctrl.employees, correction.Employee, middlewares.ReasonOwnCorrection) {
+		return
+	}
+
+	var input struct {
+		Note string `form:"note" json:"note"`
+	}
+	if err := c.ShouldBind(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input format"})
+		return
+	}
+
+	// Status dicek ulang di dalam transaksi (baris koreksi dikunci) supaya dua review
+	// bersamaan tidak sama-sama menerapkan jam dan saling menimpa
+	var attendance *models.Attendance
+	var breaks []schedule.Break
+	err := ctrl.transactor.Transaction(func(tx repositories.Repositories) error {
+		current, err := tx.Corrections.LockByID(correction.ID)
+		if err != nil {
+			return err
+		}
+		*correction = *current
+		if correction.Status != models.CorrectionPending {
+			return errCorrectionNotPending
+		}
+
+		now := time.Now()
+		correction.Status = status
+		correction.ReviewedAt = &now
+		correction.ReviewNote = input.Note
+		if user := middlewares.CurrentUser(c); user != nil {
+			correction.ReviewedBy = &user.UserID
+		}
+		if status == models.CorrectionApproved {
+			if attendance, breaks, err = applyCorrection(tx, *correction); err != nil {
+				return err
+			}
+		}
+		correction.Attendance = models.Attendance{}
+		return tx.Corrections.Update(correction)
+	})
+	if errors.Is(err, errCorrectionNotPending) {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":  fmt.Sprintf("Correction request is already %s", correction.Status),
+			"code":   CodeCorrectionNotPending,
+			"status": correction.Status,
+		})
+		return
+	}
+	if errors.Is(err, errClockOutOrder) {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":    "clock_out must be after clock_in",
+			"code":     CodeClockOutBeforeClockIn,
+			"clock_in": attendance.ClockIn,
+		})
+		return
+	}
+	if errors.Is(err, errBreakNotEnded) {
+		open, _ := schedule.OpenBreak(breaks)
+		c.JSON(http.StatusConflict, gin.H{
+			"error":       "clock_out cannot be set while a break is still open",
+			"code":        CodeBreakNotEnded,
+			"break_start": open.Start,
+		})
+		return
+	}
+	if errors.Is(err, errBreakOrder) {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":  "clock_in must be before the first break and clock_out after the last break",
+			"code":   CodeBreakOutOfOrder,
+			"breaks": breaks,
+		})
+		return
+	}
+	if err != nil {
+		fmt.Println("DB error:", err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal Server Error"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": toCorrectionResp(*correction)})
+}
+
+// applyCorrection ubah jam attendance, hitung ulang status history clock in / clock out
+// dan lemburnya, lalu catat baris koreksi (type 5) berisi jam lama, jam baru dan approver.
+// Attendance dibaca ulang (dikunci) karena bisa saja sudah clock out setelah koreksi diajukan.
+// Jam baru harus tetap mencakup semua istirahat yang tercatat, istirahat dikembalikan
+// untuk response errBreakOrder / errBreakNotEnded.
+func applyCorrection(tx repositories.Repositories, correction models.AttendanceCorrection) (*models.Attendance, []schedule.Break, error) {
+	attendance, err := tx.Attendances.LockByAttendanceID(correction.AttendanceID)
+	if err != nil {
+		return nil, nil, err
+	}
+	employee := correction.Employee
+
+	old := models.CorrectionValues{ClockIn: attendance.ClockIn, ClockOut: attendance.ClockOut}
+	values := correctedValues(correction, *attendance)
+	if values.ClockOut != nil && !values.ClockOut.After(values.ClockIn) {
+		return attendance, nil, errClockOutOrder
+	}
+	breakHistories, err := tx.Attendances.FindHistories(repositories.HistoryFilter{AttendanceID: attendance.AttendanceID, Types: breakTypes})
+	if err != nil {
+		return nil, nil, err
+	}
+	breaks := schedule.Breaks(breakHistories)
+	if len(breaks) > 0 {
+		if values.ClockIn.After(breaks[0].Start) {
+			return attendance, breaks, errBreakOrder
+		}
+		last := breaks[len(breaks)-1]
+		if values.ClockOut != nil && last.End == nil {
+			return attendance, breaks, errBreakNotEnded
+		}
+		if values.ClockOut != nil && !values.ClockOut.After(*last.End) {
+			return attendance, breaks, errBreakOrder
+		}
+	}
+
+	attendance.ClockIn = values.ClockIn
+	attendance.ClockOut = values.ClockOut
+	attendance.BusinessDate = schedule.BusinessDate(employee, values.ClockIn).Format(schedule.DateLayout)
+	if err := tx.Attendances.Update(attendance); err != nil {
+		return nil, nil, err
+	}
+	holidays, err := holidayCalendar(tx.Holidays, attendance.BusinessDate, attendance.BusinessDate)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	histories, err := tx.Attendances.FindHistories(repositories.HistoryFilter{AttendanceID: attendance.AttendanceID})
+	if err != nil {
+		return nil, nil, err
+	}
+	clockedOut := false
+	for _, history := range histories {
+		switch history.AttendanceType {
+		case models.AttendanceTypeClockIn:
+			history.DateAttendance = attendance.ClockIn
+		case models.AttendanceTypeClockOut:
+			if attendance.ClockOut == nil {
+				continue
+			}
+			clockedOut = true
+			history.DateAttendance = *attendance.ClockOut
+			// Koreksi yang mengganti jam auto-close sekaligus menyelesaikan review HR
+			if history.AutoClosed && history.ReviewStatus == models.ReviewPending {
+				history.ReviewStatus = models.ReviewCorrected
+				history.ReviewedBy = correction.ReviewedBy
+				history.ReviewedAt = correction.ReviewedAt
+			}
+		default:
+			continue
+		}
+		schedule.Apply(&history, employee, *attendance, holidays)
+		history.Employee = models.Employee{}
+		history.Attendance = models.Attendance{}
+		if err := tx.Attendances.UpdateHistory(&history); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	// Koreksi yang menambahkan jam clock out pada attendance yang masih terbuka
+	if attendance.ClockOut != nil && !clockedOut {
+		history := models.AttendanceHistory{
+			EmployeeID:     attendance.EmployeeID,
+			AttendanceID:   attendance.AttendanceID,
+			DateAttendance: *attendance.ClockOut,
+			AttendanceType: models.AttendanceTypeClockOut,
+		}
+		schedule.Apply(&history, employee, *attendance, holidays)
+		if err := tx.Attendances.CreateHistory(&history); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	oldValue, _ := json.Marshal(old)
+	newValue, _ := json.Marshal(values)
+	correctionID := correction.ID
+	history := models.AttendanceHistory{
+		EmployeeID:     attendance.EmployeeID,
+		AttendanceID:   attendance.AttendanceID,
+		DateAttendance: *correction.ReviewedAt,
+		AttendanceType: models.AttendanceTypeCorrection,
+		Description:    "Correction",
+		RuleSnapshot:   schedule.Resolve(employee, schedule.BusinessDateOf(*attendance), holidays).Snapshot(),
+		ReviewedBy:     correction.ReviewedBy,
+		ReviewedAt:     correction.ReviewedAt,
+		CorrectionID:   &correctionID,
+		OldValue:       string(oldValue),
+		NewValue:       string(newValue),
+	}
+	if err := tx.Attendances.CreateHistory(&history); err != nil {
+		return nil, nil, err
+	}
+
+	if err := syncOvertime(tx, *attendance); err != nil {
+		return nil, nil, err
+	}
+
+	// Attendance yang dipindah ke tanggal bisnis lain menggantikan catatan absen di tanggal itu
+	return attendance, breaks, tx.Attendances.DeleteAbsences(attendance.EmployeeID, attendance.BusinessDate, attendance.BusinessDate)
+}
+
+// sameTime true kalau kedua waktu nil atau sama
+func sameTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
+
+// findCorrection baca :id, kirim 404 kalau tidak ada
+func (ctrl *CorrectionController) findCorrection(c *gin.Context) (*models.AttendanceCorrection, bool) {
+	id, ok := parseID(c)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Correction request not found"})
+		return nil, false
+	}
+	correction, err := ctrl.corrections.FindByID(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Correction request not found"})
+		return nil, false
+	}
+	return correction, true
+}
+
+// allowedToView: pemilik koreksi, admin & HR, dan manager untuk department sendiri
+func (ctrl *CorrectionController) allowedToView(c *gin.Context, employee models.Employee) bool {
+	user := middlewares.CurrentUser(c)
+	if user == nil || user.Role != models.RoleManager || user.EmployeeID == employee.EmployeeID {
+		return allowedToPunch(c, employee.EmployeeID)
+	}
+	return managesDepartment(c, ctrl.employees, employee)
+}
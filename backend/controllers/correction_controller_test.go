@@ -0,0 +1,106 @@
+package controllers_test
+
+import (
+	"fleetify-backend/models"
+	"fleetify-backend/repositories"
+	"net/http"
+	"testing"
+)
+
+// requestCorrection EMP-001 clock in 08:00 - clock out 17:00, lalu mengajukan koreksi
+// dengan body, mengembalikan id koreksinya
+func requestCorrection(t *testing.T, app *testApp, body string) string {
+	t.Helper()
+	employee := token(t, models.RoleEmployee, "EMP-001")
+	app.do(t, employee, http.MethodPost, "/api/attendance", `{"clock_in":"2026-10-16 08:00:00"}`)
+	status, resp := app.do(t, employee, http.MethodPut, "/api/attendance/ATT-000001", `{"clock_out":"2026-10-16 17:00:00"}`)
+	expect(t, status, resp, http.StatusOK, "")
+
+	status, resp = app.do(t, employee, http.MethodPost, "/api/attendance/correction", body)
+	expect(t, status, resp, http.StatusOK, "")
+	return jsonID(data(t, resp)["id"])
+}
+
+func TestReviewCorrectionTwice(t *testing.T) {
+	app := newTestApp(t)
+	hr := token(t, models.RoleHR, "")
+	id := requestCorrection(t, app, `{"attendance_id":"ATT-000001","clock_out":"2026-10-16 18:00:00","reason":"lupa"}`)
+
+	status, resp := app.do(t, hr, http.MethodPut, "/api/attendance/correction/"+id+"/approve", "")
+	expect(t, status, resp, http.StatusOK, "")
+
+	for _, action := range []string{"approve", "reject"} {
+		status, resp = app.do(t, hr, http.MethodPut, "/api/attendance/correction/"+id+"/"+action, "")
+		expect(t, status, resp, http.StatusConflict, "correction_not_pending")
+		if resp["status"] != models.CorrectionApproved {
+			t.Fatalf("%s: status = %v, want approved", action, resp["status"])
+		}
+	}
+
+	// Hanya satu baris koreksi (type 5) yang tercatat
+	histories, err := app.repos.Attendances.FindHistories(repositories.HistoryFilter{
+		AttendanceID: "ATT-000001",
+		Types:        []models.AttendanceEventType{models.AttendanceTypeCorrection},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(histories) != 1 {
+		t.Fatalf("correction histories = %d, want 1", len(histories))
+	}
+}
+
+func TestApproveCorrectionBreaks(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+		wantCode   string
+	}{
+		{name: "clock out after the break", body: `{"attendance_id":"ATT-000001","clock_out":"2026-10-16 14:00:00","reason":"pulang cepat"}`, wantStatus: http.StatusOK},
+		{name: "clock out before the break ended", body: `{"attendance_id":"ATT-000001","clock_out":"2026-10-16 11:00:00","reason":"pulang cepat"}`, wantStatus: http.StatusConflict, wantCode: "break_out_of_order"},
+		{name: "clock out at the break end", body: `{"attendance_id":"ATT-000001","clock_out":"2026-10-16 13:00:00","reason":"pulang cepat"}`, wantStatus: http.StatusConflict, wantCode: "break_out_of_order"},
+		{name: "clock in after the break started", body: `{"attendance_id":"ATT-000001","clock_in":"2026-10-16 12:30:00","reason":"telat"}`, wantStatus: http.StatusConflict, wantCode: "break_out_of_order"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := newTestApp(t)
+			employee := token(t, models.RoleEmployee, "EMP-001")
+			app.do(t, employee, http.MethodPost, "/api/attendance", `{"clock_in":"2026-10-16 08:00:00"}`)
+			for _, request := range []breakRequest{breakStart("2026-10-16 12:00:00"), breakEnd("2026-10-16 13:00:00")} {
+				status, resp := app.do(t, employee, request.method, "/api/attendance/ATT-000001/break", request.body)
+				expect(t, status, resp, http.StatusOK, "")
+			}
+			status, resp := app.do(t, employee, http.MethodPut, "/api/attendance/ATT-000001", `{"clock_out":"2026-10-16 17:00:00"}`)
+			expect(t, status, resp, http.StatusOK, "")
+			status, resp = app.do(t, employee, http.MethodPost, "/api/attendance/correction", tt.body)
+			expect(t, status, resp, http.StatusOK, "")
+			id := jsonID(data(t, resp)["id"])
+
+			status, resp = app.do(t, token(t, models.RoleHR, ""), http.MethodPut, "/api/attendance/correction/"+id+"/approve", "")
+			expect(t, status, resp, tt.wantStatus, tt.wantCode)
+			if tt.wantStatus == http.StatusOK {
+				return
+			}
+			attendance, err := app.repos.Attendances.FindByAttendanceID("ATT-000001")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := attendance.ClockOut.Format("15:04:05"); got != "17:00:00" || attendance.ClockIn.Format("15:04:05") != "08:00:00" {
+				t.Fatalf("attendance = %s - %s, want unchanged 08:00:00 - 17:00:00", attendance.ClockIn.Format("15:04:05"), got)
+			}
+		})
+	}
+}
+
+func TestCreateCorrectionPending(t *testing.T) {
+	app := newTestApp(t)
+	id := requestCorrection(t, app, `{"attendance_id":"ATT-000001","clock_out":"2026-10-16 18:00:00","reason":"lupa"}`)
+
+	status, resp := app.do(t, token(t, models.RoleEmployee, "EMP-001"), http.MethodPost, "/api/attendance/correction",
+		`{"attendance_id":"ATT-000001","clock_out":"2026-10-16 19:00:00","reason":"lupa"}`)
+	expect(t, status, resp, http.StatusConflict, "correction_pending")
+	if got := jsonID(resp["correction_id"]); got != id {
+		t.Fatalf("correction_id = %s, want %s", got, id)
+	}
+}
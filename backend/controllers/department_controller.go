@@ -1,54 +1,89 @@
 package controllers
 
 import (
-	"fleetify-backend/config"
+	"errors"
 	"fleetify-backend/models"
+	"fleetify-backend/repositories"
+	"fleetify-backend/schedule"
+	"fmt"
 	"net/http"
 	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
+type DepartmentController struct {
+	departments repositories.DepartmentRepository
+	employees   repositories.EmployeeRepository
+	shifts      repositories.ShiftRepository
+	transactor  repositories.Transactor
+}
+
+func NewDepartmentController(departments repositories.DepartmentRepository, employees repositories.EmployeeRepository, shifts repositories.ShiftRepository, transactor repositories.Transactor) *DepartmentController {
+	return &DepartmentController{departments: departments, employees: employees, shifts: shifts, transactor: transactor}
+}
+
 // Input untuk form department
 type DepartmentFormInput struct {
 	DepartmentName     string `form:"department_name"`
 	MaxClockInTimeStr  string `form:"max_clock_in_time"`
 	MaxClockOutTimeStr string `form:"max_clock_out_time"`
+
+	// Opsional, kosong = default (create) atau nilai lama (update)
+	GracePeriodMinutes         *int `form:"grace_period_minutes"`
+	SlightlyLateMinutes        *int `form:"slightly_late_minutes"`
+	LateMinutes                *int `form:"late_minutes"`
+	EarlyLeaveToleranceMinutes *int `form:"early_leave_tolerance_minutes"`
+	OvertimeMinMinutes         *int `form:"overtime_min_minutes"`
+	OvertimeRoundingMinutes    *int `form:"overtime_rounding_minutes"`
+	MinBreakMinutes            *int `form:"min_break_minutes"`
 }
 
 // Response struct untuk department dan employee
 type EmployeeResp struct {
-	ID           uint      `json:"id"`
-	EmployeeID   string    `json:"employee_id"`
-	DepartmentID uint      `json:"department_id"`
-	Name         string    `json:"name"`
-	Address      string    `json:"address"`
-	CreatedAt    time.Time `json:"created_at"`
-	UpdatedAt    time.Time `json:"updated_at"`
+	ID           uint       `json:"id"`
+	EmployeeID   string     `json:"employee_id"`
+	DepartmentID uint       `json:"department_id"`
+	ShiftID      *uint      `json:"shift_id"`
+	Name         string     `json:"name"`
+	Address      string     `json:"address"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+	DeletedAt    *time.Time `json:"deleted_at"`
 }
 type DepartmentResp struct {
 	ID              uint           `json:"id"`
 	DepartmentName  string         `json:"department_name"`
 	MaxClockInTime  string         `json:"max_clock_in_time"`
 	MaxClockOutTime string         `json:"max_clock_out_time"`
+	ShiftID         *uint          `json:"shift_id"`
 	CreatedAt       time.Time      `json:"created_at"`
 	UpdatedAt       time.Time      `json:"updated_at"`
+	DeletedAt       *time.Time     `json:"deleted_at"`
 	Employees       []EmployeeResp `json:"employees"`
+	schedule.Policy
 }
 
 type DepartmentOnlyResp struct {
-	ID              uint      `json:"id"`
-	DepartmentName  string    `json:"department_name"`
-	MaxClockInTime  string    `json:"max_clock_in_time"`
-	MaxClockOutTime string    `json:"max_clock_out_time"`
-	CreatedAt       time.Time `json:"created_at"`
-	UpdatedAt       time.Time `json:"updated_at"`
+	ID              uint       `json:"id"`
+	DepartmentName  string     `json:"department_name"`
+	MaxClockInTime  string     `json:"max_clock_in_time"`
+	MaxClockOutTime string     `json:"max_clock_out_time"`
+	ShiftID         *uint      `json:"shift_id"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+	DeletedAt       *time.Time `json:"deleted_at"`
+	schedule.Policy
 }
 
-// GetAllDepartments
-func GetAllDepartments(c *gin.Context) {
-	var departments []models.Department
-	if err := config.DB.Preload("Employees").Find(&departments).Error; err != nil {
+// GetAllDepartments, ?include_deleted=true ikut menampilkan yang sudah dihapus
+func (ctrl *DepartmentController) GetAllDepartments(c *gin.Context) {
+	repo := ctrl.departments
+	if includeDeleted(c) {
+		repo = repo.WithDeleted()
+	}
+	departments, err := repo.FindAll()
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal Server Error"})
 		return
 	}
@@ -61,10 +96,12 @@ func GetAllDepartments(c *gin.Context) {
 				ID:           emp.ID,
 				EmployeeID:   emp.EmployeeID,
 				DepartmentID: emp.DepartmentID,
+				ShiftID:      emp.ShiftID,
 				Name:         emp.Name,
 				Address:      emp.Address,
 				CreatedAt:    emp.CreatedAt,
 				UpdatedAt:    emp.UpdatedAt,
+				DeletedAt:    deletedAt(emp.DeletedAt),
 			})
 		}
 		resp = append(resp, DepartmentResp{
@@ -72,8 +109,11 @@ func GetAllDepartments(c *gin.Context) {
 			DepartmentName:  dept.DepartmentName,
 			MaxClockInTime:  dept.MaxClockInTime,
 			MaxClockOutTime: dept.MaxClockOutTime,
+			ShiftID:         dept.ShiftID,
+			Policy:          schedule.PolicyOf(dept),
 			CreatedAt:       dept.CreatedAt,
 			UpdatedAt:       dept.UpdatedAt,
+			DeletedAt:       deletedAt(dept.DeletedAt),
 			Employees:       employees,
 		})
 	}
@@ -81,10 +121,14 @@ func GetAllDepartments(c *gin.Context) {
 }
 
 // GetDepartmentDetail
-func GetDepartmentDetail(c *gin.Context) {
-	id := c.Param("id")
-	var department models.Department
-	if err := config.DB.Preload("Employees").First(&department, id).Error; err != nil {
+func (ctrl *DepartmentController) GetDepartmentDetail(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Department not found"})
+		return
+	}
+	department, err := ctrl.departments.FindByID(id)
+	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Department not found"})
 		return
 	}
@@ -95,10 +139,12 @@ func GetDepartmentDetail(c *gin.Context) {
 			ID:           emp.ID,
 			EmployeeID:   emp.EmployeeID,
 			DepartmentID: emp.DepartmentID,
+			ShiftID:      emp.ShiftID,
 			Name:         emp.Name,
 			Address:      emp.Address,
 			CreatedAt:    emp.CreatedAt,
 			UpdatedAt:    emp.UpdatedAt,
+			DeletedAt:    deletedAt(emp.DeletedAt),
 		})
 	}
 
@@ -107,6 +153,8 @@ func GetDepartmentDetail(c *gin.Context) {
 		DepartmentName:  department.DepartmentName,
 		MaxClockInTime:  department.MaxClockInTime,
 		MaxClockOutTime: department.MaxClockOutTime,
+		ShiftID:         department.ShiftID,
+		Policy:          schedule.PolicyOf(*department),
 		CreatedAt:       department.CreatedAt,
 		UpdatedAt:       department.UpdatedAt,
 		Employees:       employees,
@@ -115,7 +163,7 @@ func GetDepartmentDetail(c *gin.Context) {
 }
 
 // CreateDepartment
-func CreateDepartment(c *gin.Context) {
+func (ctrl *DepartmentController) CreateDepartment(c *gin.Context) {
 	var input DepartmentFormInput
 	if err := c.ShouldBind(&input); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input format"})
@@ -150,11 +198,22 @@ func CreateDepartment(c *gin.Context) {
 	}
 
 	dept := models.Department{
-		DepartmentName:  input.DepartmentName,
-		MaxClockInTime:  clockIn.Format("15:04:05"),
-		MaxClockOutTime: clockOut.Format("15:04:05"),
+		DepartmentName:             input.DepartmentName,
+		MaxClockInTime:             clockIn.Format("15:04:05"),
+		MaxClockOutTime:            clockOut.Format("15:04:05"),
+		GracePeriodMinutes:         schedule.DefaultGracePeriodMinutes,
+		SlightlyLateMinutes:        schedule.DefaultSlightlyLateMinutes,
+		LateMinutes:                schedule.DefaultLateMinutes,
+		EarlyLeaveToleranceMinutes: schedule.DefaultEarlyLeaveToleranceMinutes,
+		OvertimeMinMinutes:         schedule.DefaultOvertimeMinMinutes,
+		OvertimeRoundingMinutes:    schedule.DefaultOvertimeRoundingMinutes,
+		MinBreakMinutes:            schedule.DefaultMinBreakMinutes,
+	}
+	if msg := applyLatenessPolicy(&dept, input); msg != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": msg})
+		return
 	}
-	if err := config.DB.Create(&dept).Error; err != nil {
+	if err := ctrl.departments.Create(&dept); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal Server Error"})
 		return
 	}
@@ -164,6 +223,8 @@ func CreateDepartment(c *gin.Context) {
 		DepartmentName:  dept.DepartmentName,
 		MaxClockInTime:  dept.MaxClockInTime,
 		MaxClockOutTime: dept.MaxClockOutTime,
+		ShiftID:         dept.ShiftID,
+		Policy:          schedule.PolicyOf(dept),
 		CreatedAt:       dept.CreatedAt,
 		UpdatedAt:       dept.UpdatedAt,
 		Employees:       []EmployeeResp{},
@@ -172,10 +233,14 @@ func CreateDepartment(c *gin.Context) {
 }
 
 // Update
-func UpdateDepartment(c *gin.Context) {
-	id := c.Param("id")
-	var department models.Department
-	if err := config.DB.First(&department, id).Error; err != nil {
+func (ctrl *DepartmentController) UpdateDepartment(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Department not found"})
+		return
+	}
+	department, err := ctrl.departments.FindByID(id)
+	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Department not found"})
 		return
 	}
@@ -217,8 +282,12 @@ func UpdateDepartment(c *gin.Context) {
 	department.DepartmentName = input.DepartmentName
 	department.MaxClockInTime = clockIn.Format("15:04:05")
 	department.MaxClockOutTime = clockOut.Format("15:04:05")
+	if msg := applyLatenessPolicy(department, input); msg != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": msg})
+		return
+	}
 
-	if err := config.DB.Save(&department).Error; err != nil {
+	if err := ctrl.departments.Update(department); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
 		return
 	}
@@ -228,6 +297,8 @@ func UpdateDepartment(c *gin.Context) {
 		DepartmentName:  department.DepartmentName,
 		MaxClockInTime:  department.MaxClockInTime,
 		MaxClockOutTime: department.MaxClockOutTime,
+		ShiftID:         department.ShiftID,
+		Policy:          schedule.PolicyOf(*department),
 		CreatedAt:       department.CreatedAt,
 		UpdatedAt:       department.UpdatedAt,
 	}
@@ -235,30 +306,365 @@ func UpdateDepartment(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"data": resp})
 }
 
-// Delete
-func DeleteDepartment(c *gin.Context) {
-	id := c.Param("id")
-	var department models.Department
-	if err := config.DB.First(&department, id).Error; err != nil {
+// applyLatenessPolicy isi toleransi yang dikirim lalu validasi urutannya, termasuk aturan lembur
+func applyLatenessPolicy(department *models.Department, input DepartmentFormInput) string {
+	if input.GracePeriodMinutes != nil {
+		department.GracePeriodMinutes = *input.GracePeriodMinutes
+	}
+	if input.SlightlyLateMinutes != nil {
+		department.SlightlyLateMinutes = *input.SlightlyLateMinutes
+	}
+	if input.LateMinutes != nil {
+		department.LateMinutes = *input.LateMinutes
+	}
+	if input.EarlyLeaveToleranceMinutes != nil {
+		department.EarlyLeaveToleranceMinutes = *input.EarlyLeaveToleranceMinutes
+	}
+
+	if department.GracePeriodMinutes < 0 || department.SlightlyLateMinutes < 0 ||
+		department.LateMinutes < 0 || department.EarlyLeaveToleranceMinutes < 0 {
+		return "lateness minutes must not be negative"
+	}
+	if department.GracePeriodMinutes > department.SlightlyLateMinutes {
+		return "grace_period_minutes must not exceed slightly_late_minutes"
+	}
+	if department.SlightlyLateMinutes > department.LateMinutes {
+		return "slightly_late_minutes must not exceed late_minutes"
+	}
+	return applyOvertimePolicy(department, input)
+}
+
+// applyOvertimePolicy isi aturan lembur dan minimal istirahat yang dikirim
+func applyOvertimePolicy(department *models.Department, input DepartmentFormInput) string {
+	if input.OvertimeMinMinutes != nil {
+		department.OvertimeMinMinutes = *input.OvertimeMinMinutes
+	}
+	if input.OvertimeRoundingMinutes != nil {
+		department.OvertimeRoundingMinutes = *input.OvertimeRoundingMinutes
+	}
+	if input.MinBreakMinutes != nil {
+		department.MinBreakMinutes = *input.MinBreakMinutes
+	}
+	if department.OvertimeMinMinutes < 0 || department.OvertimeRoundingMinutes < 0 {
+		return "overtime minutes must not be negative"
+	}
+	if department.MinBreakMinutes < 0 {
+		return "min_break_minutes must not be negative"
+	}
+	return ""
+}
+
+// AssignShift mengatur shift default untuk semua employee di department
+func (ctrl *DepartmentController) AssignShift(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Department not found"})
+		return
+	}
+	department, err := ctrl.departments.FindByID(id)
+	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Department not found"})
 		return
 	}
 
-	// Hapus semua employee dan attendance terkait
-	var employees []models.Employee
-	if err := config.DB.Where("department_id = ?", department.ID).Find(&employees).Error; err == nil {
-		for _, emp := range employees {
-			config.DB.Where("employee_id = ?", emp.EmployeeID).Delete(&models.AttendanceHistory{})
-			config.DB.Where("employee_id = ?", emp.EmployeeID).Delete(&models.Attendance{})
-			config.DB.Delete(&emp)
+	var input ShiftAssignInput
+	if err := c.ShouldBind(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input format"})
+		return
+	}
+	shiftID, err := lookupShift(ctrl.shifts, input.ShiftID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Shift not found"})
+		return
+	}
+
+	department.ShiftID = shiftID
+	if err := ctrl.departments.Update(department); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+		return
+	}
+
+	resp := DepartmentOnlyResp{
+		ID:              department.ID,
+		DepartmentName:  department.DepartmentName,
+		MaxClockInTime:  department.MaxClockInTime,
+		MaxClockOutTime: department.MaxClockOutTime,
+		ShiftID:         department.ShiftID,
+		Policy:          schedule.PolicyOf(*department),
+		CreatedAt:       department.CreatedAt,
+		UpdatedAt:       department.UpdatedAt,
+	}
+	c.JSON(http.StatusOK, gin.H{"data": resp})
+}
+
+// Kode error delete department
+const CodeDepartmentNotEmpty = "department_not_empty"
+
+// Aksi delete department terhadap employee di dalamnya
+const (
+	DeleteActionNone     = "none"     // department kosong
+	DeleteActionReassign = "reassign" // employee dipindah ke reassign_to
+	DeleteActionCascade  = "cascade"  // employee ikut dihapus
+)
+
+// Input delete department, bisa lewat query string atau form
+type DepartmentDeleteInput struct {
+	ReassignTo uint `form:"reassign_to"`
+	Cascade    bool `form:"cascade"`
+	DryRun     bool `form:"dry_run"`
+}
+
+// DepartmentDeleteImpact jumlah employee yang terdampak delete department
+type DepartmentDeleteImpact struct {
+	Employees           int `json:"employees"`
+	ActiveEmployees     int `json:"active_employees"`
+	TerminatedEmployees int `json:"terminated_employees"`
+}
+
+func departmentDeleteImpact(employees []models.Employee, now time.Time) DepartmentDeleteImpact {
+	impact := DepartmentDeleteImpact{Employees: len(employees)}
+	for _, emp := range employees {
+		if emp.IsActiveAt(now) {
+			impact.ActiveEmployees++
+		} else {
+			impact.TerminatedEmployees++
+		}
+	}
+	return impact
+}
+
+// Error sentinel untuk membatalkan transaksi delete department
+var (
+	errDepartmentNotEmpty     = errors.New("department is not empty")
+	errReassignTargetNotFound = errors.New("reassign target department not found")
+)
+
+// departmentDeleteAction aksi untuk department dengan sejumlah employee, false kalau
+// masih ada employee tapi reassign_to / cascade tidak diisi
+func departmentDeleteAction(employees int, input DepartmentDeleteInput) (string, bool) {
+	switch {
+	case employees == 0:
+		return DeleteActionNone, true
+	case input.ReassignTo != 0:
+		return DeleteActionReassign, true
+	case input.Cascade:
+		return DeleteActionCascade, true
+	}
+	return "", false
+}
+
+func departmentNotEmpty(c *gin.Context, impact DepartmentDeleteImpact) {
+	c.JSON(http.StatusConflict, gin.H{
+		"error":    "Department still has employees, use reassign_to or cascade=true",
+		"code":     CodeDepartmentNotEmpty,
+		"affected": impact,
+	})
+}
+
+// lockDepartments mengunci department yang dihapus dan tujuan reassign (kalau ada)
+// urut id, supaya dua delete dengan reassign bersilangan tidak saling menunggu
+func lockDepartments(tx repositories.Repositories, departmentID, reassignTo uint) error {
+	ids := []uint{departmentID}
+	if reassignTo != 0 {
+		ids = append(ids, reassignTo)
+		if reassignTo < departmentID {
+			ids[0], ids[1] = reassignTo, departmentID
+		}
+	}
+	for _, id := range ids {
+		err := tx.Departments.LockByID(id)
+		if errors.Is(err, repositories.ErrNotFound) && id == reassignTo {
+			return errReassignTargetNotFound
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Delete (soft delete). Ditolak 409 selama masih ada employee, kecuali employee
+// dipindah ke reassign_to atau ikut dihapus dengan cascade=true. dry_run=true
+// hanya mengembalikan dampaknya tanpa mengubah data.
+func (ctrl *DepartmentController) DeleteDepartment(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Department not found"})
+		return
+	}
+	department, err := ctrl.departments.FindByID(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Department not found"})
+		return
+	}
+
+	var input DepartmentDeleteInput
+	if err := c.ShouldBind(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input format"})
+		return
+	}
+	if input.ReassignTo != 0 && input.Cascade {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "reassign_to and cascade cannot be used together"})
+		return
+	}
+	if input.ReassignTo == department.ID {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "reassign_to must be a different department"})
+		return
+	}
+	if input.ReassignTo != 0 {
+		if _, err := ctrl.departments.FindByID(input.ReassignTo); err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Reassign target department not found"})
+			return
 		}
 	}
 
-	// Hapus department
-	if err := config.DB.Delete(&department).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	employees, err := ctrl.employees.FindByDepartment(department.ID)
+	if err != nil {
+		fmt.Println("DB error:", err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal Server Error"})
+		return
+	}
+	impact := departmentDeleteImpact(employees, time.Now())
+	action, ok := departmentDeleteAction(len(employees), input)
+	if !ok {
+		departmentNotEmpty(c, impact)
+		return
+	}
+
+	if input.DryRun {
+		c.JSON(http.StatusOK, gin.H{"dry_run": true, "action": action, "affected": impact})
+		return
+	}
+
+	// Department dikunci lalu employee dihitung ulang, create / pindah / restore employee
+	// mengunci baris yang sama jadi tidak ada employee yang masuk di sela-selanya.
+	// Attendance tidak ikut dihapus.
+	err = ctrl.transactor.Transaction(func(tx repositories.Repositories) error {
+		if err := lockDepartments(tx, department.ID, input.ReassignTo); err != nil {
+			return err
+		}
+		employees, err := tx.Employees.FindByDepartment(department.ID)
+		if err != nil {
+			return err
+		}
+		impact = departmentDeleteImpact(employees, time.Now())
+		if action, ok = departmentDeleteAction(len(employees), input); !ok {
+			return errDepartmentNotEmpty
+		}
+
+		if action == DeleteActionReassign {
+			if _, err := tx.Employees.MoveDepartment(department.ID, input.ReassignTo); err != nil {
+				return err
+			}
+		}
+		if err := tx.Departments.Delete(department); err != nil {
+			return err
+		}
+		// Employee ikut dihapus dengan deleted_at yang sama supaya bisa di-restore bersama
+		return tx.Employees.DeleteByDepartment(department.ID, department.DeletedAt.Time)
+	})
+	switch {
+	case errors.Is(err, errDepartmentNotEmpty):
+		departmentNotEmpty(c, impact)
+		return
+	case errors.Is(err, errReassignTargetNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Reassign target department not found"})
+		return
+	case errors.Is(err, repositories.ErrNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Department not found"})
+		return
+	case err != nil:
+		fmt.Println("DB error:", err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete department"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Department deleted successfully", "action": action, "affected": impact})
+}
+
+// RestoreDepartment mengembalikan department beserta employee yang terhapus bersamanya
+func (ctrl *DepartmentController) RestoreDepartment(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Department not found"})
+		return
+	}
+	department, err := ctrl.departments.WithDeleted().FindByID(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Department not found"})
+		return
+	}
+	if !department.DeletedAt.Valid {
+		c.JSON(http.StatusConflict, gin.H{"error": "Department is not deleted", "code": CodeNotDeleted})
+		return
+	}
+
+	var restored int64
+	deletedTime := department.DeletedAt.Time
+	err = ctrl.transactor.Transaction(func(tx repositories.Repositories) error {
+		if err := tx.Departments.Restore(department); err != nil {
+			return err
+		}
+		restored, err = tx.Employees.RestoreByDepartment(department.ID, deletedTime)
+		return err
+	})
+	if err != nil {
+		fmt.Println("DB error:", err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to restore department"})
+		return
+	}
+
+	resp := DepartmentOnlyResp{
+		ID:              department.ID,
+		DepartmentName:  department.DepartmentName,
+		MaxClockInTime:  department.MaxClockInTime,
+		MaxClockOutTime: department.MaxClockOutTime,
+		ShiftID:         department.ShiftID,
+		Policy:          schedule.PolicyOf(*department),
+		CreatedAt:       department.CreatedAt,
+		UpdatedAt:       department.UpdatedAt,
+	}
+	c.JSON(http.StatusOK, gin.H{"data": resp, "restored_employees": restored})
+}
+
+// PurgeDepartment hapus permanen department yang sudah di-soft delete,
+// termasuk semua employee dan attendance-nya
+func (ctrl *DepartmentController) PurgeDepartment(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Department not found"})
+		return
+	}
+	department, err := ctrl.departments.WithDeleted().FindByID(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Department not found"})
+		return
+	}
+	if !department.DeletedAt.Valid {
+		c.JSON(http.StatusConflict, gin.H{"error": "Department must be deleted before it can be purged", "code": CodeNotDeleted})
+		return
+	}
+
+	err = ctrl.transactor.Transaction(func(tx repositories.Repositories) error {
+		employees, err := tx.Employees.WithDeleted().FindByDepartment(department.ID)
+		if err != nil {
+			return err
+		}
+		for _, emp := range employees {
+			if err := tx.Attendances.DeleteByEmployee(emp.EmployeeID); err != nil {
+				return err
+			}
+			if err := tx.Employees.Purge(&emp); err != nil {
+				return err
+			}
+		}
+		return tx.Departments.Purge(department)
+	})
+	if err != nil {
+		fmt.Println("DB error:", err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to purge department"})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Department deleted successfully"})
+	c.JSON(http.StatusOK, gin.H{"message": "Department purged successfully"})
 }
@@ -0,0 +1,71 @@
+package controllers_test
+
+import (
+	"fleetify-backend/models"
+	"net/http"
+	"testing"
+)
+
+func TestDeleteDepartment(t *testing.T) {
+	tests := []struct {
+		name       string
+		query      string
+		wantStatus int
+		wantCode   string
+		wantAction string
+	}{
+		{name: "still has employees", wantStatus: http.StatusConflict, wantCode: "department_not_empty"},
+		{name: "reassign", query: "?reassign_to=2", wantStatus: http.StatusOK, wantAction: "reassign"},
+		{name: "reassign to deleted department", query: "?reassign_to=3", wantStatus: http.StatusNotFound},
+		{name: "cascade", query: "?cascade=true", wantStatus: http.StatusOK, wantAction: "cascade"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := newTestApp(t)
+			closed := models.Department{DepartmentName: "Closed", MaxClockInTime: "08:00:00", MaxClockOutTime: "17:00:00"}
+			if err := app.repos.Departments.Create(&closed); err != nil {
+				t.Fatal(err)
+			}
+			if err := app.repos.Departments.Delete(&closed); err != nil {
+				t.Fatal(err)
+			}
+
+			status, resp := app.do(t, token(t, models.RoleAdmin, ""), http.MethodDelete, "/api/departement/1"+tt.query, "")
+			expect(t, status, resp, tt.wantStatus, tt.wantCode)
+			if resp["action"] != nil && resp["action"] != tt.wantAction {
+				t.Fatalf("action = %v, want %s", resp["action"], tt.wantAction)
+			}
+
+			_, err := app.repos.Departments.FindByID(1)
+			if deleted := err != nil; deleted != (tt.wantStatus == http.StatusOK) {
+				t.Fatalf("department deleted = %v, err = %v", deleted, err)
+			}
+			employees, err := app.repos.Employees.FindByDepartment(1)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tt.wantStatus == http.StatusOK && len(employees) != 0 {
+				t.Fatalf("employees left = %d, want 0", len(employees))
+			}
+		})
+	}
+}
+
+func TestRestoreEmployeeDeletedDepartment(t *testing.T) {
+	app := newTestApp(t)
+	admin := token(t, models.RoleAdmin, "")
+
+	// Employee dihapus sendiri, jadi tidak ikut di-restore bersama department
+	status, resp := app.do(t, admin, http.MethodDelete, "/api/employee/3", "")
+	expect(t, status, resp, http.StatusOK, "")
+	status, resp = app.do(t, admin, http.MethodDelete, "/api/departement/2", "")
+	expect(t, status, resp, http.StatusOK, "")
+
+	status, resp = app.do(t, admin, http.MethodPost, "/api/employee/3/restore", "")
+	expect(t, status, resp, http.StatusConflict, "department_deleted")
+
+	status, resp = app.do(t, admin, http.MethodPost, "/api/departement/2/restore", "")
+	expect(t, status, resp, http.StatusOK, "")
+	status, resp = app.do(t, admin, http.MethodPost, "/api/employee/3/restore", "")
+	expect(t, status, resp, http.StatusOK, "")
+}
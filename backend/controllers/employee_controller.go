@@ -1,20 +1,35 @@
 package controllers
 
 import (
-	"fleetify-backend/config"
+	"errors"
+	"fleetify-backend/idgen"
 	"fleetify-backend/models"
+	"fleetify-backend/repositories"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
+type EmployeeController struct {
+	employees   repositories.EmployeeRepository
+	departments repositories.DepartmentRepository
+	shifts      repositories.ShiftRepository
+	transactor  repositories.Transactor
+}
+
+func NewEmployeeController(employees repositories.EmployeeRepository, departments repositories.DepartmentRepository, shifts repositories.ShiftRepository, transactor repositories.Transactor) *EmployeeController {
+	return &EmployeeController{employees: employees, departments: departments, shifts: shifts, transactor: transactor}
+}
+
 // Response structs
 type EmployeeDepartmentResp struct {
 	ID              uint   `json:"id"`
 	DepartmentName  string `json:"department_name"`
 	MaxClockInTime  string `json:"max_clock_in_time"`
 	MaxClockOutTime string `json:"max_clock_out_time"`
+	ShiftID         *uint  `json:"shift_id"`
 	CreatedAt       string `json:"created_at"`
 	UpdatedAt       string `json:"updated_at"`
 }
@@ -23,10 +38,13 @@ type EmployeeDetailResp struct {
 	ID           uint                   `json:"id"`
 	EmployeeID   string                 `json:"employee_id"`
 	DepartmentID uint                   `json:"department_id"`
+	ShiftID      *uint                  `json:"shift_id"`
 	Name         string                 `json:"name"`
 	Address      string                 `json:"address"`
+	TerminatedAt *time.Time             `json:"terminated_at"`
 	CreatedAt    string                 `json:"created_at"`
 	UpdatedAt    string                 `json:"updated_at"`
+	DeletedAt    *time.Time             `json:"deleted_at"`
 	Department   EmployeeDepartmentResp `json:"department"`
 }
 
@@ -37,6 +55,7 @@ func toEmployeeDepartmentResp(dept models.Department) EmployeeDepartmentResp {
 		DepartmentName:  dept.DepartmentName,
 		MaxClockInTime:  dept.MaxClockInTime,
 		MaxClockOutTime: dept.MaxClockOutTime,
+		ShiftID:         dept.ShiftID,
 		CreatedAt:       dept.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
 		UpdatedAt:       dept.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
 	}
@@ -47,18 +66,25 @@ func toEmployeeDetailResp(emp models.Employee) EmployeeDetailResp {
 		ID:           emp.ID,
 		EmployeeID:   emp.EmployeeID,
 		DepartmentID: emp.DepartmentID,
+		ShiftID:      emp.ShiftID,
 		Name:         emp.Name,
 		Address:      emp.Address,
+		TerminatedAt: emp.TerminatedAt,
 		CreatedAt:    emp.CreatedAt.Format("2006-01-02T15:04:05Z07:00"),
 		UpdatedAt:    emp.UpdatedAt.Format("2006-01-02T15:04:05Z07:00"),
+		DeletedAt:    deletedAt(emp.DeletedAt),
 		Department:   toEmployeeDepartmentResp(emp.Department),
 	}
 }
 
-// Get all employees
-func GetAllEmployees(c *gin.Context) {
-	var employees []models.Employee
-	if err := config.DB.Preload("Department").Find(&employees).Error; err != nil {
+// Get all employees, ?include_deleted=true ikut menampilkan yang sudah dihapus
+func (ctrl *EmployeeController) GetAllEmployees(c *gin.Context) {
+	repo := ctrl.employees
+	if includeDeleted(c) {
+		repo = repo.WithDeleted()
+	}
+	employees, err := repo.FindAll()
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal Server Error"})
 		return
 	}
@@ -70,18 +96,22 @@ func GetAllEmployees(c *gin.Context) {
 }
 
 // Get employee detail by ID
-func GetEmployeeDetail(c *gin.Context) {
-	id := c.Param("id")
-	var employee models.Employee
-	if err := config.DB.Preload("Department").First(&employee, id).Error; err != nil {
+func (ctrl *EmployeeController) GetEmployeeDetail(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Employee not found"})
+		return
+	}
+	employee, err := ctrl.employees.FindByID(id)
+	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Employee not found"})
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"data": toEmployeeDetailResp(employee)})
+	c.JSON(http.StatusOK, gin.H{"data": toEmployeeDetailResp(*employee)})
 }
 
 // Create a new employee
-func CreateEmployee(c *gin.Context) {
+func (ctrl *EmployeeController) CreateEmployee(c *gin.Context) {
 	var input struct {
 		DepartmentID uint   `form:"department_id"`
 		Name         string `form:"name"`
@@ -106,47 +136,60 @@ func CreateEmployee(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Address is required"})
 		return
 	}
-
-	// Generate EmployeeID format EMP-xxx
-	var lastEmployee models.Employee
-	if err := config.DB.Order("id desc").First(&lastEmployee).Error; err != nil && err.Error() != "record not found" {
-		fmt.Println("DB error:", err.Error()) // log internal error
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal Server Error"})
+	if !ctrl.departmentExists(c, input.DepartmentID) {
 		return
 	}
 
-	nextID := 1
-	if lastEmployee.ID > 0 {
-		nextID = int(lastEmployee.ID) + 1
-	}
-	employeeID := fmt.Sprintf("EMP-%03d", nextID)
-
 	employee := models.Employee{
-		EmployeeID:   employeeID,
 		DepartmentID: input.DepartmentID,
 		Name:         input.Name,
 		Address:      input.Address,
 	}
 
-	if err := config.DB.Create(&employee).Error; err != nil {
-		fmt.Println("DB error:", err.Error())
+	// Generate EmployeeID format EMP-xxx dari counter id_sequences, satu transaksi
+	// dengan insert supaya nomor tidak terpakai kalau insert gagal. Department dikunci
+	// supaya tidak terhapus bersamaan (lihat DeleteDepartment).
+	err := ctrl.transactor.Transaction(func(tx repositories.Repositories) error {
+		if err := tx.Departments.LockByID(employee.DepartmentID); err != nil {
+			return err
+		}
+		employeeID, err := idgen.Next(tx.Sequences, idgen.Employee, time.Now(), tx.Employees.CodesWithPrefix)
+		if err != nil {
+			return err
+		}
+		employee.EmployeeID = employeeID
+		return tx.Employees.Create(&employee)
+	})
+	if sequenceExhausted(c, err, idgen.Employee, "EMPLOYEE_ID") {
+		return
+	}
+	if errors.Is(err, repositories.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Department not found"})
+		return
+	}
+	if err != nil {
+		fmt.Println("DB error:", err.Error()) // log internal error
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal Server Error"})
 		return
 	}
 
 	// Return with response struct
-	if err := config.DB.Preload("Department").First(&employee, employee.ID).Error; err == nil {
-		c.JSON(http.StatusOK, gin.H{"data": toEmployeeDetailResp(employee)})
+	if created, err := ctrl.employees.FindByID(employee.ID); err == nil {
+		c.JSON(http.StatusOK, gin.H{"data": toEmployeeDetailResp(*created)})
 	} else {
 		c.JSON(http.StatusOK, gin.H{"data": employee})
 	}
 }
 
 // Update employee by ID
-func UpdateEmployee(c *gin.Context) {
-	id := c.Param("id")
-	var employee models.Employee
-	if err := config.DB.First(&employee, id).Error; err != nil {
+func (ctrl *EmployeeController) UpdateEmployee(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Employee not found"})
+		return
+	}
+	employee, err := ctrl.employees.FindByID(id)
+	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Employee not found"})
 		return
 	}
@@ -175,40 +218,236 @@ func UpdateEmployee(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Address is required"})
 		return
 	}
+	if !ctrl.departmentExists(c, input.DepartmentID) {
+		return
+	}
 
 	employee.DepartmentID = input.DepartmentID
 	employee.Name = input.Name
 	employee.Address = input.Address
 
-	if err := config.DB.Save(&employee).Error; err != nil {
+	// Department tujuan dikunci supaya tidak terhapus bersamaan (lihat DeleteDepartment)
+	err = ctrl.transactor.Transaction(func(tx repositories.Repositories) error {
+		if err := tx.Departments.LockByID(employee.DepartmentID); err != nil {
+			return err
+		}
+		return tx.Employees.Update(employee)
+	})
+	if errors.Is(err, repositories.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Department not found"})
+		return
+	}
+	if err != nil {
 		fmt.Println("DB error:", err.Error())
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal Server Error"})
 		return
 	}
 
 	// Return with response struct
-	if err := config.DB.Preload("Department").First(&employee, employee.ID).Error; err == nil {
-		c.JSON(http.StatusOK, gin.H{"data": toEmployeeDetailResp(employee)})
+	if updated, err := ctrl.employees.FindByID(employee.ID); err == nil {
+		c.JSON(http.StatusOK, gin.H{"data": toEmployeeDetailResp(*updated)})
 	} else {
 		c.JSON(http.StatusOK, gin.H{"data": employee})
 	}
 }
 
-// Delete
-func DeleteEmployee(c *gin.Context) {
-	id := c.Param("id")
-	var employee models.Employee
-	if err := config.DB.First(&employee, id).Error; err != nil {
+// departmentExists cek department tujuan ada dan belum di-soft delete
+func (ctrl *EmployeeController) departmentExists(c *gin.Context, departmentID uint) bool {
+	_, err := ctrl.departments.FindByID(departmentID)
+	if errors.Is(err, repositories.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Department not found"})
+		return false
+	}
+	if err != nil {
+		fmt.Println("DB error:", err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal Server Error"})
+		return false
+	}
+	return true
+}
+
+// AssignShift mengatur shift khusus employee (override shift department)
+func (ctrl *EmployeeController) AssignShift(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Employee not found"})
+		return
+	}
+	employee, err := ctrl.employees.FindByID(id)
+	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "Employee not found"})
 		return
 	}
-	// Delete attendance history & attendance
-	config.DB.Where("employee_id = ?", employee.EmployeeID).Delete(&models.AttendanceHistory{})
-	config.DB.Where("employee_id = ?", employee.EmployeeID).Delete(&models.Attendance{})
-	// Delete employee
-	if err := config.DB.Delete(&employee).Error; err != nil {
+
+	var input ShiftAssignInput
+	if err := c.ShouldBind(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input format"})
+		return
+	}
+	shiftID, err := lookupShift(ctrl.shifts, input.ShiftID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Shift not found"})
+		return
+	}
+
+	employee.ShiftID = shiftID
+	if err := ctrl.employees.Update(employee); err != nil {
+		fmt.Println("DB error:", err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal Server Error"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": toEmployeeDetailResp(*employee)})
+}
+
+// TerminateEmployee menonaktifkan employee mulai terminated_at (default sekarang)
+func (ctrl *EmployeeController) TerminateEmployee(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Employee not found"})
+		return
+	}
+	employee, err := ctrl.employees.FindByID(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Employee not found"})
+		return
+	}
+
+	var input struct {
+		TerminatedAt string `form:"terminated_at" json:"terminated_at"` // format: 2006-01-02 15:04:05
+	}
+	if err := c.ShouldBind(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input format"})
+		return
+	}
+
+	terminatedAt := time.Now()
+	if input.TerminatedAt != "" {
+		terminatedAt, err = time.Parse("2006-01-02 15:04:05", input.TerminatedAt)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid format for terminated_at, expected YYYY-MM-DD HH:mm:ss"})
+			return
+		}
+	}
+
+	employee.TerminatedAt = &terminatedAt
+	if err := ctrl.employees.Update(employee); err != nil {
+		fmt.Println("DB error:", err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal Server Error"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": toEmployeeDetailResp(*employee)})
+}
+
+// ReactivateEmployee mengaktifkan kembali employee yang sudah berhenti
+func (ctrl *EmployeeController) ReactivateEmployee(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Employee not found"})
+		return
+	}
+	employee, err := ctrl.employees.FindByID(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Employee not found"})
+		return
+	}
+
+	employee.TerminatedAt = nil
+	if err := ctrl.employees.Update(employee); err != nil {
+		fmt.Println("DB error:", err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal Server Error"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": toEmployeeDetailResp(*employee)})
+}
+
+// Delete (soft delete), attendance employee tetap tersimpan
+func (ctrl *EmployeeController) DeleteEmployee(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Employee not found"})
+		return
+	}
+	employee, err := ctrl.employees.FindByID(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Employee not found"})
+		return
+	}
+	if err := ctrl.employees.Delete(employee); err != nil {
+		fmt.Println("DB error:", err.Error())
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete employee"})
 		return
 	}
 	c.JSON(http.StatusOK, gin.H{"message": "Employee deleted successfully"})
 }
+
+// RestoreEmployee mengembalikan employee yang sudah di-soft delete
+func (ctrl *EmployeeController) RestoreEmployee(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Employee not found"})
+		return
+	}
+	employee, err := ctrl.employees.WithDeleted().FindByID(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Employee not found"})
+		return
+	}
+	if !employee.DeletedAt.Valid {
+		c.JSON(http.StatusConflict, gin.H{"error": "Employee is not deleted", "code": CodeNotDeleted})
+		return
+	}
+	// Department harus di-restore dulu
+	if employee.Department.ID == 0 || employee.Department.DeletedAt.Valid {
+		c.JSON(http.StatusConflict, gin.H{"error": "Department of this employee is deleted, restore the department first", "code": CodeDepartmentDeleted})
+		return
+	}
+
+	err = ctrl.transactor.Transaction(func(tx repositories.Repositories) error {
+		if err := tx.Departments.LockByID(employee.DepartmentID); err != nil {
+			return err
+		}
+		return tx.Employees.Restore(employee)
+	})
+	if errors.Is(err, repositories.ErrNotFound) {
+		c.JSON(http.StatusConflict, gin.H{"error": "Department of this employee is deleted, restore the department first", "code": CodeDepartmentDeleted})
+		return
+	}
+	if err != nil {
+		fmt.Println("DB error:", err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to restore employee"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": toEmployeeDetailResp(*employee)})
+}
+
+// PurgeEmployee hapus permanen employee yang sudah di-soft delete beserta attendance-nya
+func (ctrl *EmployeeController) PurgeEmployee(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Employee not found"})
+		return
+	}
+	employee, err := ctrl.employees.WithDeleted().FindByID(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Employee not found"})
+		return
+	}
+	if !employee.DeletedAt.Valid {
+		c.JSON(http.StatusConflict, gin.H{"error": "Employee must be deleted before it can be purged", "code": CodeNotDeleted})
+		return
+	}
+
+	// Delete attendance history & attendance lalu employee dalam satu transaksi
+	err = ctrl.transactor.Transaction(func(tx repositories.Repositories) error {
+		if err := tx.Attendances.DeleteByEmployee(employee.EmployeeID); err != nil {
+			return err
+		}
+		return tx.Employees.Purge(employee)
+	})
+	if err != nil {
+		fmt.Println("DB error:", err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to purge employee"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Employee purged successfully"})
+}
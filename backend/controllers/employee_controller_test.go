@@ -0,0 +1,97 @@
+package controllers_test
+
+import (
+	"fleetify-backend/models"
+	"net/http"
+	"testing"
+)
+
+func TestCreateEmployee(t *testing.T) {
+	tests := []struct {
+		name       string
+		role       string
+		body       string
+		wantStatus int
+		wantCode   string
+		wantID     string
+	}{
+		{name: "hr creates employee", role: models.RoleHR, body: "department_id=1&name=Dewi&address=Bandung", wantStatus: http.StatusOK, wantID: "EMP-004"},
+		{name: "admin creates employee", role: models.RoleAdmin, body: "department_id=2&name=Eko&address=Jakarta", wantStatus: http.StatusOK, wantID: "EMP-004"},
+		{name: "employee is forbidden", role: models.RoleEmployee, body: "department_id=1&name=Dewi&address=Bandung", wantStatus: http.StatusForbidden},
+		{name: "manager is forbidden", role: models.RoleManager, body: "department_id=1&name=Dewi&address=Bandung", wantStatus: http.StatusForbidden},
+		{name: "missing department", role: models.RoleHR, body: "name=Dewi&address=Bandung", wantStatus: http.StatusBadRequest},
+		{name: "unknown department", role: models.RoleHR, body: "department_id=99&name=Dewi&address=Bandung", wantStatus: http.StatusNotFound},
+		{name: "missing name", role: models.RoleHR, body: "department_id=1&address=Bandung", wantStatus: http.StatusBadRequest},
+		{name: "missing address", role: models.RoleHR, body: "department_id=1&name=Dewi", wantStatus: http.StatusBadRequest},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := newTestApp(t)
+			status, resp := app.do(t, token(t, tt.role, ""), http.MethodPost, "/api/employee", tt.body)
+			expect(t, status, resp, tt.wantStatus, tt.wantCode)
+			if tt.wantID != "" && data(t, resp)["employee_id"] != tt.wantID {
+				t.Fatalf("employee_id = %v, want %s", data(t, resp)["employee_id"], tt.wantID)
+			}
+		})
+	}
+}
+
+func TestEmployeeDeletedDepartment(t *testing.T) {
+	app := newTestApp(t)
+	hr := token(t, models.RoleHR, "")
+	department := models.Department{DepartmentName: "Closed", MaxClockInTime: "08:00:00", MaxClockOutTime: "17:00:00"}
+	if err := app.repos.Departments.Create(&department); err != nil {
+		t.Fatal(err)
+	}
+	if err := app.repos.Departments.Delete(&department); err != nil {
+		t.Fatal(err)
+	}
+	body := "department_id=" + jsonID(department.ID) + "&name=Dewi&address=Bandung"
+
+	status, resp := app.do(t, hr, http.MethodPost, "/api/employee", body)
+	expect(t, status, resp, http.StatusNotFound, "")
+
+	status, resp = app.do(t, hr, http.MethodPatch, "/api/employee/1", body)
+	expect(t, status, resp, http.StatusNotFound, "")
+	employee, err := app.repos.Employees.FindByID(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if employee.DepartmentID != 1 {
+		t.Fatalf("department_id = %d, want unchanged 1", employee.DepartmentID)
+	}
+
+	status, resp = app.do(t, hr, http.MethodPatch, "/api/employee/1", "department_id=2&name=Andi&address=Bandung")
+	expect(t, status, resp, http.StatusOK, "")
+}
+
+func TestGetEmployeeDetail(t *testing.T) {
+	app := newTestApp(t)
+	employee := token(t, models.RoleEmployee, "EMP-001")
+
+	tests := []struct {
+		name       string
+		path       string
+		wantStatus int
+	}{
+		{name: "existing employee", path: "/api/employee/1", wantStatus: http.StatusOK},
+		{name: "unknown id", path: "/api/employee/99", wantStatus: http.StatusNotFound},
+		{name: "invalid id", path: "/api/employee/abc", wantStatus: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, resp := app.do(t, employee, http.MethodGet, tt.path, "")
+			expect(t, status, resp, tt.wantStatus, "")
+		})
+	}
+}
+
+func TestCreateEmployeeSequenceExhausted(t *testing.T) {
+	app := newTestApp(t)
+	if err := app.repos.Sequences.EnsureAtLeast("employee", "", 999); err != nil {
+		t.Fatal(err)
+	}
+
+	status, resp := app.do(t, token(t, models.RoleHR, ""), http.MethodPost, "/api/employee", "department_id=1&name=Dewi&address=Bandung")
+	expect(t, status, resp, http.StatusServiceUnavailable, "id_sequence_exhausted")
+}
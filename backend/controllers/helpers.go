@@ -0,0 +1,123 @@
+package controllers
+
+import (
+	"errors"
+	"fleetify-backend/idgen"
+	"fleetify-backend/middlewares"
+	"fleetify-backend/models"
+	"fleetify-backend/repositories"
+	"fleetify-backend/schedule"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// Kode error soft delete
+const (
+	CodeNotDeleted        = "not_deleted"        // restore / purge data yang belum dihapus
+	CodeDepartmentDeleted = "department_deleted" // restore employee di department yang masih terhapus
+)
+
+// CodeIDSequenceExhausted kode employee / attendance berikutnya tidak muat di padding
+const CodeIDSequenceExhausted = "id_sequence_exhausted"
+
+// sequenceExhausted menjawab 503 kalau counter kode sudah penuh. Kode yang lebih
+// panjang dari padding merusak urutan, jadi admin harus menaikkan *_PADDING dulu.
+func sequenceExhausted(c *gin.Context, err error, f idgen.Format, env string) bool {
+	if !errors.Is(err, idgen.ErrSequenceExhausted) {
+		return false
+	}
+	fmt.Println("ID sequence error:", err.Error())
+	c.JSON(http.StatusServiceUnavailable, gin.H{
+		"error":   fmt.Sprintf("No %s codes left for padding %d, ask an administrator to increase %s_PADDING", f.Prefix, f.Padding, env),
+		"code":    CodeIDSequenceExhausted,
+		"prefix":  f.Prefix,
+		"padding": f.Padding,
+	})
+	return true
+}
+
+// parseID membaca parameter :id numerik dari URL
+func parseID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// Input untuk assign shift, shift_id kosong / 0 / null berarti lepas shift
+type ShiftAssignInput struct {
+	ShiftID *uint `form:"shift_id" json:"shift_id"`
+}
+
+// lookupShift mengembalikan shift_id yang valid (nil = tanpa shift)
+func lookupShift(shifts repositories.ShiftRepository, shiftID *uint) (*uint, error) {
+	if shiftID == nil || *shiftID == 0 {
+		return nil, nil
+	}
+	shift, err := shifts.FindByID(*shiftID)
+	if err != nil {
+		return nil, err
+	}
+	return &shift.ID, nil
+}
+
+// includeDeleted membaca query ?include_deleted=true
+func includeDeleted(c *gin.Context) bool {
+	include, _ := strconv.ParseBool(c.Query("include_deleted"))
+	return include
+}
+
+// deletedAt nil kalau data belum dihapus
+func deletedAt(d gorm.DeletedAt) *time.Time {
+	if !d.Valid {
+		return nil
+	}
+	return &d.Time
+}
+
+// holidayCalendar hari libur di rentang tanggal (YYYY-MM-DD, kosong = tanpa batas) untuk schedule.Resolve
+func holidayCalendar(holidays repositories.HolidayRepository, from, to string) (schedule.Calendar, error) {
+	list, err := holidays.FindAll(repositories.HolidayFilter{DateFrom: from, DateTo: to})
+	return schedule.Calendar(list), err
+}
+
+// allowedToReview: admin & HR untuk siapa saja, manager untuk department sendiri.
+// Tidak ada yang boleh memproses pengajuan miliknya sendiri (403 ownReason).
+func allowedToReview(c *gin.Context, employees repositories.EmployeeRepository, employee models.Employee, ownReason string) bool {
+	user := middlewares.CurrentUser(c)
+	if user == nil {
+		return true
+	}
+	if user.EmployeeID != "" && user.EmployeeID == employee.EmployeeID {
+		middlewares.Forbid(c, ownReason, "You cannot review your own request")
+		return false
+	}
+	if canPunchForOthers(user.Role) {
+		return true
+	}
+	return managesDepartment(c, employees, employee)
+}
+
+// managesDepartment mengirim 403 kalau employee bukan dari department manager yang login
+func managesDepartment(c *gin.Context, employees repositories.EmployeeRepository, employee models.Employee) bool {
+	user := middlewares.CurrentUser(c)
+	if user == nil {
+		return true
+	}
+	manager, err := employees.FindByEmployeeID(user.EmployeeID)
+	if user.EmployeeID == "" || err != nil {
+		middlewares.Forbid(c, middlewares.ReasonNoEmployeeLinked, "Your account is not linked to an employee")
+		return false
+	}
+	if manager.DepartmentID != employee.DepartmentID {
+		middlewares.Forbid(c, middlewares.ReasonNotOwnDepartment, "You can only manage requests of your own department")
+		return false
+	}
+	return true
+}
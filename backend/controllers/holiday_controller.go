@@ -0,0 +1,367 @@
+package controllers
+
+import (
+	"errors"
+	"fleetify-backend/ical"
+	"fleetify-backend/models"
+	"fleetify-backend/repositories"
+	"fleetify-backend/schedule"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type HolidayController struct {
+	holidays    repositories.HolidayRepository
+	departments repositories.DepartmentRepository
+	transactor  repositories.Transactor
+}
+
+func NewHolidayController(holidays repositories.HolidayRepository, departments repositories.DepartmentRepository, transactor repositories.Transactor) *HolidayController {
+	return &HolidayController{holidays: holidays, departments: departments, transactor: transactor}
+}
+
+// CodeHolidayExists 409, tanggal yang sama sudah jadi hari libur untuk cakupan yang sama
+const CodeHolidayExists = "holiday_exists"
+
+// errHolidayExists membatalkan transaksi create / update hari libur duplikat
+var errHolidayExists = errors.New("holiday already exists")
+
+// Input hari libur, department_id kosong = semua department
+type HolidayInput struct {
+	Date         string `form:"date" json:"date"` // YYYY-MM-DD
+	Name         string `form:"name" json:"name"`
+	Type         string `form:"type" json:"type"` // national (default) / company
+	DepartmentID *uint  `form:"department_id" json:"department_id"`
+}
+
+type HolidayResp struct {
+	ID           uint      `json:"id"`
+	Date         string    `json:"date"`
+	Weekday      string    `json:"weekday"`
+	Name         string    `json:"name"`
+	Type         string    `json:"type"`
+	DepartmentID *uint     `json:"department_id"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+func toHolidayResp(holiday models.Holiday) HolidayResp {
+	weekday := ""
+	if date, err := time.Parse(schedule.DateLayout, holiday.Date); err == nil {
+		weekday = date.Weekday().String()
+	}
+	return HolidayResp{
+		ID:           holiday.ID,
+		Date:         holiday.Date,
+		Weekday:      weekday,
+		Name:         holiday.Name,
+		Type:         holiday.Type,
+		DepartmentID: holiday.DepartmentID,
+		CreatedAt:    holiday.CreatedAt,
+		UpdatedAt:    holiday.UpdatedAt,
+	}
+}
+
+// GetAllHolidays ?year= atau ?from=&to=, ?type=, ?department_id= (termasuk libur umum)
+func (ctrl *HolidayController) GetAllHolidays(c *gin.Context) {
+	filter := repositories.HolidayFilter{Type: c.Query("type")}
+
+	if yearParam := c.Query("year"); yearParam != "" {
+		year, err := strconv.Atoi(yearParam)
+		if err != nil || year < 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid format for year"})
+			return
+		}
+		filter.DateFrom, filter.DateTo = yearRange(year)
+	}
+	for param, target := range map[string]*string{"from": &filter.DateFrom, "to": &filter.DateTo} {
+		value := c.Query(param)
+		if value == "" {
+			continue
+		}
+		if _, err := time.Parse(schedule.DateLayout, value); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("invalid format for %s, expected YYYY-MM-DD", param)})
+			return
+		}
+		*target = value
+	}
+	if departmentParam := c.Query("department_id"); departmentParam != "" {
+		departmentID, err := strconv.ParseUint(departmentParam, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid format for department_id"})
+			return
+		}
+		id := uint(departmentID)
+		filter.DepartmentID = &id
+	}
+
+	holidays, err := ctrl.holidays.FindAll(filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal Server Error"})
+		return
+	}
+	resp := []HolidayResp{}
+	for _, holiday := range holidays {
+		resp = append(resp, toHolidayResp(holiday))
+	}
+	c.JSON(http.StatusOK, gin.H{"data": resp})
+}
+
+// GetHolidayDetail
+func (ctrl *HolidayController) GetHolidayDetail(c *gin.Context) {
+	holiday, ok := ctrl.findHoliday(c)
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": toHolidayResp(*holiday)})
+}
+
+// CreateHoliday
+func (ctrl *HolidayController) CreateHoliday(c *gin.Context) {
+	var input HolidayInput
+	if err := c.ShouldBind(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input format"})
+		return
+	}
+	holiday := models.Holiday{}
+	if !ctrl.applyInput(c, &holiday, input) {
+		return
+	}
+
+	err := ctrl.transactor.Transaction(func(tx repositories.Repositories) error {
+		return saveHoliday(tx, &holiday)
+	})
+	if !ctrl.handleSaveError(c, holiday, err) {
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": toHolidayResp(holiday)})
+}
+
+// UpdateHoliday
+func (ctrl *HolidayController) UpdateHoliday(c *gin.Context) {
+	holiday, ok := ctrl.findHoliday(c)
+	if !ok {
+		return
+	}
+
+	var input HolidayInput
+	if err := c.ShouldBind(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input format"})
+		return
+	}
+	if input.Type == "" {
+		input.Type = holiday.Type
+	}
+	if !ctrl.applyInput(c, holiday, input) {
+		return
+	}
+
+	err := ctrl.transactor.Transaction(func(tx repositories.Repositories) error {
+		return saveHoliday(tx, holiday)
+	})
+	if !ctrl.handleSaveError(c, *holiday, err) {
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": toHolidayResp(*holiday)})
+}
+
+// DeleteHoliday. Catatan absen yang sudah terhapus karena hari libur tidak dibuat ulang,
+// jalankan detect-absences untuk tanggal tersebut kalau perlu.
+func (ctrl *HolidayController) DeleteHoliday(c *gin.Context) {
+	holiday, ok := ctrl.findHoliday(c)
+	if !ok {
+		return
+	}
+	if err := ctrl.holidays.Delete(holiday); err != nil {
+		fmt.Println("DB error:", err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete holiday"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Holiday deleted successfully"})
+}
+
+// ImportHolidays membaca file .ics (multipart field "file"). Setiap hari dari sebuah
+// event menjadi satu hari libur dengan type / department_id dari form. Tanggal yang
+// sudah ada untuk cakupan yang sama dilewati.
+func (ctrl *HolidayController) ImportHolidays(c *gin.Context) {
+	var input HolidayInput
+	if err := c.ShouldBind(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input format"})
+		return
+	}
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "File is required"})
+		return
+	}
+	file, err := fileHeader.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Failed to read file"})
+		return
+	}
+	defer file.Close()
+
+	events, err := ical.Parse(file)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Invalid iCalendar file: %s", err.Error())})
+		return
+	}
+
+	// Nama dan tanggal diambil dari event, type / department dari form
+	if !ctrl.validateScope(c, &input) {
+		return
+	}
+	template := models.Holiday{Type: input.Type, DepartmentID: input.DepartmentID}
+
+	var created []models.Holiday
+	var skipped []string
+	err = ctrl.transactor.Transaction(func(tx repositories.Repositories) error {
+		for _, event := range events {
+			for _, date := range event.Dates() {
+				holiday := template
+				holiday.Date = date.Format(schedule.DateLayout)
+				holiday.Name = event.Summary
+				if holiday.Name == "" {
+					holiday.Name = "Holiday"
+				}
+				err := saveHoliday(tx, &holiday)
+				if errors.Is(err, errHolidayExists) {
+					skipped = append(skipped, holiday.Date)
+					continue
+				}
+				if err != nil {
+					return err
+				}
+				created = append(created, holiday)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		fmt.Println("DB error:", err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to import holidays"})
+		return
+	}
+
+	resp := []HolidayResp{}
+	for _, holiday := range created {
+		resp = append(resp, toHolidayResp(holiday))
+	}
+	if skipped == nil {
+		skipped = []string{}
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"data":     resp,
+		"imported": len(created),
+		"skipped":  skipped,
+	})
+}
+
+// saveHoliday create / update lalu hapus catatan absen yang jatuh di hari libur tersebut
+func saveHoliday(tx repositories.Repositories, holiday *models.Holiday) error {
+	existing, err := tx.Holidays.FindAll(repositories.HolidayFilter{DateFrom: holiday.Date, DateTo: holiday.Date})
+	if err != nil {
+		return err
+	}
+	for _, other := range existing {
+		if other.ID != holiday.ID && sameScope(other.DepartmentID, holiday.DepartmentID) {
+			return errHolidayExists
+		}
+	}
+
+	if holiday.ID == 0 {
+		err = tx.Holidays.Create(holiday)
+	} else {
+		err = tx.Holidays.Update(holiday)
+	}
+	if err != nil {
+		return err
+	}
+	return tx.Attendances.DeleteAbsencesOn(holiday.Date, holiday.DepartmentID)
+}
+
+func sameScope(a, b *uint) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
+
+// applyInput validasi input lalu mengisinya ke holiday, false kalau response error sudah dikirim
+func (ctrl *HolidayController) applyInput(c *gin.Context, holiday *models.Holiday, input HolidayInput) bool {
+	if _, err := time.Parse(schedule.DateLayout, input.Date); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid format for date, expected YYYY-MM-DD"})
+		return false
+	}
+	if input.Name == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Name is required"})
+		return false
+	}
+	if !ctrl.validateScope(c, &input) {
+		return false
+	}
+
+	holiday.Date = input.Date
+	holiday.Name = input.Name
+	holiday.Type = input.Type
+	holiday.DepartmentID = input.DepartmentID
+	return true
+}
+
+// validateScope default dan validasi type / department_id
+func (ctrl *HolidayController) validateScope(c *gin.Context, input *HolidayInput) bool {
+	if input.Type == "" {
+		input.Type = models.HolidayNational
+	}
+	if input.Type != models.HolidayNational && input.Type != models.HolidayCompany {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "type must be national or company"})
+		return false
+	}
+	if input.DepartmentID != nil && *input.DepartmentID == 0 {
+		input.DepartmentID = nil
+	}
+	if input.DepartmentID != nil {
+		if _, err := ctrl.departments.FindByID(*input.DepartmentID); err != nil {
+			c.JSON(http.StatusNotFound, gin.H{"error": "Department not found"})
+			return false
+		}
+	}
+	return true
+}
+
+// handleSaveError kirim response untuk error saveHoliday, true kalau tidak ada error
+func (ctrl *HolidayController) handleSaveError(c *gin.Context, holiday models.Holiday, err error) bool {
+	if errors.Is(err, errHolidayExists) {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":         fmt.Sprintf("A holiday on %s already exists", holiday.Date),
+			"code":          CodeHolidayExists,
+			"date":          holiday.Date,
+			"department_id": holiday.DepartmentID,
+		})
+		return false
+	}
+	if err != nil {
+		fmt.Println("DB error:", err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal Server Error"})
+		return false
+	}
+	return true
+}
+
+func (ctrl *HolidayController) findHoliday(c *gin.Context) (*models.Holiday, bool) {
+	id, ok := parseID(c)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Holiday not found"})
+		return nil, false
+	}
+	holiday, err := ctrl.holidays.FindByID(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Holiday not found"})
+		return nil, false
+	}
+	return holiday, true
+}
@@ -0,0 +1,617 @@
+package controllers
+
+import (
+	"errors"
+	"fleetify-backend/middlewares"
+	"fleetify-backend/models"
+	"fleetify-backend/repositories"
+	"fleetify-backend/schedule"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type LeaveController struct {
+	leaves     repositories.LeaveRepository
+	employees  repositories.EmployeeRepository
+	holidays   repositories.HolidayRepository
+	transactor repositories.Transactor
+}
+
+func NewLeaveController(leaves repositories.LeaveRepository, employees repositories.EmployeeRepository, holidays repositories.HolidayRepository, transactor repositories.Transactor) *LeaveController {
+	return &LeaveController{leaves: leaves, employees: employees, holidays: holidays, transactor: transactor}
+}
+
+// Kode error pengajuan cuti
+const (
+	CodeLeaveOverlap        = "leave_overlap"              // 409, beririsan dengan cuti pending / approved
+	CodeLeaveNotPending     = "leave_not_pending"          // 409, approve / reject cuti yang sudah diproses
+	CodeLeaveNotCancellable = "leave_not_cancellable"      // 409, cancel cuti yang sudah ditolak / dibatalkan
+	CodeInsufficientLeave   = "insufficient_leave_balance" // 422
+	CodeLeaveNoWorkingDays  = "no_working_days"            // 422, rentang cuti hanya berisi hari libur
+	CodeLeaveTypeNotFound   = "leave_type_not_found"       // 422
+	CodeLeaveCrossesYear    = "leave_crosses_year"         // 422, jatah cuti dihitung per tahun
+)
+
+// Error sentinel untuk membatalkan transaksi pengajuan cuti
+var (
+	errLeaveOverlap        = errors.New("leave overlaps another request")
+	errLeaveBalance        = errors.New("insufficient leave balance")
+	errLeaveNotPending     = errors.New("leave is not pending")
+	errLeaveNotCancellable = errors.New("leave is not cancellable")
+)
+
+// activeLeaveStatuses status yang memakai jatah dan tidak boleh beririsan
+var activeLeaveStatuses = []string{models.LeaveStatusPending, models.LeaveStatusApproved}
+
+type LeaveResp struct {
+	ID            uint       `json:"id"`
+	EmployeeID    string     `json:"employee_id"`
+	Name          string     `json:"name"`
+	DepartmentID  uint       `json:"department_id"`
+	LeaveTypeID   uint       `json:"leave_type_id"`
+	LeaveType     string     `json:"leave_type"`
+	LeaveTypeName string     `json:"leave_type_name"`
+	StartDate     string     `json:"start_date"`
+	EndDate       string     `json:"end_date"`
+	Days          int        `json:"days"`
+	Reason        string     `json:"reason"`
+	Attachment    string     `json:"attachment"`
+	Status        string     `json:"status"`
+	ReviewedBy    *uint      `json:"reviewed_by"`
+	ReviewedAt    *time.Time `json:"reviewed_at"`
+	ReviewNote    string     `json:"review_note"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
+
+// LeaveBalanceResp jatah cuti satu jenis cuti pada satu tahun
+type LeaveBalanceResp struct {
+	LeaveTypeID uint   `json:"leave_type_id"`
+	LeaveType   string `json:"leave_type"`
+	Name        string `json:"name"`
+	Paid        bool   `json:"paid"`
+	YearlyQuota int    `json:"yearly_quota"`
+	Entitled    int    `json:"entitled"`
+	Used        int    `json:"used"`      // approved
+	Pending     int    `json:"pending"`   // masih menunggu approval, sudah mengurangi sisa
+	Remaining   *int   `json:"remaining"` // nil = tidak dibatasi
+}
+
+func toLeaveResp(leave models.LeaveRequest) LeaveResp {
+	return LeaveResp{
+		ID:            leave.ID,
+		EmployeeID:    leave.EmployeeID,
+		Name:          leave.Employee.Name,
+		DepartmentID:  leave.Employee.DepartmentID,
+		LeaveTypeID:   leave.LeaveTypeID,
+		LeaveType:     leave.LeaveType.Code,
+		LeaveTypeName: leave.LeaveType.Name,
+		StartDate:     leave.StartDate,
+		EndDate:       leave.EndDate,
+		Days:          leave.Days,
+		Reason:        leave.Reason,
+		Attachment:    leave.Attachment,
+		Status:        leave.Status,
+		ReviewedBy:    leave.ReviewedBy,
+		ReviewedAt:    leave.ReviewedAt,
+		ReviewNote:    leave.ReviewNote,
+		CreatedAt:     leave.CreatedAt,
+		UpdatedAt:     leave.UpdatedAt,
+	}
+}
+
+// leaveBalances hitung jatah per jenis cuti dari pengajuan pending / approved di tahun tersebut
+func leaveBalances(types []models.LeaveType, leaves []models.LeaveRequest, employee models.Employee, year int) []LeaveBalanceResp {
+	balances := make([]LeaveBalanceResp, 0, len(types))
+	for _, leaveType := range types {
+		balance := LeaveBalanceResp{
+			LeaveTypeID: leaveType.ID,
+			LeaveType:   leaveType.Code,
+			Name:        leaveType.Name,
+			Paid:        leaveType.Paid,
+			YearlyQuota: leaveType.YearlyQuota,
+			Entitled:    leaveType.EntitledDays(employee.CreatedAt, year),
+		}
+		for _, leave := range leaves {
+			if leave.LeaveTypeID != leaveType.ID || leave.EmployeeID != employee.EmployeeID {
+				continue
+			}
+			switch leave.Status {
+			case models.LeaveStatusApproved:
+				balance.Used += leave.Days
+			case models.LeaveStatusPending:
+				balance.Pending += leave.Days
+			}
+		}
+		if leaveType.YearlyQuota > 0 {
+			remaining := balance.Entitled - balance.Used - balance.Pending
+			balance.Remaining = &remaining
+		}
+		balances = append(balances, balance)
+	}
+	return balances
+}
+
+// yearRange rentang 1 Januari - 31 Desember dalam format YYYY-MM-DD
+func yearRange(year int) (string, string) {
+	return fmt.Sprintf("%04d-01-01", year), fmt.Sprintf("%04d-12-31", year)
+}
+
+// GetLeaveTypes daftar jenis cuti
+func (ctrl *LeaveController) GetLeaveTypes(c *gin.Context) {
+	types, err := ctrl.leaves.FindTypes()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal Server Error"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": types})
+}
+
+// GetLeaves daftar pengajuan cuti. Employee hanya melihat miliknya sendiri,
+// manager hanya department sendiri.
+func (ctrl *LeaveController) GetLeaves(c *gin.Context) {
+	filter := repositories.LeaveFilter{
+		EmployeeID: c.Query("employee_id"),
+		DateFrom:   c.Query("from"),
+		DateTo:     c.Query("to"),
+	}
+	if status := c.Query("status"); status != "" {
+		filter.Statuses = []string{status}
+	}
+	for _, date := range []string{filter.DateFrom, filter.DateTo} {
+		if date == "" {
+			continue
+		}
+		if _, err := time.Parse(schedule.DateLayout, date); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid format for from / to, expected YYYY-MM-DD"})
+			return
+		}
+	}
+	if departmentParam := c.Query("department_id"); departmentParam != "" {
+		departmentID, err := strconv.ParseUint(departmentParam, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid format for department_id"})
+			return
+		}
+		id := uint(departmentID)
+		filter.DepartmentID = &id
+	}
+
+	if user := middlewares.CurrentUser(c); user != nil {
+		switch user.Role {
+		case models.RoleEmployee:
+			if user.EmployeeID == "" {
+				middlewares.Forbid(c, middlewares.ReasonNoEmployeeLinked, "Your account is not linked to an employee")
+				return
+			}
+			if filter.EmployeeID != "" && filter.EmployeeID != user.EmployeeID {
+				middlewares.Forbid(c, middlewares.ReasonNotOwnLeave, "You can only view your own leave requests")
+				return
+			}
+			filter.EmployeeID = user.EmployeeID
+		case models.RoleManager:
+			manager, err := ctrl.employees.FindByEmployeeID(user.EmployeeID)
+			if user.EmployeeID == "" || err != nil {
+				middlewares.Forbid(c, middlewares.ReasonNoEmployeeLinked, "Your account is not linked to an employee")
+				return
+			}
+			if filter.DepartmentID != nil && *filter.DepartmentID != manager.DepartmentID {
+				middlewares.Forbid(c, middlewares.ReasonNotOwnDepartment, "You can only view leave requests of your own department")
+				return
+			}
+			filter.DepartmentID = &manager.DepartmentID
+		}
+	}
+
+	leaves, err := ctrl.leaves.FindAll(filter)
+	if err != nil {
+		fmt.Println("DB error:", err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal Server Error"})
+		return
+	}
+	resp := []LeaveResp{}
+	for _, leave := range leaves {
+		resp = append(resp, toLeaveResp(leave))
+	}
+	c.JSON(http.StatusOK, gin.H{"data": resp})
+}
+
+// GetLeaveDetail
+func (ctrl *LeaveController) GetLeaveDetail(c *gin.Context) {
+	leave, ok := ctrl.findLeave(c)
+	if !ok || !ctrl.allowedToView(c, leave.Employee) {
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": toLeaveResp(*leave)})
+}
+
+// CreateLeave mengajukan cuti. Employee / manager hanya untuk dirinya sendiri.
+func (ctrl *LeaveController) CreateLeave(c *gin.Context) {
+	var input struct {
+		EmployeeID  string `form:"employee_id" json:"employee_id"`
+		LeaveTypeID uint   `form:"leave_type_id" json:"leave_type_id"`
+		StartDate   string `form:"start_date" json:"start_date"` // format: 2006-01-02
+		EndDate     string `form:"end_date" json:"end_date"`     // format: 2006-01-02, kosong = satu hari
+		Reason      string `form:"reason" json:"reason"`
+		Attachment  string `form:"attachment" json:"attachment"`
+	}
+	if err := c.ShouldBind(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input format"})
+		return
+	}
+
+	// Employee boleh tidak mengisi employee_id, otomatis dirinya sendiri
+	user := middlewares.CurrentUser(c)
+	if input.EmployeeID == "" && user != nil && !canPunchForOthers(user.Role) {
+		input.EmployeeID = user.EmployeeID
+	}
+	if input.EndDate == "" {
+		input.EndDate = input.StartDate
+	}
+
+	// Custom validation
+	if input.EmployeeID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Employee is required"})
+		return
+	}
+	if input.LeaveTypeID == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Leave type is required"})
+		return
+	}
+	if input.StartDate == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Start date is required"})
+		return
+	}
+	if !ctrl.allowedToRequest(c, input.EmployeeID) {
+		return
+	}
+
+	startDate, errStart := time.Parse(schedule.DateLayout, input.StartDate)
+	endDate, errEnd := time.Parse(schedule.DateLayout, input.EndDate)
+	if errStart != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid format for start_date, expected YYYY-MM-DD"})
+		return
+	}
+	if errEnd != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid format for end_date, expected YYYY-MM-DD"})
+		return
+	}
+	if endDate.Before(startDate) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "end_date must not be before start_date"})
+		return
+	}
+	if endDate.Year() != startDate.Year() {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error": "Leave request must not cross a calendar year, split it into two requests",
+			"code":  CodeLeaveCrossesYear,
+		})
+		return
+	}
+
+	// Employee harus ada dan masih aktif pada tanggal mulai cuti
+	employee, err := ctrl.employees.FindByEmployeeID(input.EmployeeID)
+	if errors.Is(err, repositories.ErrNotFound) {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error":       fmt.Sprintf("Employee %s not found", input.EmployeeID),
+			"code":        CodeEmployeeNotFound,
+			"employee_id": input.EmployeeID,
+		})
+		return
+	}
+	if err != nil {
+		fmt.Println("DB error:", err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal Server Error"})
+		return
+	}
+	if !employee.IsActiveAt(startDate) {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error":         fmt.Sprintf("Employee %s is no longer active", input.EmployeeID),
+			"code":          CodeEmployeeInactive,
+			"employee_id":   input.EmployeeID,
+			"terminated_at": employee.TerminatedAt,
+		})
+		return
+	}
+
+	leaveType, err := ctrl.leaves.FindTypeByID(input.LeaveTypeID)
+	if err != nil {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": "Leave type not found", "code": CodeLeaveTypeNotFound})
+		return
+	}
+
+	// Hanya hari kerja (sesuai shift, di luar hari libur) yang memakai jatah cuti
+	holidays, err := holidayCalendar(ctrl.holidays, input.StartDate, input.EndDate)
+	if err != nil {
+		fmt.Println("DB error:", err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal Server Error"})
+		return
+	}
+	days := len(schedule.WorkingDays(*employee, startDate, endDate, holidays))
+	if days == 0 {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error": "Leave range has no working days",
+			"code":  CodeLeaveNoWorkingDays,
+		})
+		return
+	}
+
+	leave := models.LeaveRequest{
+		EmployeeID:  employee.EmployeeID,
+		LeaveTypeID: leaveType.ID,
+		StartDate:   input.StartDate,
+		EndDate:     input.EndDate,
+		Days:        days,
+		Reason:      input.Reason,
+		Attachment:  input.Attachment,
+		Status:      models.LeaveStatusPending,
+	}
+
+	// Cek irisan dan sisa jatah di transaksi yang sama dengan insert. Baris employee dikunci
+	// dulu supaya dua pengajuan bersamaan untuk employee yang sama dicek satu per satu.
+	var overlap models.LeaveRequest
+	var balance LeaveBalanceResp
+	err = ctrl.transactor.Transaction(func(tx repositories.Repositories) error {
+		if err := tx.Employees.LockByEmployeeID(employee.EmployeeID); err != nil {
+			return err
+		}
+		yearStart, yearEnd := yearRange(startDate.Year())
+		existing, err := tx.Leaves.FindAll(repositories.LeaveFilter{
+			EmployeeID: employee.EmployeeID,
+			Statuses:   activeLeaveStatuses,
+			DateFrom:   yearStart,
+			DateTo:     yearEnd,
+		})
+		if err != nil {
+			return err
+		}
+		for _, other := range existing {
+			if other.StartDate <= leave.EndDate && leave.StartDate <= other.EndDate {
+				overlap = other
+				return errLeaveOverlap
+			}
+		}
+		balance = leaveBalances([]models.LeaveType{*leaveType}, existing, *employee, startDate.Year())[0]
+		if balance.Remaining != nil && *balance.Remaining < leave.Days {
+			return errLeaveBalance
+		}
+		return tx.Leaves.Create(&leave)
+	})
+	if errors.Is(err, errLeaveOverlap) {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":    "Leave request overlaps another pending or approved request",
+			"code":     CodeLeaveOverlap,
+			"leave_id": overlap.ID,
+		})
+		return
+	}
+	if errors.Is(err, errLeaveBalance) {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error":     fmt.Sprintf("Insufficient %s balance", leaveType.Name),
+			"code":      CodeInsufficientLeave,
+			"requested": leave.Days,
+			"remaining": balance.Remaining,
+		})
+		return
+	}
+	if err != nil {
+		fmt.Println("DB error:", err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal Server Error"})
+		return
+	}
+
+	if created, err := ctrl.leaves.FindByID(leave.ID); err == nil {
+		c.JSON(http.StatusOK, gin.H{"data": toLeaveResp(*created)})
+	} else {
+		c.JSON(http.StatusOK, gin.H{"data": leave})
+	}
+}
+
+// ApproveLeave (HR)
+func (ctrl *LeaveController) ApproveLeave(c *gin.Context) {
+	ctrl.review(c, models.LeaveStatusApproved)
+}
+
+// RejectLeave (HR)
+func (ctrl *LeaveController) RejectLeave(c *gin.Context) {
+	ctrl.review(c, models.LeaveStatusRejected)
+}
+
+// review approve / reject cuti yang masih pending. HR tidak boleh memproses cuti miliknya sendiri.
+func (ctrl *LeaveController) review(c *gin.Context, status string) {
+	leave, ok := ctrl.findLeave(c)
+	if !ok || !allowedToReview(c, ctrl.employees, leave.Employee, middlewares.ReasonOwnLeave) {
+		return
+	}
+
+	var input struct {
+		Note string `form:"note" json:"note"`
+	}
+	if err := c.ShouldBind(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input format"})
+		return
+	}
+
+	// Status dicek ulang di dalam transaksi (baris cuti dikunci) supaya approve dan
+	// reject / cancel bersamaan tidak saling menimpa
+	err := ctrl.transactor.Transaction(func(tx repositories.Repositories) error {
+		current, err := tx.Leaves.LockByID(leave.ID)
+		if err != nil {
+			return err
+		}
+		*leave = *current
+		if leave.Status != models.LeaveStatusPending {
+			return errLeaveNotPending
+		}
+
+		now := time.Now()
+		leave.Status = status
+		leave.ReviewedAt = &now
+		leave.ReviewNote = input.Note
+		if user := middlewares.CurrentUser(c); user != nil {
+			leave.ReviewedBy = &user.UserID
+		}
+		if err := tx.Leaves.Update(leave); err != nil {
+			return err
+		}
+		// Cuti yang di-approve belakangan menggantikan catatan absen di rentangnya
+		if status != models.LeaveStatusApproved {
+			return nil
+		}
+		return tx.Attendances.DeleteAbsences(leave.EmployeeID, leave.StartDate, leave.EndDate)
+	})
+	if errors.Is(err, errLeaveNotPending) {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":  fmt.Sprintf("Leave request is already %s", leave.Status),
+			"code":   CodeLeaveNotPending,
+			"status": leave.Status,
+		})
+		return
+	}
+	if err != nil {
+		fmt.Println("DB error:", err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal Server Error"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": toLeaveResp(*leave)})
+}
+
+// CancelLeave membatalkan cuti pending / approved, oleh pemilik cuti atau HR
+func (ctrl *LeaveController) CancelLeave(c *gin.Context) {
+	leave, ok := ctrl.findLeave(c)
+	if !ok || !ctrl.allowedToRequest(c, leave.EmployeeID) {
+		return
+	}
+
+	// Sama seperti review, status dicek ulang dengan baris cuti dikunci dan hanya kolom
+	// status yang disimpan, supaya approve yang masuk bersamaan tidak tertimpa
+	err := ctrl.transactor.Transaction(func(tx repositories.Repositories) error {
+		current, err := tx.Leaves.LockByID(leave.ID)
+		if err != nil {
+			return err
+		}
+		*leave = *current
+		if leave.Status != models.LeaveStatusPending && leave.Status != models.LeaveStatusApproved {
+			return errLeaveNotCancellable
+		}
+		leave.Status = models.LeaveStatusCancelled
+		return tx.Leaves.UpdateStatus(leave)
+	})
+	if errors.Is(err, errLeaveNotCancellable) {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":  fmt.Sprintf("Leave request is already %s", leave.Status),
+			"code":   CodeLeaveNotCancellable,
+			"status": leave.Status,
+		})
+		return
+	}
+	if err != nil {
+		fmt.Println("DB error:", err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal Server Error"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": toLeaveResp(*leave)})
+}
+
+// GetLeaveBalance jatah cuti employee per jenis cuti, ?year= (default tahun ini)
+func (ctrl *LeaveController) GetLeaveBalance(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Employee not found"})
+		return
+	}
+	employee, err := ctrl.employees.FindByID(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Employee not found"})
+		return
+	}
+	if !ctrl.allowedToView(c, *employee) {
+		return
+	}
+
+	year := time.Now().Year()
+	if yearParam := c.Query("year"); yearParam != "" {
+		year, err = strconv.Atoi(yearParam)
+		if err != nil || year < 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid format for year"})
+			return
+		}
+	}
+
+	types, err := ctrl.leaves.FindTypes()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal Server Error"})
+		return
+	}
+	yearStart, yearEnd := yearRange(year)
+	leaves, err := ctrl.leaves.FindAll(repositories.LeaveFilter{
+		EmployeeID: employee.EmployeeID,
+		Statuses:   activeLeaveStatuses,
+		DateFrom:   yearStart,
+		DateTo:     yearEnd,
+	})
+	if err != nil {
+		fmt.Println("DB error:", err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal Server Error"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{
+		"employee_id": employee.EmployeeID,
+		"year":        year,
+		"balances":    leaveBalances(types, leaves, *employee, year),
+	}})
+}
+
+// findLeave baca :id, kirim 404 kalau tidak ada
+func (ctrl *LeaveController) findLeave(c *gin.Context) (*models.LeaveRequest, bool) {
+	id, ok := parseID(c)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Leave request not found"})
+		return nil, false
+	}
+	leave, err := ctrl.leaves.FindByID(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Leave request not found"})
+		return nil, false
+	}
+	return leave, true
+}
+
+// allowedToRequest: admin & HR untuk siapa saja, selain itu hanya untuk dirinya sendiri
+func (ctrl *LeaveController) allowedToRequest(c *gin.Context, employeeID string) bool {
+	user := middlewares.CurrentUser(c)
+	if user == nil || canPunchForOthers(user.Role) {
+		return true
+	}
+	if user.EmployeeID == "" {
+		middlewares.Forbid(c, middlewares.ReasonNoEmployeeLinked, "Your account is not linked to an employee")
+		return false
+	}
+	if user.EmployeeID != employeeID {
+		middlewares.Forbid(c, middlewares.ReasonNotOwnLeave, "You can only manage your own leave requests")
+		return false
+	}
+	return true
+}
+
+// allowedToView: seperti allowedToRequest, ditambah manager untuk department sendiri
+func (ctrl *LeaveController) allowedToView(c *gin.Context, employee models.Employee) bool {
+	user := middlewares.CurrentUser(c)
+	if user == nil || user.Role != models.RoleManager || user.EmployeeID == employee.EmployeeID {
+		return ctrl.allowedToRequest(c, employee.EmployeeID)
+	}
+	manager, err := ctrl.employees.FindByEmployeeID(user.EmployeeID)
+	if user.EmployeeID == "" || err != nil {
+		middlewares.Forbid(c, middlewares.ReasonNoEmployeeLinked, "Your account is not linked to an employee")
+		return false
+	}
+	if manager.DepartmentID != employee.DepartmentID {
+		middlewares.Forbid(c, middlewares.ReasonNotOwnDepartment, "You can only view leave of your own department")
+		return false
+	}
+	return true
+}
@@ -0,0 +1,103 @@
+package controllers_test
+
+import (
+	"fleetify-backend/models"
+	"net/http"
+	"testing"
+)
+
+// requestLeave cuti tanpa jatah (unpaid) untuk employeeID, mengembalikan id-nya
+func requestLeave(t *testing.T, app *testApp, employeeID, start, end string) string {
+	t.Helper()
+	status, resp := app.do(t, token(t, models.RoleHR, ""), http.MethodPost, "/api/leave",
+		`{"employee_id":"`+employeeID+`","leave_type_id":3,"start_date":"`+start+`","end_date":"`+end+`"}`)
+	expect(t, status, resp, http.StatusOK, "")
+	return jsonID(data(t, resp)["id"])
+}
+
+func TestReviewLeave(t *testing.T) {
+	tests := []struct {
+		name       string
+		role       string
+		employeeID string // employee yang login
+		action     string
+		wantStatus int
+		wantCode   string
+		wantLeave  string // status cuti setelah request
+	}{
+		{name: "hr approves", role: models.RoleHR, employeeID: "EMP-002", action: "approve", wantStatus: http.StatusOK, wantLeave: models.LeaveStatusApproved},
+		{name: "hr rejects", role: models.RoleHR, action: "reject", wantStatus: http.StatusOK, wantLeave: models.LeaveStatusRejected},
+		{name: "hr approves own leave", role: models.RoleHR, employeeID: "EMP-001", action: "approve", wantStatus: http.StatusForbidden, wantCode: "own_leave", wantLeave: models.LeaveStatusPending},
+		{name: "manager cannot approve", role: models.RoleManager, employeeID: "EMP-002", action: "approve", wantStatus: http.StatusForbidden, wantCode: "insufficient_role", wantLeave: models.LeaveStatusPending},
+		{name: "employee cannot approve", role: models.RoleEmployee, employeeID: "EMP-001", action: "approve", wantStatus: http.StatusForbidden, wantCode: "insufficient_role", wantLeave: models.LeaveStatusPending},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := newTestApp(t)
+			id := requestLeave(t, app, "EMP-001", "2026-11-02", "2026-11-03")
+
+			status, resp := app.do(t, token(t, tt.role, tt.employeeID), http.MethodPut, "/api/leave/"+id+"/"+tt.action, `{"note":"ok"}`)
+			expect(t, status, resp, tt.wantStatus, tt.wantCode)
+
+			_, resp = app.do(t, token(t, models.RoleHR, ""), http.MethodGet, "/api/leave/"+id, "")
+			if got := data(t, resp)["status"]; got != tt.wantLeave {
+				t.Fatalf("leave status = %v, want %s", got, tt.wantLeave)
+			}
+		})
+	}
+}
+
+func TestReviewLeaveTwice(t *testing.T) {
+	app := newTestApp(t)
+	hr := token(t, models.RoleHR, "")
+	id := requestLeave(t, app, "EMP-001", "2026-11-02", "2026-11-03")
+
+	status, resp := app.do(t, hr, http.MethodPut, "/api/leave/"+id+"/approve", "")
+	expect(t, status, resp, http.StatusOK, "")
+
+	for _, action := range []string{"approve", "reject"} {
+		status, resp = app.do(t, hr, http.MethodPut, "/api/leave/"+id+"/"+action, "")
+		expect(t, status, resp, http.StatusConflict, "leave_not_pending")
+		if resp["status"] != models.LeaveStatusApproved {
+			t.Fatalf("%s: status = %v, want approved", action, resp["status"])
+		}
+	}
+}
+
+func TestCreateLeaveOverlap(t *testing.T) {
+	app := newTestApp(t)
+	hr := token(t, models.RoleHR, "")
+	id := requestLeave(t, app, "EMP-001", "2026-11-02", "2026-11-04")
+
+	status, resp := app.do(t, hr, http.MethodPost, "/api/leave",
+		`{"employee_id":"EMP-001","leave_type_id":3,"start_date":"2026-11-04","end_date":"2026-11-05"}`)
+	expect(t, status, resp, http.StatusConflict, "leave_overlap")
+	if got := jsonID(resp["leave_id"]); got != id {
+		t.Fatalf("leave_id = %s, want %s", got, id)
+	}
+
+	// Employee lain di tanggal yang sama tidak bentrok
+	requestLeave(t, app, "EMP-002", "2026-11-04", "2026-11-05")
+}
+
+func TestCancelLeave(t *testing.T) {
+	app := newTestApp(t)
+	hr := token(t, models.RoleHR, "")
+	id := requestLeave(t, app, "EMP-001", "2026-11-02", "2026-11-03")
+
+	status, resp := app.do(t, hr, http.MethodPut, "/api/leave/"+id+"/approve", `{"note":"ok"}`)
+	expect(t, status, resp, http.StatusOK, "")
+
+	status, resp = app.do(t, hr, http.MethodPut, "/api/leave/"+id+"/cancel", "")
+	expect(t, status, resp, http.StatusOK, "")
+	// Data review dari approve tetap tersimpan
+	leave := data(t, resp)
+	if leave["status"] != models.LeaveStatusCancelled || leave["review_note"] != "ok" {
+		t.Fatalf("leave = %v, want cancelled with review note", leave)
+	}
+
+	status, resp = app.do(t, hr, http.MethodPut, "/api/leave/"+id+"/cancel", "")
+	expect(t, status, resp, http.StatusConflict, "leave_not_cancellable")
+	status, resp = app.do(t, hr, http.MethodPut, "/api/leave/"+id+"/approve", "")
+	expect(t, status, resp, http.StatusConflict, "leave_not_pending")
+}
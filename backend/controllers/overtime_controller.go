@@ -0,0 +1,454 @@
+package controllers
+
+import (
+	"errors"
+	"fleetify-backend/middlewares"
+	"fleetify-backend/models"
+	"fleetify-backend/repositories"
+	"fleetify-backend/schedule"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type OvertimeController struct {
+	overtimes   repositories.OvertimeRepository
+	attendances repositories.AttendanceRepository
+	employees   repositories.EmployeeRepository
+	holidays    repositories.HolidayRepository
+	transactor  repositories.Transactor
+}
+
+func NewOvertimeController(overtimes repositories.OvertimeRepository, attendances repositories.AttendanceRepository, employees repositories.EmployeeRepository, holidays repositories.HolidayRepository, transactor repositories.Transactor) *OvertimeController {
+	return &OvertimeController{overtimes: overtimes, attendances: attendances, employees: employees, holidays: holidays, transactor: transactor}
+}
+
+// CodeOvertimeNotPending 409, approve / reject lembur yang sudah diproses
+const CodeOvertimeNotPending = "overtime_not_pending"
+
+var errOvertimeNotPending = errors.New("overtime is not pending")
+
+type OvertimeResp struct {
+	ID             uint       `json:"id"`
+	AttendanceID   string     `json:"attendance_id"`
+	EmployeeID     string     `json:"employee_id"`
+	Name           string     `json:"name"`
+	DepartmentID   uint       `json:"department_id"`
+	BusinessDate   string     `json:"business_date"`
+	Type           string     `json:"type"`
+	WorkedMinutes  int        `json:"worked_minutes"`
+	RegularMinutes int        `json:"regular_minutes"`
+	Minutes        int        `json:"minutes"`
+	Status         string     `json:"status"`
+	ReviewedBy     *uint      `json:"reviewed_by"`
+	ReviewedAt     *time.Time `json:"reviewed_at"`
+	ReviewNote     string     `json:"review_note"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+// AttendanceSummaryResp rekap jam kerja satu employee di rentang tanggal (menit).
+// Lembur hanya dihitung yang sudah di-approve, yang masih pending ditampilkan terpisah.
+type AttendanceSummaryResp struct {
+	EmployeeID             string `json:"employee_id"`
+	Name                   string `json:"name"`
+	DepartmentID           uint   `json:"department_id"`
+	Department             string `json:"department"`
+	DaysPresent            int    `json:"days_present"`
+	WorkedMinutes          int    `json:"worked_minutes"` // bersih, tanpa istirahat
+	RegularMinutes         int    `json:"regular_minutes"`
+	BreakMinutes           int    `json:"break_minutes"`
+	BreakShortMinutes      int    `json:"break_short_minutes"` // total kekurangan dari minimal istirahat department
+	OvertimeMinutes        int    `json:"overtime_minutes"`
+	WeekdayOvertimeMinutes int    `json:"weekday_overtime_minutes"`
+	HolidayOvertimeMinutes int    `json:"holiday_overtime_minutes"`
+	PendingOvertimeMinutes int    `json:"pending_overtime_minutes"`
+}
+
+func toOvertimeResp(overtime models.Overtime) OvertimeResp {
+	return OvertimeResp{
+		ID:             overtime.ID,
+		AttendanceID:   overtime.AttendanceID,
+		EmployeeID:     overtime.EmployeeID,
+		Name:           overtime.Employee.Name,
+		DepartmentID:   overtime.Employee.DepartmentID,
+		BusinessDate:   overtime.BusinessDate,
+		Type:           overtime.Type,
+		WorkedMinutes:  overtime.WorkedMinutes,
+		RegularMinutes: overtime.RegularMinutes,
+		Minutes:        overtime.Minutes,
+		Status:         overtime.Status,
+		ReviewedBy:     overtime.ReviewedBy,
+		ReviewedAt:     overtime.ReviewedAt,
+		ReviewNote:     overtime.ReviewNote,
+		CreatedAt:      overtime.CreatedAt,
+		UpdatedAt:      overtime.UpdatedAt,
+	}
+}
+
+// breakTypes history mulai / selesai istirahat
+var breakTypes = []models.AttendanceEventType{models.AttendanceTypeBreakStart, models.AttendanceTypeBreakEnd}
+
+// workedTime jam kerja attendance yang sudah clock out menurut aturan yang berlaku sekarang
+func workedTime(employee models.Employee, attendance models.Attendance, holidays schedule.Calendar, breaks []schedule.Break) schedule.WorkedTime {
+	if attendance.ClockOut == nil {
+		return schedule.WorkedTime{}
+	}
+	businessDate := schedule.BusinessDateOf(attendance)
+	rule := schedule.Resolve(employee, businessDate, holidays)
+	return schedule.Worked(rule, businessDate, attendance.ClockIn, *attendance.ClockOut, breaks)
+}
+
+// breaksByAttendance kelompokkan history istirahat per attendance_id
+func breaksByAttendance(histories []models.AttendanceHistory) map[string][]schedule.Break {
+	grouped := map[string][]models.AttendanceHistory{}
+	for _, history := range histories {
+		grouped[history.AttendanceID] = append(grouped[history.AttendanceID], history)
+	}
+	breaks := map[string][]schedule.Break{}
+	for attendanceID, list := range grouped {
+		breaks[attendanceID] = schedule.Breaks(list)
+	}
+	return breaks
+}
+
+// syncOvertime hitung ulang lembur attendance setelah clock out / koreksi. Jumlah yang
+// berubah kembali pending, lembur yang hilang (di bawah minimum) dihapus.
+func syncOvertime(tx repositories.Repositories, attendance models.Attendance) error {
+	existing, err := tx.Overtimes.FindByAttendanceID(attendance.AttendanceID)
+	if err != nil && !errors.Is(err, repositories.ErrNotFound) {
+		return err
+	}
+
+	employee, err := tx.Employees.WithDeleted().FindByEmployeeID(attendance.EmployeeID)
+	if err != nil {
+		return err
+	}
+	holidays, err := holidayCalendar(tx.Holidays, attendance.BusinessDate, attendance.BusinessDate)
+	if err != nil {
+		return err
+	}
+	histories, err := tx.Attendances.FindHistories(repositories.HistoryFilter{AttendanceID: attendance.AttendanceID, Types: breakTypes})
+	if err != nil {
+		return err
+	}
+	worked := workedTime(*employee, attendance, holidays, schedule.Breaks(histories))
+
+	if worked.OvertimeMinutes == 0 {
+		if existing == nil {
+			return nil
+		}
+		return tx.Overtimes.Delete(existing)
+	}
+
+	if existing == nil {
+		return tx.Overtimes.Create(&models.Overtime{
+			AttendanceID:   attendance.AttendanceID,
+			EmployeeID:     attendance.EmployeeID,
+			BusinessDate:   attendance.BusinessDate,
+			Type:           worked.OvertimeType,
+			WorkedMinutes:  worked.WorkedMinutes,
+			RegularMinutes: worked.RegularMinutes,
+			Minutes:        worked.OvertimeMinutes,
+			Status:         models.OvertimePending,
+		})
+	}
+
+	if existing.Minutes != worked.OvertimeMinutes || existing.Type != worked.OvertimeType {
+		existing.Status = models.OvertimePending
+		existing.ReviewedBy = nil
+		existing.ReviewedAt = nil
+		existing.ReviewNote = ""
+	}
+	existing.BusinessDate = attendance.BusinessDate
+	existing.Type = worked.OvertimeType
+	existing.WorkedMinutes = worked.WorkedMinutes
+	existing.RegularMinutes = worked.RegularMinutes
+	existing.Minutes = worked.OvertimeMinutes
+	return tx.Overtimes.Update(existing)
+}
+
+// GetOvertimes daftar lembur. Employee hanya melihat miliknya sendiri, manager hanya
+// department sendiri.
+func (ctrl *OvertimeController) GetOvertimes(c *gin.Context) {
+	filter := repositories.OvertimeFilter{
+		EmployeeID: c.Query("employee_id"),
+		Type:       c.Query("type"),
+		DateFrom:   c.Query("from"),
+		DateTo:     c.Query("to"),
+	}
+	if status := c.Query("status"); status != "" {
+		filter.Statuses = []string{status}
+	}
+	if filter.Type != "" && filter.Type != models.OvertimeWeekday && filter.Type != models.OvertimeHoliday {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "type must be weekday or holiday"})
+		return
+	}
+	for _, date := range []string{filter.DateFrom, filter.DateTo} {
+		if date == "" {
+			continue
+		}
+		if _, err := time.Parse(schedule.DateLayout, date); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid format for from / to, expected YYYY-MM-DD"})
+			return
+		}
+	}
+
+	scope, ok := ctrl.scope(c, c.Query("department_id"))
+	if !ok {
+		return
+	}
+	if scope.EmployeeID != "" {
+		filter.EmployeeID = scope.EmployeeID
+	}
+	filter.DepartmentID = scope.DepartmentID
+
+	overtimes, err := ctrl.overtimes.FindAll(filter)
+	if err != nil {
+		fmt.Println("DB error:", err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal Server Error"})
+		return
+	}
+	resp := []OvertimeResp{}
+	for _, overtime := range overtimes {
+		resp = append(resp, toOvertimeResp(overtime))
+	}
+	c.JSON(http.StatusOK, gin.H{"data": resp})
+}
+
+// ApproveOvertime (manager department sendiri / HR)
+func (ctrl *OvertimeController) ApproveOvertime(c *gin.Context) {
+	ctrl.review(c, models.OvertimeApproved)
+}
+
+// RejectOvertime (manager department sendiri / HR)
+func (ctrl *OvertimeController) RejectOvertime(c *gin.Context) {
+	ctrl.review(c, models.OvertimeRejected)
+}
+
+// review approve / reject lembur yang masih pending
+func (ctrl *OvertimeController) review(c *gin.Context, status string) {
+	id, ok := parseID(c)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Overtime not found"})
+		return
+	}
+	overtime, err := ctrl.overtimes.FindByID(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Overtime not found"})
+		return
+	}
+	if !allowedToReview(c, ctrl.employees, overtime.Employee, middlewares.ReasonOwnOvertime) {
+		return
+	}
+
+	var input struct {
+		Note string `form:"note" json:"note"`
+	}
+	if err := c.ShouldBind(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input format"})
+		return
+	}
+
+	// Status dicek ulang di dalam transaksi (baris lembur dikunci) supaya dua review
+	// bersamaan tidak saling menimpa
+	err = ctrl.transactor.Transaction(func(tx repositories.Repositories) error {
+		current, err := tx.Overtimes.LockByID(overtime.ID)
+		if err != nil {
+			return err
+		}
+		*overtime = *current
+		if overtime.Status != models.OvertimePending {
+			return errOvertimeNotPending
+		}
+
+		now := time.Now()
+		overtime.Status = status
+		overtime.ReviewedAt = &now
+		overtime.ReviewNote = input.Note
+		if user := middlewares.CurrentUser(c); user != nil {
+			overtime.ReviewedBy = &user.UserID
+		}
+		return tx.Overtimes.Review(overtime)
+	})
+	if errors.Is(err, errOvertimeNotPending) {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":  fmt.Sprintf("Overtime is already %s", overtime.Status),
+			"code":   CodeOvertimeNotPending,
+			"status": overtime.Status,
+		})
+		return
+	}
+	if err != nil {
+		fmt.Println("DB error:", err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal Server Error"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": toOvertimeResp(*overtime)})
+}
+
+// GetAttendanceSummary rekap jam kerja dan lembur per employee,
+// ?from= & ?to= (default awal bulan ini - hari ini), employee_id, department_id
+func (ctrl *OvertimeController) GetAttendanceSummary(c *gin.Context) {
+	now := time.Now()
+	from := c.DefaultQuery("from", now.Format("2006-01")+"-01")
+	to := c.DefaultQuery("to", now.Format(schedule.DateLayout))
+	fromDate, errFrom := time.Parse(schedule.DateLayout, from)
+	toDate, errTo := time.Parse(schedule.DateLayout, to)
+	if errFrom != nil || errTo != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid format for from / to, expected YYYY-MM-DD"})
+		return
+	}
+	if toDate.Before(fromDate) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "to must not be before from"})
+		return
+	}
+
+	filter := repositories.HistoryFilter{DateFrom: from, DateTo: to, EmployeeID: c.Query("employee_id")}
+	scope, ok := ctrl.scope(c, c.Query("department_id"))
+	if !ok {
+		return
+	}
+	if scope.EmployeeID != "" {
+		filter.EmployeeID = scope.EmployeeID
+	}
+	filter.DepartmentID = scope.DepartmentID
+
+	attendances, err := ctrl.attendances.FindAll(filter)
+	if err != nil {
+		fmt.Println("DB error:", err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal Server Error"})
+		return
+	}
+	overtimes, err := ctrl.overtimes.FindAll(repositories.OvertimeFilter{
+		EmployeeID:   filter.EmployeeID,
+		DepartmentID: filter.DepartmentID,
+		Statuses:     []string{models.OvertimePending, models.OvertimeApproved},
+		DateFrom:     from,
+		DateTo:       to,
+	})
+	if err != nil {
+		fmt.Println("DB error:", err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal Server Error"})
+		return
+	}
+	holidays, err := holidayCalendar(ctrl.holidays, from, to)
+	if err != nil {
+		fmt.Println("DB error:", err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal Server Error"})
+		return
+	}
+	breakFilter := filter
+	breakFilter.Types = breakTypes
+	breakHistories, err := ctrl.attendances.FindHistories(breakFilter)
+	if err != nil {
+		fmt.Println("DB error:", err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal Server Error"})
+		return
+	}
+	breaks := breaksByAttendance(breakHistories)
+
+	// Satu baris per employee, urut sesuai attendance pertama
+	summaries := []AttendanceSummaryResp{}
+	index := map[string]int{}
+	row := func(employee models.Employee, employeeID string) *AttendanceSummaryResp {
+		i, ok := index[employeeID]
+		if !ok {
+			i = len(summaries)
+			index[employeeID] = i
+			deptName := employee.Department.DepartmentName
+			if deptName == "" {
+				deptName = "-"
+			}
+			summaries = append(summaries, AttendanceSummaryResp{
+				EmployeeID:   employeeID,
+				Name:         employee.Name,
+				DepartmentID: employee.DepartmentID,
+				Department:   deptName,
+			})
+		}
+		return &summaries[i]
+	}
+
+	presentDays := map[string]bool{}
+	for _, attendance := range attendances {
+		summary := row(attendance.Employee, attendance.EmployeeID)
+		if key := attendance.EmployeeID + "|" + attendance.BusinessDate; !presentDays[key] {
+			presentDays[key] = true
+			summary.DaysPresent++
+		}
+		worked := workedTime(attendance.Employee, attendance, holidays, breaks[attendance.AttendanceID])
+		summary.WorkedMinutes += worked.WorkedMinutes
+		summary.RegularMinutes += worked.RegularMinutes
+		summary.BreakMinutes += worked.BreakMinutes
+		summary.BreakShortMinutes += worked.BreakShortMinutes
+	}
+	for _, overtime := range overtimes {
+		summary := row(overtime.Employee, overtime.EmployeeID)
+		if overtime.Status == models.OvertimePending {
+			summary.PendingOvertimeMinutes += overtime.Minutes
+			continue
+		}
+		summary.OvertimeMinutes += overtime.Minutes
+		if overtime.Type == models.OvertimeHoliday {
+			summary.HolidayOvertimeMinutes += overtime.Minutes
+		} else {
+			summary.WeekdayOvertimeMinutes += overtime.Minutes
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": summaries, "from": from, "to": to})
+}
+
+// overtimeScope batas data yang boleh dilihat user yang login
+type overtimeScope struct {
+	EmployeeID   string
+	DepartmentID *uint
+}
+
+// scope membaca ?department_id= lalu membatasi employee ke dirinya sendiri dan manager
+// ke department sendiri. false kalau response error sudah dikirim.
+func (ctrl *OvertimeController) scope(c *gin.Context, departmentParam string) (overtimeScope, bool) {
+	var scope overtimeScope
+	if departmentParam != "" {
+		departmentID, err := strconv.ParseUint(departmentParam, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid format for department_id"})
+			return scope, false
+		}
+		id := uint(departmentID)
+		scope.DepartmentID = &id
+	}
+
+	user := middlewares.CurrentUser(c)
+	if user == nil {
+		return scope, true
+	}
+	switch user.Role {
+	case models.RoleEmployee:
+		if user.EmployeeID == "" {
+			middlewares.Forbid(c, middlewares.ReasonNoEmployeeLinked, "Your account is not linked to an employee")
+			return scope, false
+		}
+		if employeeID := c.Query("employee_id"); employeeID != "" && employeeID != user.EmployeeID {
+			middlewares.Forbid(c, middlewares.ReasonNotOwnAttendance, "You can only view your own attendance")
+			return scope, false
+		}
+		scope.EmployeeID = user.EmployeeID
+	case models.RoleManager:
+		manager, err := ctrl.employees.FindByEmployeeID(user.EmployeeID)
+		if user.EmployeeID == "" || err != nil {
+			middlewares.Forbid(c, middlewares.ReasonNoEmployeeLinked, "Your account is not linked to an employee")
+			return scope, false
+		}
+		if scope.DepartmentID != nil && *scope.DepartmentID != manager.DepartmentID {
+			middlewares.Forbid(c, middlewares.ReasonNotOwnDepartment, "You can only view attendance of your own department")
+			return scope, false
+		}
+		scope.DepartmentID = &manager.DepartmentID
+	}
+	return scope, true
+}
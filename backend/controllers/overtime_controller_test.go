@@ -0,0 +1,76 @@
+package controllers_test
+
+import (
+	"fleetify-backend/models"
+	"net/http"
+	"testing"
+)
+
+// overtime EMP-001 clock out jam 19:00 (lembur 2 jam), mengembalikan id lemburnya
+func overtime(t *testing.T, app *testApp) string {
+	t.Helper()
+	hr := token(t, models.RoleHR, "")
+	app.do(t, hr, http.MethodPost, "/api/attendance", `{"employee_id":"EMP-001","clock_in":"2026-10-16 08:00:00"}`)
+	status, resp := app.do(t, hr, http.MethodPut, "/api/attendance/ATT-000001", `{"clock_out":"2026-10-16 19:00:00"}`)
+	expect(t, status, resp, http.StatusOK, "")
+
+	overtime, err := app.repos.Overtimes.FindByAttendanceID("ATT-000001")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return jsonID(overtime.ID)
+}
+
+func TestReviewOvertime(t *testing.T) {
+	tests := []struct {
+		name         string
+		role         string
+		employeeID   string // employee yang login
+		action       string
+		wantStatus   int
+		wantCode     string
+		wantOvertime string // status lembur setelah request
+	}{
+		{name: "manager approves", role: models.RoleManager, employeeID: "EMP-002", action: "approve", wantStatus: http.StatusOK, wantOvertime: models.OvertimeApproved},
+		{name: "hr rejects", role: models.RoleHR, action: "reject", wantStatus: http.StatusOK, wantOvertime: models.OvertimeRejected},
+		{name: "manager approves own overtime", role: models.RoleManager, employeeID: "EMP-001", action: "approve", wantStatus: http.StatusForbidden, wantCode: "own_overtime", wantOvertime: models.OvertimePending},
+		{name: "manager of another department", role: models.RoleManager, employeeID: "EMP-003", action: "approve", wantStatus: http.StatusForbidden, wantCode: "not_own_department", wantOvertime: models.OvertimePending},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := newTestApp(t)
+			id := overtime(t, app)
+
+			status, resp := app.do(t, token(t, tt.role, tt.employeeID), http.MethodPut, "/api/overtime/"+id+"/"+tt.action, `{"note":"ok"}`)
+			expect(t, status, resp, tt.wantStatus, tt.wantCode)
+
+			stored, err := app.repos.Overtimes.FindByAttendanceID("ATT-000001")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if stored.Status != tt.wantOvertime {
+				t.Fatalf("overtime status = %s, want %s", stored.Status, tt.wantOvertime)
+			}
+			if stored.Minutes != 120 {
+				t.Fatalf("overtime minutes = %d, want 120", stored.Minutes)
+			}
+		})
+	}
+}
+
+func TestReviewOvertimeTwice(t *testing.T) {
+	app := newTestApp(t)
+	hr := token(t, models.RoleHR, "")
+	id := overtime(t, app)
+
+	status, resp := app.do(t, hr, http.MethodPut, "/api/overtime/"+id+"/approve", "")
+	expect(t, status, resp, http.StatusOK, "")
+
+	for _, action := range []string{"approve", "reject"} {
+		status, resp = app.do(t, hr, http.MethodPut, "/api/overtime/"+id+"/"+action, "")
+		expect(t, status, resp, http.StatusConflict, "overtime_not_pending")
+		if resp["status"] != models.OvertimeApproved {
+			t.Fatalf("%s: status = %v, want approved", action, resp["status"])
+		}
+	}
+}
@@ -0,0 +1,238 @@
+package controllers
+
+import (
+	"fleetify-backend/models"
+	"fleetify-backend/repositories"
+	"fleetify-backend/schedule"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ShiftController struct {
+	shifts repositories.ShiftRepository
+}
+
+func NewShiftController(shifts repositories.ShiftRepository) *ShiftController {
+	return &ShiftController{shifts: shifts}
+}
+
+// Input untuk shift (JSON)
+type ShiftDayInput struct {
+	Weekday      int    `json:"weekday"` // 0=Minggu ... 6=Sabtu
+	IsWorkingDay bool   `json:"is_working_day"`
+	StartTime    string `json:"start_time"` // HH:mm
+	EndTime      string `json:"end_time"`   // HH:mm
+}
+
+type ShiftFormInput struct {
+	Name string          `json:"name"`
+	Days []ShiftDayInput `json:"days"`
+}
+
+// Response struct untuk shift
+type ShiftDayResp struct {
+	Weekday      int     `json:"weekday"`
+	WeekdayName  string  `json:"weekday_name"`
+	IsWorkingDay bool    `json:"is_working_day"`
+	StartTime    *string `json:"start_time"`
+	EndTime      *string `json:"end_time"`
+	Overnight    bool    `json:"overnight"`
+}
+
+type ShiftResp struct {
+	ID        uint           `json:"id"`
+	Name      string         `json:"name"`
+	Days      []ShiftDayResp `json:"days"`
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+}
+
+// toShiftResp selalu mengembalikan 7 hari, hari yang tidak diatur dianggap libur
+func toShiftResp(shift models.Shift) ShiftResp {
+	days := make([]ShiftDayResp, 0, 7)
+	for weekday := time.Sunday; weekday <= time.Saturday; weekday++ {
+		resp := ShiftDayResp{Weekday: int(weekday), WeekdayName: weekday.String()}
+		if day, ok := shift.Day(weekday); ok {
+			resp.IsWorkingDay = day.IsWorkingDay
+			resp.StartTime = day.StartTime
+			resp.EndTime = day.EndTime
+			if day.StartTime != nil && day.EndTime != nil {
+				resp.Overnight = schedule.IsOvernight(*day.StartTime, *day.EndTime)
+			}
+		}
+		days = append(days, resp)
+	}
+	return ShiftResp{
+		ID:        shift.ID,
+		Name:      shift.Name,
+		Days:      days,
+		CreatedAt: shift.CreatedAt,
+		UpdatedAt: shift.UpdatedAt,
+	}
+}
+
+// GetAllShifts
+func (ctrl *ShiftController) GetAllShifts(c *gin.Context) {
+	shifts, err := ctrl.shifts.FindAll()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal Server Error"})
+		return
+	}
+	resp := []ShiftResp{}
+	for _, shift := range shifts {
+		resp = append(resp, toShiftResp(shift))
+	}
+	c.JSON(http.StatusOK, gin.H{"data": resp})
+}
+
+// GetShiftDetail
+func (ctrl *ShiftController) GetShiftDetail(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Shift not found"})
+		return
+	}
+	shift, err := ctrl.shifts.FindByID(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Shift not found"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": toShiftResp(*shift)})
+}
+
+// CreateShift
+func (ctrl *ShiftController) CreateShift(c *gin.Context) {
+	var input ShiftFormInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input format"})
+		return
+	}
+
+	days, msg := parseShiftInput(input)
+	if msg != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": msg})
+		return
+	}
+
+	shift := models.Shift{Name: input.Name, Days: days}
+	if err := ctrl.shifts.Create(&shift); err != nil {
+		fmt.Println("DB error:", err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal Server Error"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": toShiftResp(shift)})
+}
+
+// UpdateShift
+func (ctrl *ShiftController) UpdateShift(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Shift not found"})
+		return
+	}
+	shift, err := ctrl.shifts.FindByID(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Shift not found"})
+		return
+	}
+
+	var input ShiftFormInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input format"})
+		return
+	}
+
+	days, msg := parseShiftInput(input)
+	if msg != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": msg})
+		return
+	}
+
+	shift.Name = input.Name
+	shift.Days = days
+	if err := ctrl.shifts.Update(shift); err != nil {
+		fmt.Println("DB error:", err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal Server Error"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": toShiftResp(*shift)})
+}
+
+// DeleteShift, ditolak kalau shift masih dipakai
+func (ctrl *ShiftController) DeleteShift(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Shift not found"})
+		return
+	}
+	shift, err := ctrl.shifts.FindByID(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Shift not found"})
+		return
+	}
+
+	departments, employees, err := ctrl.shifts.CountAssignments(shift.ID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal Server Error"})
+		return
+	}
+	if departments > 0 || employees > 0 {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":       "Shift is still assigned",
+			"code":        "shift_in_use",
+			"departments": departments,
+			"employees":   employees,
+		})
+		return
+	}
+
+	if err := ctrl.shifts.Delete(shift); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to delete shift"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Shift deleted successfully"})
+}
+
+// parseShiftInput validasi input dan mengubahnya menjadi ShiftDay
+func parseShiftInput(input ShiftFormInput) ([]models.ShiftDay, string) {
+	if input.Name == "" {
+		return nil, "Shift Name is required"
+	}
+
+	seen := map[int]bool{}
+	var days []models.ShiftDay
+	for _, d := range input.Days {
+		if d.Weekday < 0 || d.Weekday > 6 {
+			return nil, "weekday must be between 0 (Sunday) and 6 (Saturday)"
+		}
+		if seen[d.Weekday] {
+			return nil, fmt.Sprintf("weekday %d is defined more than once", d.Weekday)
+		}
+		seen[d.Weekday] = true
+
+		day := models.ShiftDay{Weekday: d.Weekday, IsWorkingDay: d.IsWorkingDay}
+		if d.IsWorkingDay {
+			start, errStart := time.Parse("15:04", d.StartTime)
+			end, errEnd := time.Parse("15:04", d.EndTime)
+			if errStart != nil {
+				return nil, fmt.Sprintf("invalid format for start_time on weekday %d, expected HH:mm", d.Weekday)
+			}
+			if errEnd != nil {
+				return nil, fmt.Sprintf("invalid format for end_time on weekday %d, expected HH:mm", d.Weekday)
+			}
+			// end_time lebih kecil dari start_time = shift malam, selesai di hari berikutnya
+			if end.Equal(start) {
+				return nil, fmt.Sprintf("end_time must differ from start_time on weekday %d", d.Weekday)
+			}
+			startStr := start.Format("15:04:05")
+			endStr := end.Format("15:04:05")
+			day.StartTime = &startStr
+			day.EndTime = &endStr
+		}
+		days = append(days, day)
+	}
+	return days, ""
+}
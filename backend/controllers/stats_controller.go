@@ -0,0 +1,171 @@
+package controllers
+
+import (
+	"fleetify-backend/middlewares"
+	"fleetify-backend/models"
+	"fleetify-backend/repositories"
+	"fleetify-backend/schedule"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type StatsController struct {
+	stats     repositories.StatsRepository
+	employees repositories.EmployeeRepository
+}
+
+func NewStatsController(stats repositories.StatsRepository, employees repositories.EmployeeRepository) *StatsController {
+	return &StatsController{stats: stats, employees: employees}
+}
+
+// Batas query statistik
+const (
+	statsMaxDays      = 366 // rentang from - to
+	statsDefaultLimit = 5   // jumlah employee paling sering telat
+	statsMaxLimit     = 50
+)
+
+// AttendanceStatsTotals total hadir / telat / absen di rentang tanggal (hitungan employee per hari)
+type AttendanceStatsTotals struct {
+	Present int `json:"present"`
+	Late    int `json:"late"`
+	Absent  int `json:"absent"`
+}
+
+// GetAttendanceStats ringkasan absensi untuk dashboard: headcount, hadir / telat / absen per hari,
+// persentase tepat waktu, rata-rata jam clock in dan employee yang paling sering telat.
+// ?from= & ?to= (default awal bulan ini - hari ini), ?department_id=, ?limit= (default 5)
+func (ctrl *StatsController) GetAttendanceStats(c *gin.Context) {
+	now := time.Now()
+	from := c.DefaultQuery("from", now.Format("2006-01")+"-01")
+	to := c.DefaultQuery("to", now.Format(schedule.DateLayout))
+	fromDate, errFrom := time.Parse(schedule.DateLayout, from)
+	toDate, errTo := time.Parse(schedule.DateLayout, to)
+	if errFrom != nil || errTo != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid format for from / to, expected YYYY-MM-DD"})
+		return
+	}
+	if toDate.Before(fromDate) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "to must not be before from"})
+		return
+	}
+	if toDate.Sub(fromDate) >= statsMaxDays*24*time.Hour {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("date range must not exceed %d days", statsMaxDays)})
+		return
+	}
+
+	limit := statsDefaultLimit
+	if limitParam := c.Query("limit"); limitParam != "" {
+		n, err := strconv.Atoi(limitParam)
+		if err != nil || n < 1 || n > statsMaxLimit {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("limit must be between 1 and %d", statsMaxLimit)})
+			return
+		}
+		limit = n
+	}
+
+	filter := repositories.StatsFilter{DateFrom: from, DateTo: to}
+	if departmentParam := c.Query("department_id"); departmentParam != "" {
+		departmentID, err := strconv.ParseUint(departmentParam, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid format for department_id"})
+			return
+		}
+		id := uint(departmentID)
+		filter.DepartmentID = &id
+	}
+
+	// Manager hanya boleh melihat statistik department sendiri
+	if user := middlewares.CurrentUser(c); user != nil && user.Role == models.RoleManager {
+		manager, err := ctrl.employees.FindByEmployeeID(user.EmployeeID)
+		if user.EmployeeID == "" || err != nil {
+			middlewares.Forbid(c, middlewares.ReasonNoEmployeeLinked, "Your account is not linked to an employee")
+			return
+		}
+		if filter.DepartmentID != nil && *filter.DepartmentID != manager.DepartmentID {
+			middlewares.Forbid(c, middlewares.ReasonNotOwnDepartment, "You can only view statistics of your own department")
+			return
+		}
+		filter.DepartmentID = &manager.DepartmentID
+	}
+
+	// Headcount di akhir hari terakhir rentang
+	headcount, err := ctrl.stats.Headcount(filter.DepartmentID, toDate.AddDate(0, 0, 1))
+	if err != nil {
+		fmt.Println("DB error:", err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal Server Error"})
+		return
+	}
+	daily, err := ctrl.stats.Daily(filter)
+	if err != nil {
+		fmt.Println("DB error:", err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal Server Error"})
+		return
+	}
+	averageSeconds, clockIns, err := ctrl.stats.AverageClockIn(filter)
+	if err != nil {
+		fmt.Println("DB error:", err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal Server Error"})
+		return
+	}
+	topLate, err := ctrl.stats.TopLate(filter, limit)
+	if err != nil {
+		fmt.Println("DB error:", err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal Server Error"})
+		return
+	}
+
+	// Satu baris untuk setiap tanggal, termasuk yang kosong, supaya mudah dibuat grafik
+	byDate := map[string]repositories.DailyAttendanceStats{}
+	for _, day := range daily {
+		byDate[day.Date] = day
+	}
+	days := []repositories.DailyAttendanceStats{}
+	var totals AttendanceStatsTotals
+	onTime, rated := 0, 0
+	for date := fromDate; !date.After(toDate); date = date.AddDate(0, 0, 1) {
+		day, ok := byDate[date.Format(schedule.DateLayout)]
+		if !ok {
+			day = repositories.DailyAttendanceStats{Date: date.Format(schedule.DateLayout)}
+		}
+		totals.Present += day.Present
+		totals.Late += day.Late
+		totals.Absent += day.Absent
+		onTime += day.OnTime
+		rated += day.Rated
+		days = append(days, day)
+	}
+
+	// Persentase dari clock in yang dinilai (tanpa hari libur / di luar shift)
+	var onTimePercentage *float64
+	if rated > 0 {
+		percentage := math.Round(float64(onTime)/float64(rated)*1000) / 10
+		onTimePercentage = &percentage
+	}
+	var averageClockIn *string
+	if clockIns > 0 {
+		seconds := int(math.Round(averageSeconds))
+		formatted := fmt.Sprintf("%02d:%02d:%02d", seconds/3600, seconds%3600/60, seconds%60)
+		averageClockIn = &formatted
+	}
+	if topLate == nil {
+		topLate = []repositories.LateEmployeeStats{}
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{
+		"from":               from,
+		"to":                 to,
+		"department_id":      filter.DepartmentID,
+		"headcount":          headcount,
+		"daily":              days,
+		"totals":             totals,
+		"on_time_percentage": onTimePercentage,
+		"average_clock_in":   averageClockIn,
+		"top_late":           topLate,
+	}})
+}
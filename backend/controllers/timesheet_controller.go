@@ -0,0 +1,297 @@
+package controllers
+
+import (
+	"fleetify-backend/middlewares"
+	"fleetify-backend/models"
+	"fleetify-backend/repositories"
+	"fleetify-backend/schedule"
+	"fmt"
+	"math"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type TimesheetController struct {
+	attendances repositories.AttendanceRepository
+	employees   repositories.EmployeeRepository
+	leaves      repositories.LeaveRepository
+	holidays    repositories.HolidayRepository
+	overtimes   repositories.OvertimeRepository
+}
+
+func NewTimesheetController(attendances repositories.AttendanceRepository, employees repositories.EmployeeRepository, leaves repositories.LeaveRepository, holidays repositories.HolidayRepository, overtimes repositories.OvertimeRepository) *TimesheetController {
+	return &TimesheetController{attendances: attendances, employees: employees, leaves: leaves, holidays: holidays, overtimes: overtimes}
+}
+
+// Status satu hari di timesheet
+const (
+	TimesheetPresent  = "present"
+	TimesheetLate     = "late"
+	TimesheetAbsent   = "absent"
+	TimesheetLeave    = "leave"
+	TimesheetHoliday  = "holiday"
+	TimesheetWeekend  = "weekend"  // bukan hari kerja menurut shift
+	TimesheetUpcoming = "upcoming" // hari kerja mulai hari ini yang belum ada attendance
+	TimesheetInactive = "inactive" // sebelum bergabung / setelah berhenti
+)
+
+// TimesheetDayResp satu baris timesheet per tanggal (menit, jam clock in / out "2006-01-02 15:04:05")
+type TimesheetDayResp struct {
+	Date                   string  `json:"date"`
+	Weekday                string  `json:"weekday"`
+	Status                 string  `json:"status"`
+	Holiday                string  `json:"holiday,omitempty"`
+	LeaveType              string  `json:"leave_type,omitempty"`
+	FirstClockIn           *string `json:"first_clock_in"`
+	LastClockOut           *string `json:"last_clock_out"`
+	WorkedMinutes          int     `json:"worked_minutes"` // bersih, tanpa istirahat
+	WorkedHours            float64 `json:"worked_hours"`
+	BreakMinutes           int     `json:"break_minutes"`
+	OvertimeMinutes        int     `json:"overtime_minutes"` // hanya yang approved
+	PendingOvertimeMinutes int     `json:"pending_overtime_minutes"`
+	LateMinutes            int     `json:"late_minutes"`
+}
+
+// TimesheetTotalsResp total satu bulan, days_present termasuk hari yang telat
+type TimesheetTotalsResp struct {
+	DaysPresent            int     `json:"days_present"`
+	DaysLate               int     `json:"days_late"`
+	DaysAbsent             int     `json:"days_absent"`
+	DaysLeave              int     `json:"days_leave"`
+	DaysHoliday            int     `json:"days_holiday"`
+	DaysWeekend            int     `json:"days_weekend"`
+	WorkedMinutes          int     `json:"worked_minutes"`
+	WorkedHours            float64 `json:"worked_hours"`
+	BreakMinutes           int     `json:"break_minutes"`
+	OvertimeMinutes        int     `json:"overtime_minutes"`
+	PendingOvertimeMinutes int     `json:"pending_overtime_minutes"`
+	LateMinutes            int     `json:"late_minutes"`
+}
+
+// lateStatuses status clock in yang dihitung telat di timesheet
+var lateStatuses = map[string]bool{
+	schedule.StatusSlightlyLate: true,
+	schedule.StatusLate:         true,
+	schedule.StatusVeryLate:     true,
+}
+
+// GetTimesheet satu baris per tanggal di bulan ?month=YYYY-MM (default bulan ini) untuk satu employee
+func (ctrl *TimesheetController) GetTimesheet(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Employee not found"})
+		return
+	}
+	employee, err := ctrl.employees.FindByID(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Employee not found"})
+		return
+	}
+	if !ctrl.allowedToView(c, *employee) {
+		return
+	}
+
+	now := time.Now()
+	month := c.DefaultQuery("month", now.Format("2006-01"))
+	monthStart, err := time.Parse("2006-01", month)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid format for month, expected YYYY-MM"})
+		return
+	}
+	monthEnd := monthStart.AddDate(0, 1, -1)
+	from, to := monthStart.Format(schedule.DateLayout), monthEnd.Format(schedule.DateLayout)
+
+	filter := repositories.HistoryFilter{EmployeeID: employee.EmployeeID, DateFrom: from, DateTo: to}
+	attendances, err := ctrl.attendances.FindAll(filter)
+	if err != nil {
+		fmt.Println("DB error:", err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal Server Error"})
+		return
+	}
+	historyFilter := filter
+	historyFilter.Types = append([]models.AttendanceEventType{models.AttendanceTypeClockIn}, breakTypes...)
+	histories, err := ctrl.attendances.FindHistories(historyFilter)
+	if err != nil {
+		fmt.Println("DB error:", err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal Server Error"})
+		return
+	}
+	leaves, err := ctrl.leaves.FindAll(repositories.LeaveFilter{
+		EmployeeID: employee.EmployeeID,
+		Statuses:   []string{models.LeaveStatusApproved},
+		DateFrom:   from,
+		DateTo:     to,
+	})
+	if err != nil {
+		fmt.Println("DB error:", err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal Server Error"})
+		return
+	}
+	overtimes, err := ctrl.overtimes.FindAll(repositories.OvertimeFilter{
+		EmployeeID: employee.EmployeeID,
+		Statuses:   []string{models.OvertimePending, models.OvertimeApproved},
+		DateFrom:   from,
+		DateTo:     to,
+	})
+	if err != nil {
+		fmt.Println("DB error:", err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal Server Error"})
+		return
+	}
+	holidays, err := holidayCalendar(ctrl.holidays, from, to)
+	if err != nil {
+		fmt.Println("DB error:", err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal Server Error"})
+		return
+	}
+
+	// Kelompokkan per tanggal bisnis
+	byDate := map[string][]models.Attendance{}
+	for _, attendance := range attendances {
+		byDate[attendance.BusinessDate] = append(byDate[attendance.BusinessDate], attendance)
+	}
+	var breakHistories []models.AttendanceHistory
+	clockIns := map[string]models.AttendanceHistory{}
+	for _, history := range histories {
+		if history.AttendanceType == models.AttendanceTypeClockIn {
+			clockIns[history.AttendanceID] = history
+			continue
+		}
+		breakHistories = append(breakHistories, history)
+	}
+	breaks := breaksByAttendance(breakHistories)
+	overtimeByDate := map[string][]models.Overtime{}
+	for _, overtime := range overtimes {
+		overtimeByDate[overtime.BusinessDate] = append(overtimeByDate[overtime.BusinessDate], overtime)
+	}
+
+	today := now.Format(schedule.DateLayout)
+	days := []TimesheetDayResp{}
+	var totals TimesheetTotalsResp
+	for date := monthStart; !date.After(monthEnd); date = date.AddDate(0, 0, 1) {
+		day := TimesheetDayResp{Date: date.Format(schedule.DateLayout), Weekday: date.Weekday().String()}
+		rule := schedule.Resolve(*employee, date, holidays)
+		day.Holiday = rule.Holiday
+
+		for i, attendance := range byDate[day.Date] {
+			clockIn := attendance.ClockIn.Format("2006-01-02 15:04:05")
+			if day.FirstClockIn == nil || clockIn < *day.FirstClockIn {
+				day.FirstClockIn = &clockIn
+			}
+			if attendance.ClockOut != nil {
+				clockOut := attendance.ClockOut.Format("2006-01-02 15:04:05")
+				if day.LastClockOut == nil || clockOut > *day.LastClockOut {
+					day.LastClockOut = &clockOut
+				}
+			}
+			worked := workedTime(*employee, attendance, holidays, breaks[attendance.AttendanceID])
+			day.WorkedMinutes += worked.WorkedMinutes
+			day.BreakMinutes += worked.BreakMinutes
+
+			// Telat dihitung dari clock in pertama di hari itu
+			if i == 0 {
+				if history, ok := clockIns[attendance.AttendanceID]; ok {
+					if history.Status == "" {
+						schedule.Apply(&history, *employee, attendance, holidays)
+					}
+					if lateStatuses[history.Status] {
+						day.LateMinutes = history.MinutesLate
+					}
+				}
+			}
+		}
+		for _, overtime := range overtimeByDate[day.Date] {
+			if overtime.Status == models.OvertimePending {
+				day.PendingOvertimeMinutes += overtime.Minutes
+				continue
+			}
+			day.OvertimeMinutes += overtime.Minutes
+		}
+		day.WorkedHours = minutesToHours(day.WorkedMinutes)
+
+		switch {
+		case len(byDate[day.Date]) > 0 && day.LateMinutes > 0:
+			day.Status = TimesheetLate
+		case len(byDate[day.Date]) > 0:
+			day.Status = TimesheetPresent
+		case rule.Holiday != "":
+			day.Status = TimesheetHoliday
+		case !rule.WorkingDay:
+			day.Status = TimesheetWeekend
+		case employee.CreatedAt.Format(schedule.DateLayout) > day.Date || !employee.IsActiveAt(date):
+			day.Status = TimesheetInactive
+		default:
+			for _, leave := range leaves {
+				if leave.Covers(day.Date) {
+					day.Status = TimesheetLeave
+					day.LeaveType = leave.LeaveType.Name
+					break
+				}
+			}
+			if day.Status == "" && day.Date < today {
+				day.Status = TimesheetAbsent
+			} else if day.Status == "" {
+				day.Status = TimesheetUpcoming
+			}
+		}
+
+		switch day.Status {
+		case TimesheetLate:
+			totals.DaysLate++
+			totals.DaysPresent++
+		case TimesheetPresent:
+			totals.DaysPresent++
+		case TimesheetAbsent:
+			totals.DaysAbsent++
+		case TimesheetLeave:
+			totals.DaysLeave++
+		case TimesheetHoliday:
+			totals.DaysHoliday++
+		case TimesheetWeekend:
+			totals.DaysWeekend++
+		}
+		totals.WorkedMinutes += day.WorkedMinutes
+		totals.BreakMinutes += day.BreakMinutes
+		totals.OvertimeMinutes += day.OvertimeMinutes
+		totals.PendingOvertimeMinutes += day.PendingOvertimeMinutes
+		totals.LateMinutes += day.LateMinutes
+		days = append(days, day)
+	}
+	totals.WorkedHours = minutesToHours(totals.WorkedMinutes)
+
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{
+		"employee_id": employee.EmployeeID,
+		"name":        employee.Name,
+		"department":  employee.Department.DepartmentName,
+		"month":       month,
+		"days":        days,
+		"totals":      totals,
+	}})
+}
+
+// allowedToView: employee untuk timesheet sendiri, manager untuk department sendiri, HR & admin semua
+func (ctrl *TimesheetController) allowedToView(c *gin.Context, employee models.Employee) bool {
+	user := middlewares.CurrentUser(c)
+	if user == nil || canPunchForOthers(user.Role) {
+		return true
+	}
+	if user.Role == models.RoleManager && user.EmployeeID != employee.EmployeeID {
+		return managesDepartment(c, ctrl.employees, employee)
+	}
+	if user.EmployeeID == "" {
+		middlewares.Forbid(c, middlewares.ReasonNoEmployeeLinked, "Your account is not linked to an employee")
+		return false
+	}
+	if user.EmployeeID != employee.EmployeeID {
+		middlewares.Forbid(c, middlewares.ReasonNotOwnAttendance, "You can only view your own timesheet")
+		return false
+	}
+	return true
+}
+
+// minutesToHours jam dengan 2 angka desimal
+func minutesToHours(minutes int) float64 {
+	return math.Round(float64(minutes)/60*100) / 100
+}
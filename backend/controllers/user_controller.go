@@ -0,0 +1,177 @@
+package controllers
+
+import (
+	"fleetify-backend/auth"
+	"fleetify-backend/models"
+	"fleetify-backend/repositories"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type UserController struct {
+	users     repositories.UserRepository
+	employees repositories.EmployeeRepository
+}
+
+func NewUserController(users repositories.UserRepository, employees repositories.EmployeeRepository) *UserController {
+	return &UserController{users: users, employees: employees}
+}
+
+type UserResp struct {
+	ID         uint      `json:"id"`
+	Username   string    `json:"username"`
+	Role       string    `json:"role"`
+	EmployeeID *string   `json:"employee_id"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+func toUserResp(user models.User) UserResp {
+	return UserResp{
+		ID:         user.ID,
+		Username:   user.Username,
+		Role:       user.Role,
+		EmployeeID: user.EmployeeID,
+		CreatedAt:  user.CreatedAt,
+		UpdatedAt:  user.UpdatedAt,
+	}
+}
+
+type UserFormInput struct {
+	Username   string `form:"username" json:"username"`
+	Password   string `form:"password" json:"password"`
+	Role       string `form:"role" json:"role"`
+	EmployeeID string `form:"employee_id" json:"employee_id"`
+}
+
+// GetAllUsers
+func (ctrl *UserController) GetAllUsers(c *gin.Context) {
+	users, err := ctrl.users.FindAll()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal Server Error"})
+		return
+	}
+	var resp []UserResp
+	for _, user := range users {
+		resp = append(resp, toUserResp(user))
+	}
+	c.JSON(http.StatusOK, gin.H{"data": resp})
+}
+
+// CreateUser
+func (ctrl *UserController) CreateUser(c *gin.Context) {
+	var input UserFormInput
+	if err := c.ShouldBind(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input format"})
+		return
+	}
+
+	// Custom validation
+	if input.Username == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Username is required"})
+		return
+	}
+	if input.Password == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Password is required"})
+		return
+	}
+	if !models.IsValidRole(input.Role) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Role must be one of admin, hr, manager, employee"})
+		return
+	}
+	if msg := ctrl.validateUserEmployee(input.Role, input.EmployeeID); msg != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": msg})
+		return
+	}
+
+	hash, err := auth.HashPassword(input.Password)
+	if err != nil {
+		fmt.Println("Hash error:", err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal Server Error"})
+		return
+	}
+
+	user := models.User{
+		Username:     input.Username,
+		PasswordHash: hash,
+		Role:         input.Role,
+	}
+	if input.EmployeeID != "" {
+		user.EmployeeID = &input.EmployeeID
+	}
+	if err := ctrl.users.Create(&user); err != nil {
+		fmt.Println("DB error:", err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal Server Error"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": toUserResp(user)})
+}
+
+// UpdateUser
+func (ctrl *UserController) UpdateUser(c *gin.Context) {
+	id, ok := parseID(c)
+	if !ok {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+	user, err := ctrl.users.FindByID(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "User not found"})
+		return
+	}
+
+	var input UserFormInput
+	if err := c.ShouldBind(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid input format"})
+		return
+	}
+
+	// Custom validation
+	if !models.IsValidRole(input.Role) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Role must be one of admin, hr, manager, employee"})
+		return
+	}
+	if msg := ctrl.validateUserEmployee(input.Role, input.EmployeeID); msg != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": msg})
+		return
+	}
+
+	user.Role = input.Role
+	user.EmployeeID = nil
+	if input.EmployeeID != "" {
+		user.EmployeeID = &input.EmployeeID
+	}
+	if input.Password != "" {
+		hash, err := auth.HashPassword(input.Password)
+		if err != nil {
+			fmt.Println("Hash error:", err.Error())
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal Server Error"})
+			return
+		}
+		user.PasswordHash = hash
+	}
+
+	if err := ctrl.users.Update(user); err != nil {
+		fmt.Println("DB error:", err.Error())
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Internal Server Error"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": toUserResp(*user)})
+}
+
+// validateUserEmployee: manager dan employee wajib terhubung ke employee yang ada
+func (ctrl *UserController) validateUserEmployee(role string, employeeID string) string {
+	if employeeID == "" {
+		if role == models.RoleManager || role == models.RoleEmployee {
+			return "Employee is required for manager and employee roles"
+		}
+		return ""
+	}
+	if _, err := ctrl.employees.FindByEmployeeID(employeeID); err != nil {
+		return "Employee not found"
+	}
+	return ""
+}
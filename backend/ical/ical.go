@@ -0,0 +1,160 @@
+package ical
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// MaxEventDays batas jumlah hari satu event, supaya DTEND yang salah tulis
+// (misalnya tahun 2206) tidak menghasilkan ribuan hari libur
+const MaxEventDays = 31
+
+// Event satu VEVENT dari file iCalendar. Start / End dalam tanggal lokal,
+// End inklusif (DTEND event seharian di file .ics bersifat eksklusif).
+type Event struct {
+	UID     string
+	Summary string
+	Start   time.Time
+	End     time.Time
+}
+
+// Dates semua tanggal yang dicakup event
+func (e Event) Dates() []time.Time {
+	var dates []time.Time
+	for date := e.Start; !date.After(e.End); date = date.AddDate(0, 0, 1) {
+		dates = append(dates, date)
+	}
+	return dates
+}
+
+// Parse membaca VEVENT dari file .ics. Hanya DTSTART, DTEND, SUMMARY dan UID
+// yang dipakai; RRULE (event berulang) tidak didukung. Event yang berakhir sebelum
+// mulai atau lebih dari MaxEventDays hari ditolak.
+func Parse(r io.Reader) ([]Event, error) {
+	lines, err := unfold(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var events []Event
+	var current *Event
+	var endExclusive bool
+	for i, line := range lines {
+		name, params, value := splitLine(line)
+		switch {
+		case name == "BEGIN" && value == "VEVENT":
+			current = &Event{}
+			endExclusive = false
+		case name == "END" && value == "VEVENT":
+			if current == nil {
+				return nil, fmt.Errorf("line %d: END:VEVENT without BEGIN", i+1)
+			}
+			if current.Start.IsZero() {
+				return nil, fmt.Errorf("event %q has no DTSTART", current.Summary)
+			}
+			switch {
+			case current.End.IsZero():
+				current.End = current.Start
+			case endExclusive && current.End.After(current.Start):
+				current.End = current.End.AddDate(0, 0, -1)
+			}
+			if current.End.Before(current.Start) {
+				return nil, fmt.Errorf("event %q ends before it starts", current.Summary)
+			}
+			if current.End.Sub(current.Start) >= MaxEventDays*24*time.Hour {
+				return nil, fmt.Errorf("event %q spans more than %d days", current.Summary, MaxEventDays)
+			}
+			events = append(events, *current)
+			current = nil
+		case current == nil:
+			continue
+		case name == "SUMMARY":
+			current.Summary = unescape(value)
+		case name == "UID":
+			current.UID = value
+		case name == "DTSTART", name == "DTEND":
+			date, allDay, err := parseDate(params, value)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", i+1, err)
+			}
+			if name == "DTSTART" {
+				current.Start = date
+			} else {
+				current.End = date
+				endExclusive = allDay
+			}
+		}
+	}
+	if current != nil {
+		return nil, fmt.Errorf("event %q is not closed with END:VEVENT", current.Summary)
+	}
+	return events, nil
+}
+
+// unfold menggabungkan baris lanjutan (diawali spasi / tab) sesuai RFC 5545
+func unfold(r io.Reader) ([]string, error) {
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines, scanner.Err()
+}
+
+// splitLine memecah "NAME;PARAM=X:VALUE"
+func splitLine(line string) (name string, params map[string]string, value string) {
+	head, value, _ := strings.Cut(line, ":")
+	parts := strings.Split(head, ";")
+	params = map[string]string{}
+	for _, param := range parts[1:] {
+		key, val, _ := strings.Cut(param, "=")
+		params[strings.ToUpper(key)] = val
+	}
+	return strings.ToUpper(parts[0]), params, value
+}
+
+// parseDate menerima DATE (20261225) dan DATE-TIME (20261225T090000). DATE-TIME
+// berakhiran Z dibaca sebagai UTC, dengan TZID di zona itu, tanpa keduanya di zona
+// server. Hasilnya tanggal di zona server (time.Local), jamnya diabaikan.
+func parseDate(params map[string]string, value string) (time.Time, bool, error) {
+	if params["VALUE"] == "DATE" || len(value) == 8 {
+		date, err := time.Parse("20060102", value)
+		if err != nil {
+			return time.Time{}, false, fmt.Errorf("invalid date %q", value)
+		}
+		return date, true, nil
+	}
+
+	loc := time.Local
+	if utc, found := strings.CutSuffix(value, "Z"); found {
+		value, loc = utc, time.UTC
+	} else if tzid := strings.Trim(params["TZID"], `"`); tzid != "" {
+		var err error
+		if loc, err = time.LoadLocation(tzid); err != nil {
+			return time.Time{}, false, fmt.Errorf("unknown TZID %q", tzid)
+		}
+	}
+	t, err := time.ParseInLocation("20060102T150405", value, loc)
+	if err != nil {
+		return time.Time{}, false, fmt.Errorf("invalid date-time %q", value)
+	}
+	t = t.In(time.Local)
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC), false, nil
+}
+
+var unescaper = strings.NewReplacer(`\n`, " ", `\N`, " ", `\,`, ",", `\;`, ";", `\\`, `\`)
+
+func unescape(value string) string {
+	return strings.TrimSpace(unescaper.Replace(value))
+}
@@ -0,0 +1,129 @@
+package ical
+
+import (
+	"strings"
+	"testing"
+	"time"
+	_ "time/tzdata"
+)
+
+func TestParse(t *testing.T) {
+	input := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"BEGIN:VEVENT",
+		"UID:new-year@example.com",
+		"DTSTART;VALUE=DATE:20270101",
+		"DTEND;VALUE=DATE:20270102",
+		"SUMMARY:Tahun Baru\\, Masehi",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:lebaran@example.com",
+		"DTSTART;VALUE=DATE:20270310",
+		"DTEND;VALUE=DATE:20270312",
+		"SUMMARY:Idul Fitri",
+		" (cuti bersama)",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"DTSTART:20271225T090000Z",
+		"SUMMARY:Natal",
+		"END:VEVENT",
+		"END:VCALENDAR",
+	}, "\r\n")
+
+	events, err := Parse(strings.NewReader(input))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		uid, summary, start, end string
+		days                     int
+	}{
+		{"new-year@example.com", "Tahun Baru, Masehi", "2027-01-01", "2027-01-01", 1},
+		{"lebaran@example.com", "Idul Fitri(cuti bersama)", "2027-03-10", "2027-03-11", 2},
+		{"", "Natal", "2027-12-25", "2027-12-25", 1},
+	}
+	if len(events) != len(tests) {
+		t.Fatalf("Parse() returned %d events, want %d", len(events), len(tests))
+	}
+	for i, tt := range tests {
+		event := events[i]
+		if event.UID != tt.uid || event.Summary != tt.summary ||
+			event.Start.Format("2006-01-02") != tt.start || event.End.Format("2006-01-02") != tt.end {
+			t.Errorf("event %d = %+v", i, event)
+		}
+		if got := len(event.Dates()); got != tt.days {
+			t.Errorf("event %d covers %d days, want %d", i, got, tt.days)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{name: "missing DTSTART", input: "BEGIN:VEVENT\nSUMMARY:X\nEND:VEVENT"},
+		{name: "invalid date", input: "BEGIN:VEVENT\nDTSTART;VALUE=DATE:2027-01-01\nEND:VEVENT"},
+		{name: "not closed", input: "BEGIN:VEVENT\nDTSTART;VALUE=DATE:20270101"},
+		{name: "end without begin", input: "END:VEVENT"},
+		{name: "ends before start", input: "BEGIN:VEVENT\nDTSTART;VALUE=DATE:20270105\nDTEND;VALUE=DATE:20270101\nEND:VEVENT"},
+		{name: "span too long", input: "BEGIN:VEVENT\nDTSTART;VALUE=DATE:20270101\nDTEND;VALUE=DATE:22060101\nEND:VEVENT"},
+		{name: "one day over the limit", input: "BEGIN:VEVENT\nDTSTART;VALUE=DATE:20270101\nDTEND;VALUE=DATE:20270202\nEND:VEVENT"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Parse(strings.NewReader(tt.input)); err == nil {
+				t.Fatal("Parse() accepted invalid input")
+			}
+		})
+	}
+}
+
+func TestParseMaxEventDays(t *testing.T) {
+	// DTEND eksklusif: 1 - 31 Januari tepat MaxEventDays hari
+	events, err := Parse(strings.NewReader("BEGIN:VEVENT\nDTSTART;VALUE=DATE:20270101\nDTEND;VALUE=DATE:20270201\nEND:VEVENT"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := len(events[0].Dates()); got != MaxEventDays {
+		t.Fatalf("len(Dates()) = %d, want %d", got, MaxEventDays)
+	}
+}
+
+func TestParseDateTimeZone(t *testing.T) {
+	jakarta, err := time.LoadLocation("Asia/Jakarta")
+	if err != nil {
+		t.Fatal(err)
+	}
+	local := time.Local
+	time.Local = jakarta
+	t.Cleanup(func() { time.Local = local })
+
+	tests := []struct {
+		name string
+		line string
+		want string
+	}{
+		{name: "utc evening is the next day in Jakarta", line: "DTSTART:20261224T200000Z", want: "2026-12-25"},
+		{name: "utc morning", line: "DTSTART:20261225T020000Z", want: "2026-12-25"},
+		{name: "tzid", line: "DTSTART;TZID=America/New_York:20261224T150000", want: "2026-12-25"},
+		{name: "quoted tzid", line: `DTSTART;TZID="Asia/Jakarta":20261224T230000`, want: "2026-12-24"},
+		{name: "floating time uses server zone", line: "DTSTART:20261224T230000", want: "2026-12-24"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events, err := Parse(strings.NewReader("BEGIN:VEVENT\n" + tt.line + "\nEND:VEVENT"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := events[0].Start.Format("2006-01-02"); got != tt.want || events[0].End != events[0].Start {
+				t.Fatalf("event = %+v, want %s", events[0], tt.want)
+			}
+		})
+	}
+
+	if _, err := Parse(strings.NewReader("BEGIN:VEVENT\nDTSTART;TZID=Mars/Olympus:20261224T230000\nEND:VEVENT")); err == nil {
+		t.Fatal("Parse() accepted an unknown TZID")
+	}
+}
@@ -0,0 +1,168 @@
+package idgen
+
+import (
+	"errors"
+	"fleetify-backend/repositories"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Format aturan kode, contoh EMP-003 atau ATT-2026-000123
+type Format struct {
+	Name        string // nama counter di tabel id_sequences
+	Prefix      string
+	Padding     int
+	YearlyReset bool
+}
+
+var (
+	Employee   = Format{Name: "employee", Prefix: "EMP", Padding: 3}
+	Attendance = Format{Name: "attendance", Prefix: "ATT", Padding: 6}
+)
+
+// ErrSequenceExhausted nomor berikutnya sudah tidak muat di padding
+var ErrSequenceExhausted = errors.New("id sequence exhausted")
+
+// LoadFormats membaca EMPLOYEE_ID_* dan ATTENDANCE_ID_* dari env
+func LoadFormats() error {
+	if err := loadFormat(&Employee, "EMPLOYEE_ID"); err != nil {
+		return err
+	}
+	return loadFormat(&Attendance, "ATTENDANCE_ID")
+}
+
+func loadFormat(f *Format, env string) error {
+	if v := os.Getenv(env + "_PREFIX"); v != "" {
+		if strings.Contains(v, "-") {
+			return fmt.Errorf("invalid %s_PREFIX %q: must not contain '-'", env, v)
+		}
+		f.Prefix = v
+	}
+	if v := os.Getenv(env + "_PADDING"); v != "" {
+		padding, err := strconv.Atoi(v)
+		if err != nil || padding < 1 || padding > 18 {
+			return fmt.Errorf("invalid %s_PADDING %q: expected 1-18", env, v)
+		}
+		f.Padding = padding
+	}
+	if v := os.Getenv(env + "_YEARLY_RESET"); v != "" {
+		reset, err := strconv.ParseBool(v)
+		if err != nil {
+			return fmt.Errorf("invalid %s_YEARLY_RESET %q: expected true or false", env, v)
+		}
+		f.YearlyReset = reset
+	}
+	return nil
+}
+
+// Period periode counter untuk waktu t ("" kalau tidak reset tahunan)
+func (f Format) Period(t time.Time) string {
+	if !f.YearlyReset {
+		return ""
+	}
+	return strconv.Itoa(t.Year())
+}
+
+// Max nomor urut terbesar yang muat di Padding digit
+func (f Format) Max() int64 {
+	limit := int64(1)
+	for i := 0; i < f.Padding; i++ {
+		limit *= 10
+	}
+	return limit - 1
+}
+
+// Code menyusun kode dari periode dan nomor urut
+func (f Format) Code(period string, n int64) string {
+	if period == "" {
+		return fmt.Sprintf("%s-%0*d", f.Prefix, f.Padding, n)
+	}
+	return fmt.Sprintf("%s-%s-%0*d", f.Prefix, period, f.Padding, n)
+}
+
+// ParseCode kebalikan Code. Padding tidak dicek supaya kode lama
+// (misal EMP-003 sebelum padding diubah) tetap terbaca.
+func (f Format) ParseCode(code string) (period string, n int64, ok bool) {
+	rest, found := strings.CutPrefix(code, f.Prefix+"-")
+	if !found {
+		return "", 0, false
+	}
+	if f.YearlyReset {
+		year, num, found := strings.Cut(rest, "-")
+		if !found || len(year) != 4 || !isDigits(year) {
+			return "", 0, false
+		}
+		period, rest = year, num
+	}
+	if !isDigits(rest) {
+		return "", 0, false
+	}
+	n, err := strconv.ParseInt(rest, 10, 64)
+	if err != nil {
+		return "", 0, false
+	}
+	return period, n, true
+}
+
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// MaxByPeriod nomor terbesar per periode dari kode yang sudah ada
+func (f Format) MaxByPeriod(codes []string) map[string]int64 {
+	maxes := map[string]int64{}
+	for _, code := range codes {
+		if period, n, ok := f.ParseCode(code); ok && n > maxes[period] {
+			maxes[period] = n
+		}
+	}
+	return maxes
+}
+
+// Next mengambil kode berikutnya dari counter. existingCodes dipakai untuk
+// backfill counter dari data lama kalau counter periode itu belum ada.
+// Nomor yang melebihi Padding digit ditolak (kode lebih panjang merusak urutan),
+// padding harus dinaikkan dulu lewat env.
+func Next(sequences repositories.SequenceRepository, f Format, now time.Time, existingCodes func(prefix string) ([]string, error)) (string, error) {
+	period := f.Period(now)
+	n, err := sequences.Next(f.Name, period, func() (int64, error) {
+		codes, err := existingCodes(f.Prefix)
+		if err != nil {
+			return 0, err
+		}
+		return f.MaxByPeriod(codes)[period], nil
+	})
+	if err != nil {
+		return "", err
+	}
+	if n > f.Max() {
+		return "", fmt.Errorf("%w: %s counter reached %d, the maximum for padding %d", ErrSequenceExhausted, f.Name, n, f.Padding)
+	}
+	return f.Code(period, n), nil
+}
+
+// Backfill menyamakan counter dengan kode terbesar yang sudah ada di database
+func Backfill(sequences repositories.SequenceRepository, f Format, existingCodes func(prefix string) ([]string, error)) (map[string]int64, error) {
+	codes, err := existingCodes(f.Prefix)
+	if err != nil {
+		return nil, err
+	}
+	maxes := f.MaxByPeriod(codes)
+	for period, n := range maxes {
+		if err := sequences.EnsureAtLeast(f.Name, period, n); err != nil {
+			return nil, err
+		}
+	}
+	return maxes, nil
+}
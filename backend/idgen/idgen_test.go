@@ -0,0 +1,160 @@
+package idgen
+
+import (
+	"errors"
+	"fleetify-backend/repositories"
+	"testing"
+	"time"
+)
+
+func TestCode(t *testing.T) {
+	tests := []struct {
+		name   string
+		format Format
+		period string
+		n      int64
+		want   string
+	}{
+		{name: "employee", format: Employee, n: 3, want: "EMP-003"},
+		{name: "attendance", format: Attendance, n: 123, want: "ATT-000123"},
+		{name: "wider than padding", format: Format{Prefix: "EMP", Padding: 3}, n: 1234, want: "EMP-1234"},
+		{name: "yearly", format: Format{Prefix: "ATT", Padding: 6, YearlyReset: true}, period: "2026", n: 7, want: "ATT-2026-000007"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.format.Code(tt.period, tt.n); got != tt.want {
+				t.Fatalf("Code() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseCode(t *testing.T) {
+	plain := Format{Prefix: "EMP", Padding: 3}
+	yearly := Format{Prefix: "ATT", Padding: 6, YearlyReset: true}
+
+	tests := []struct {
+		name       string
+		format     Format
+		code       string
+		wantPeriod string
+		wantN      int64
+		wantOK     bool
+	}{
+		{name: "plain", format: plain, code: "EMP-042", wantN: 42, wantOK: true},
+		{name: "old padding", format: plain, code: "EMP-00042", wantN: 42, wantOK: true},
+		{name: "other prefix", format: plain, code: "ATT-042"},
+		{name: "not a number", format: plain, code: "EMP-04a"},
+		{name: "empty number", format: plain, code: "EMP-"},
+		{name: "yearly", format: yearly, code: "ATT-2026-000123", wantPeriod: "2026", wantN: 123, wantOK: true},
+		{name: "yearly without year", format: yearly, code: "ATT-000123"},
+		{name: "yearly with short year", format: yearly, code: "ATT-26-000123"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			period, n, ok := tt.format.ParseCode(tt.code)
+			if period != tt.wantPeriod || n != tt.wantN || ok != tt.wantOK {
+				t.Fatalf("ParseCode(%q) = %q, %d, %v", tt.code, period, n, ok)
+			}
+		})
+	}
+}
+
+func TestLoadFormats(t *testing.T) {
+	defer func(employee, attendance Format) { Employee, Attendance = employee, attendance }(Employee, Attendance)
+
+	t.Setenv("EMPLOYEE_ID_PREFIX", "STAFF")
+	t.Setenv("ATTENDANCE_ID_PADDING", "8")
+	t.Setenv("ATTENDANCE_ID_YEARLY_RESET", "true")
+	if err := LoadFormats(); err != nil {
+		t.Fatal(err)
+	}
+	if Employee.Prefix != "STAFF" || Attendance.Padding != 8 || !Attendance.YearlyReset {
+		t.Fatalf("formats not loaded: %+v %+v", Employee, Attendance)
+	}
+
+	for env, value := range map[string]string{
+		"EMPLOYEE_ID_PREFIX":         "EMP-X",
+		"EMPLOYEE_ID_PADDING":        "0",
+		"ATTENDANCE_ID_YEARLY_RESET": "maybe",
+	} {
+		t.Run(env, func(t *testing.T) {
+			t.Setenv(env, value)
+			if err := LoadFormats(); err == nil {
+				t.Fatalf("%s=%s accepted", env, value)
+			}
+		})
+	}
+}
+
+func TestNext(t *testing.T) {
+	repos := repositories.NewMemoryRepositories()
+	existing := []string{"EMP-001", "EMP-007", "EMP-0012", "OTHER-999"}
+	codes := func(prefix string) ([]string, error) { return existing, nil }
+	now := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+
+	// Counter baru di-backfill dari kode terbesar yang sudah ada
+	for _, want := range []string{"EMP-013", "EMP-014"} {
+		got, err := Next(repos.Sequences, Employee, now, codes)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != want {
+			t.Fatalf("Next() = %s, want %s", got, want)
+		}
+	}
+
+	// Reset tahunan memakai counter per tahun
+	yearly := Format{Name: "yearly", Prefix: "ATT", Padding: 6, YearlyReset: true}
+	none := func(prefix string) ([]string, error) { return nil, nil }
+	for _, tt := range []struct {
+		now  time.Time
+		want string
+	}{
+		{now, "ATT-2026-000001"},
+		{now, "ATT-2026-000002"},
+		{now.AddDate(1, 0, 0), "ATT-2027-000001"},
+	} {
+		got, err := Next(repos.Sequences, yearly, tt.now, none)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tt.want {
+			t.Fatalf("Next() = %s, want %s", got, tt.want)
+		}
+	}
+}
+
+func TestNextOverflow(t *testing.T) {
+	repos := repositories.NewMemoryRepositories()
+	small := Format{Name: "small", Prefix: "T", Padding: 2}
+	codes := func(prefix string) ([]string, error) { return []string{"T-98"}, nil }
+
+	got, err := Next(repos.Sequences, small, time.Now(), codes)
+	if err != nil || got != "T-99" {
+		t.Fatalf("Next() = %s, %v, want T-99", got, err)
+	}
+	if got, err := Next(repos.Sequences, small, time.Now(), codes); !errors.Is(err, ErrSequenceExhausted) {
+		t.Fatalf("Next() = %s, %v, want ErrSequenceExhausted", got, err)
+	}
+}
+
+func TestBackfill(t *testing.T) {
+	repos := repositories.NewMemoryRepositories()
+	codes := func(prefix string) ([]string, error) { return []string{"ATT-000010", "ATT-000004"}, nil }
+
+	maxes, err := Backfill(repos.Sequences, Attendance, codes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if maxes[""] != 10 {
+		t.Fatalf("Backfill() = %v, want 10", maxes)
+	}
+	got, err := Next(repos.Sequences, Attendance, time.Now(), codes)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "ATT-000011" {
+		t.Fatalf("Next() after backfill = %s, want ATT-000011", got)
+	}
+}
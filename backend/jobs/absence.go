@@ -0,0 +1,104 @@
+package jobs
+
+import (
+	"fleetify-backend/models"
+	"fleetify-backend/repositories"
+	"fleetify-backend/schedule"
+	"fmt"
+	"time"
+)
+
+// AbsenceResult hasil deteksi tidak masuk untuk satu tanggal
+type AbsenceResult struct {
+	Date     string
+	Checked  int      // employee yang dicek
+	Absent   []string // employee_id yang baru dicatat tidak masuk
+	Existing int      // sudah tercatat dari run sebelumnya
+}
+
+// DetectAbsences mencatat AbsenceRecord untuk setiap employee yang pada tanggal
+// bisnis tersebut punya hari kerja (bukan hari libur), masih aktif, tidak punya attendance dan tidak
+// sedang cuti approved. Aman dijalankan berulang kali untuk tanggal yang sama.
+func DetectAbsences(repos repositories.Repositories, businessDate string, dryRun bool) (AbsenceResult, error) {
+	result := AbsenceResult{Date: businessDate}
+	date, err := time.Parse(schedule.DateLayout, businessDate)
+	if err != nil {
+		return result, fmt.Errorf("invalid date %q, expected YYYY-MM-DD", businessDate)
+	}
+
+	err = repos.Transaction(func(tx repositories.Repositories) error {
+		employees, err := tx.Employees.FindAll()
+		if err != nil {
+			return err
+		}
+		attendances, err := tx.Attendances.FindByBusinessDate(businessDate)
+		if err != nil {
+			return err
+		}
+		leaves, err := tx.Leaves.FindAll(repositories.LeaveFilter{
+			Statuses: []string{models.LeaveStatusApproved},
+			DateFrom: businessDate,
+			DateTo:   businessDate,
+		})
+		if err != nil {
+			return err
+		}
+		holidays, err := tx.Holidays.FindAll(repositories.HolidayFilter{DateFrom: businessDate, DateTo: businessDate})
+		if err != nil {
+			return err
+		}
+		existing, err := tx.Attendances.FindAbsences(repositories.HistoryFilter{DateFrom: businessDate, DateTo: businessDate})
+		if err != nil {
+			return err
+		}
+
+		// employee_id yang tidak perlu dicatat lagi
+		present := map[string]bool{}
+		for _, att := range attendances {
+			present[att.EmployeeID] = true
+		}
+		onLeave := map[string]bool{}
+		for _, leave := range leaves {
+			onLeave[leave.EmployeeID] = true
+		}
+		recorded := map[string]bool{}
+		for _, absence := range existing {
+			recorded[absence.EmployeeID] = true
+		}
+
+		for _, employee := range employees {
+			// Employee baru dihitung mulai hari dibuat
+			if employee.CreatedAt.Format(schedule.DateLayout) > businessDate {
+				continue
+			}
+			rule := schedule.Resolve(employee, date, holidays)
+			start, _, ok := rule.Window(date)
+			if !ok || rule.Holiday != "" || !employee.IsActiveAt(start) {
+				continue
+			}
+			result.Checked++
+			if present[employee.EmployeeID] || onLeave[employee.EmployeeID] {
+				continue
+			}
+			if recorded[employee.EmployeeID] {
+				result.Existing++
+				continue
+			}
+
+			result.Absent = append(result.Absent, employee.EmployeeID)
+			if dryRun {
+				continue
+			}
+			absence := models.AbsenceRecord{
+				EmployeeID:   employee.EmployeeID,
+				BusinessDate: businessDate,
+				RuleSnapshot: rule.Snapshot(),
+			}
+			if err := tx.Attendances.CreateAbsence(&absence); err != nil {
+				return fmt.Errorf("record absence %s: %w", employee.EmployeeID, err)
+			}
+		}
+		return nil
+	})
+	return result, err
+}
@@ -0,0 +1,135 @@
+package jobs
+
+import (
+	"fleetify-backend/models"
+	"fleetify-backend/repositories"
+	"fleetify-backend/schedule"
+	"fmt"
+	"time"
+)
+
+// AutoCloseResult hasil satu run auto-close
+type AutoCloseResult struct {
+	Checked int      // attendance yang masih terbuka
+	Closed  []string // attendance_id yang ditutup
+	Skipped []string // attendance_id yang clock in-nya setelah jam pulang shift, harus clock out manual
+}
+
+// AutoCloseAttendances menutup attendance yang masih terbuka setelah melewati batas
+// (jam pulang shift + after). Clock out diisi jam pulang shift dan history-nya
+// ditandai auto_closed dengan review_status pending supaya dicek HR. Istirahat yang
+// belum selesai ikut ditutup di jam yang sama. Attendance yang clock in setelah jam
+// pulang shift tidak punya jam pulang yang masuk akal, jadi dilewati dan dilaporkan
+// di Skipped (setelah lewat after dari clock in) supaya di-clock out manual.
+// Setiap attendance dikunci sebelum ditutup supaya clock out yang masuk bersamaan tidak ditimpa.
+func AutoCloseAttendances(repos repositories.Repositories, now time.Time, after time.Duration, dryRun bool) (AutoCloseResult, error) {
+	var result AutoCloseResult
+	err := repos.Transaction(func(tx repositories.Repositories) error {
+		attendances, err := tx.Attendances.FindOpen()
+		if err != nil {
+			return err
+		}
+		result.Checked = len(attendances)
+
+		for i := range attendances {
+			attendance := &attendances[i]
+			rule, closing, ok := closingTime(attendance.Employee, *attendance)
+			if !ok {
+				if !now.Before(attendance.ClockIn.Add(after)) {
+					result.Skipped = append(result.Skipped, attendance.AttendanceID)
+				}
+				continue
+			}
+			if now.Before(closing.Add(after)) {
+				continue
+			}
+
+			// Baris dikunci lalu dicek ulang, employee bisa saja clock out (atau jamnya
+			// dikoreksi) setelah FindOpen di atas
+			current, err := tx.Attendances.LockByAttendanceID(attendance.AttendanceID)
+			if err != nil {
+				return err
+			}
+			current.Employee = attendance.Employee
+			*attendance = *current
+			if attendance.ClockOut != nil {
+				continue
+			}
+			if rule, closing, ok = closingTime(attendance.Employee, *attendance); !ok || now.Before(closing.Add(after)) {
+				continue
+			}
+
+			// Clock out tidak boleh sebelum istirahat terakhir
+			histories, err := tx.Attendances.FindHistories(repositories.HistoryFilter{
+				AttendanceID: attendance.AttendanceID,
+				Types:        []models.AttendanceEventType{models.AttendanceTypeBreakStart, models.AttendanceTypeBreakEnd},
+			})
+			if err != nil {
+				return err
+			}
+			breaks := schedule.Breaks(histories)
+			open, onBreak := schedule.OpenBreak(breaks)
+			if len(breaks) > 0 {
+				last := breaks[len(breaks)-1]
+				if last.End != nil && closing.Before(*last.End) {
+					closing = *last.End
+				} else if last.End == nil && closing.Before(last.Start) {
+					closing = last.Start
+				}
+			}
+
+			result.Closed = append(result.Closed, attendance.AttendanceID)
+			if dryRun {
+				continue
+			}
+			if onBreak {
+				// Istirahat yang belum selesai ditutup di jam yang sama
+				breakEnd := models.AttendanceHistory{
+					EmployeeID:     attendance.EmployeeID,
+					AttendanceID:   attendance.AttendanceID,
+					DateAttendance: closing,
+					AttendanceType: models.AttendanceTypeBreakEnd,
+					Description:    "Break End",
+					RuleSnapshot:   rule.Snapshot(),
+					AutoClosed:     true,
+				}
+				if err := tx.Attendances.CreateHistory(&breakEnd); err != nil {
+					return fmt.Errorf("close break %s (started %s): %w", attendance.AttendanceID, open.Start.Format(time.DateTime), err)
+				}
+			}
+			attendance.ClockOut = &closing
+			if err := tx.Attendances.Update(attendance); err != nil {
+				return fmt.Errorf("close %s: %w", attendance.AttendanceID, err)
+			}
+			history := models.AttendanceHistory{
+				EmployeeID:     attendance.EmployeeID,
+				AttendanceID:   attendance.AttendanceID,
+				DateAttendance: closing,
+				AttendanceType: models.AttendanceTypeClockOut,
+				Status:         schedule.StatusAutoClosed,
+				Description:    schedule.Description(schedule.StatusAutoClosed, models.AttendanceTypeClockOut),
+				RuleSnapshot:   rule.Snapshot(),
+				AutoClosed:     true,
+				ReviewStatus:   models.ReviewPending,
+			}
+			if err := tx.Attendances.CreateHistory(&history); err != nil {
+				return fmt.Errorf("close %s: %w", attendance.AttendanceID, err)
+			}
+		}
+		return nil
+	})
+	return result, err
+}
+
+// closingTime jam pulang yang dipakai untuk attendance yang lupa clock out: jam selesai
+// shift pada tanggal bisnisnya, atau akhir hari kalau tidak ada jadwal (hari libur shift).
+// ok=false kalau clock in tidak sebelum jam tersebut (clock out = clock in tidak valid).
+func closingTime(employee models.Employee, attendance models.Attendance) (schedule.Rule, time.Time, bool) {
+	businessDate := schedule.BusinessDateOf(attendance)
+	rule := schedule.Resolve(employee, businessDate, nil)
+	_, closing, ok := rule.Window(businessDate)
+	if !ok {
+		closing = businessDate.AddDate(0, 0, 1).Add(-time.Second)
+	}
+	return rule, closing, closing.After(attendance.ClockIn)
+}
@@ -0,0 +1,204 @@
+package jobs
+
+import (
+	"fleetify-backend/models"
+	"fleetify-backend/repositories"
+	"fleetify-backend/schedule"
+	"testing"
+	"time"
+)
+
+func dateTime(s string) time.Time {
+	t, err := time.Parse("2006-01-02 15:04:05", s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+// newRepos repository memory dengan department IT (08:00 - 17:00), EMP-001 dan EMP-002
+func newRepos(t *testing.T) repositories.Repositories {
+	t.Helper()
+	repos := repositories.NewMemoryRepositories()
+	department := models.Department{DepartmentName: "IT", MaxClockInTime: "08:00:00", MaxClockOutTime: "17:00:00"}
+	if err := repos.Departments.Create(&department); err != nil {
+		t.Fatal(err)
+	}
+	for _, code := range []string{"EMP-001", "EMP-002"} {
+		if err := repos.Employees.Create(&models.Employee{EmployeeID: code, DepartmentID: department.ID, Name: code}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return repos
+}
+
+// clockIn attendance terbuka beserta history-nya
+func clockIn(t *testing.T, repos repositories.Repositories, attendanceID, employeeID, at string) {
+	t.Helper()
+	attendance := models.Attendance{AttendanceID: attendanceID, EmployeeID: employeeID, ClockIn: dateTime(at), BusinessDate: at[:10]}
+	if err := repos.Attendances.Create(&attendance); err != nil {
+		t.Fatal(err)
+	}
+	addHistory(t, repos, attendanceID, employeeID, models.AttendanceTypeClockIn, at)
+}
+
+func addHistory(t *testing.T, repos repositories.Repositories, attendanceID, employeeID string, eventType models.AttendanceEventType, at string) {
+	t.Helper()
+	history := models.AttendanceHistory{AttendanceID: attendanceID, EmployeeID: employeeID, AttendanceType: eventType, DateAttendance: dateTime(at)}
+	if err := repos.Attendances.CreateHistory(&history); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestAutoCloseAttendances(t *testing.T) {
+	tests := []struct {
+		name       string
+		now        string
+		dryRun     bool
+		wantClosed bool
+	}{
+		{name: "not due yet", now: "2026-10-16 17:59:00"},
+		{name: "due", now: "2026-10-16 18:00:00", wantClosed: true},
+		{name: "dry run", now: "2026-10-17 08:00:00", dryRun: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repos := newRepos(t)
+			clockIn(t, repos, "ATT-000001", "EMP-001", "2026-10-16 08:00:00")
+
+			result, err := AutoCloseAttendances(repos, dateTime(tt.now), time.Hour, tt.dryRun)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result.Checked != 1 {
+				t.Fatalf("Checked = %d, want 1", result.Checked)
+			}
+			if closed := len(result.Closed) == 1; closed != (tt.wantClosed || tt.dryRun) {
+				t.Fatalf("Closed = %v", result.Closed)
+			}
+
+			attendance, err := repos.Attendances.FindByAttendanceID("ATT-000001")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !tt.wantClosed {
+				if attendance.ClockOut != nil {
+					t.Fatalf("clock_out = %s, want still open", attendance.ClockOut)
+				}
+				return
+			}
+			if attendance.ClockOut == nil || !attendance.ClockOut.Equal(dateTime("2026-10-16 17:00:00")) {
+				t.Fatalf("clock_out = %v, want end of shift", attendance.ClockOut)
+			}
+			histories, err := repos.Attendances.FindHistories(repositories.HistoryFilter{
+				AttendanceID: "ATT-000001",
+				Types:        []models.AttendanceEventType{models.AttendanceTypeClockOut},
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(histories) != 1 || !histories[0].AutoClosed || histories[0].ReviewStatus != models.ReviewPending ||
+				histories[0].Status != schedule.StatusAutoClosed {
+				t.Fatalf("clock out history = %+v", histories)
+			}
+		})
+	}
+}
+
+func TestAutoCloseOpenBreak(t *testing.T) {
+	repos := newRepos(t)
+	clockIn(t, repos, "ATT-000001", "EMP-001", "2026-10-16 08:00:00")
+	addHistory(t, repos, "ATT-000001", "EMP-001", models.AttendanceTypeBreakStart, "2026-10-16 16:30:00")
+
+	if _, err := AutoCloseAttendances(repos, dateTime("2026-10-17 08:00:00"), time.Hour, false); err != nil {
+		t.Fatal(err)
+	}
+	histories, err := repos.Attendances.FindHistories(repositories.HistoryFilter{
+		AttendanceID: "ATT-000001",
+		Types:        []models.AttendanceEventType{models.AttendanceTypeBreakStart, models.AttendanceTypeBreakEnd},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	breaks := schedule.Breaks(histories)
+	if _, open := schedule.OpenBreak(breaks); open || len(breaks) != 1 || !breaks[0].End.Equal(dateTime("2026-10-16 17:00:00")) {
+		t.Fatalf("breaks = %+v, want the break closed at the end of shift", breaks)
+	}
+}
+
+func TestAutoCloseSkipsClockInAfterShift(t *testing.T) {
+	repos := newRepos(t)
+	clockIn(t, repos, "ATT-000001", "EMP-001", "2026-10-16 19:00:00")
+
+	for _, tt := range []struct {
+		now         string
+		wantSkipped int
+	}{
+		{now: "2026-10-16 19:30:00", wantSkipped: 0},
+		{now: "2026-10-16 20:00:00", wantSkipped: 1},
+	} {
+		result, err := AutoCloseAttendances(repos, dateTime(tt.now), time.Hour, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(result.Closed) != 0 || len(result.Skipped) != tt.wantSkipped {
+			t.Fatalf("at %s: result = %+v, want %d skipped", tt.now, result, tt.wantSkipped)
+		}
+	}
+	attendance, err := repos.Attendances.FindByAttendanceID("ATT-000001")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if attendance.ClockOut != nil {
+		t.Fatalf("clock_out = %s, want still open", attendance.ClockOut)
+	}
+}
+
+func TestDetectAbsences(t *testing.T) {
+	repos := newRepos(t)
+	today := time.Now().Format(schedule.DateLayout)
+	clockIn(t, repos, "ATT-000001", "EMP-001", today+" 08:00:00")
+
+	result, err := DetectAbsences(repos, today, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Checked != 2 || len(result.Absent) != 1 || result.Absent[0] != "EMP-002" {
+		t.Fatalf("first run = %+v, want EMP-002 absent", result)
+	}
+
+	// Run berikutnya tidak mencatat ulang
+	result, err = DetectAbsences(repos, today, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(result.Absent) != 0 || result.Existing != 1 {
+		t.Fatalf("second run = %+v, want the absence counted as existing", result)
+	}
+
+	// Tanggal sebelum employee dibuat dilewati
+	yesterday := time.Now().AddDate(0, 0, -1).Format(schedule.DateLayout)
+	if result, err = DetectAbsences(repos, yesterday, false); err != nil || result.Checked != 0 {
+		t.Fatalf("before employees were created = %+v, %v", result, err)
+	}
+
+	if _, err := DetectAbsences(repos, "18-10-2026", false); err == nil {
+		t.Fatal("invalid date accepted")
+	}
+}
+
+func TestDetectAbsencesHoliday(t *testing.T) {
+	repos := newRepos(t)
+	today := time.Now().Format(schedule.DateLayout)
+	if err := repos.Holidays.Create(&models.Holiday{Date: today, Name: "National Day", Type: models.HolidayNational}); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := DetectAbsences(repos, today, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Checked != 0 || len(result.Absent) != 0 {
+		t.Fatalf("holiday = %+v, want nobody checked", result)
+	}
+}
@@ -0,0 +1,57 @@
+package jobs
+
+import (
+	"fmt"
+	"log"
+	"time"
+)
+
+// Daily menjalankan fn setiap hari pada jam at (HH:MM, waktu lokal server)
+// di goroutine terpisah. Panic di fn dicatat ke log tanpa menghentikan server.
+func Daily(name, at string, fn func(now time.Time)) error {
+	clock, err := time.Parse("15:04", at)
+	if err != nil {
+		return fmt.Errorf("invalid time %q for job %s, expected HH:MM", at, name)
+	}
+
+	go func() {
+		for {
+			now := time.Now()
+			next := time.Date(now.Year(), now.Month(), now.Day(), clock.Hour(), clock.Minute(), 0, 0, now.Location())
+			if !next.After(now) {
+				next = next.AddDate(0, 0, 1)
+			}
+			time.Sleep(time.Until(next))
+			run(name, fn)
+		}
+	}()
+	log.Printf("⏰ job %s scheduled daily at %s", name, at)
+	return nil
+}
+
+func run(name string, fn func(now time.Time)) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("job %s panic: %v", name, r)
+		}
+	}()
+	fn(time.Now())
+}
+
+// Every menjalankan fn setiap interval di goroutine terpisah, run pertama
+// setelah interval pertama lewat
+func Every(name string, interval time.Duration, fn func(now time.Time)) error {
+	if interval <= 0 {
+		return fmt.Errorf("invalid interval %s for job %s", interval, name)
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			run(name, fn)
+		}
+	}()
+	log.Printf("⏰ job %s scheduled every %s", name, interval)
+	return nil
+}
@@ -1,8 +1,15 @@
 package main
 
 import (
+	"fleetify-backend/auth"
 	"fleetify-backend/config"
+	"fleetify-backend/idgen"
+	"fleetify-backend/jobs"
+	"fleetify-backend/migrations"
+	"fleetify-backend/models"
+	"fleetify-backend/repositories"
 	"fleetify-backend/routes"
+	"fleetify-backend/schedule"
 	"log"
 	"os"
 	"time"
@@ -21,8 +28,33 @@ func main() {
 	// Connect database
 	config.ConnectDB()
 
-	// === Manual Migration ===
-	migrateTables()
+	// Format kode EMP-xxx / ATT-xxx
+	if err := idgen.LoadFormats(); err != nil {
+		log.Fatal("Failed to load ID formats: ", err)
+	}
+
+	// Subcommand: go run . migrate up|down|status, backfill-ids
+	if len(os.Args) > 1 {
+		runCommand(os.Args[1], os.Args[2:])
+		return
+	}
+
+	// JWT config
+	if err := auth.LoadConfig(); err != nil {
+		log.Fatal("Failed to load JWT config: ", err)
+	}
+
+	// Jalankan migrasi yang belum diterapkan (matikan dengan AUTO_MIGRATE=false)
+	if os.Getenv("AUTO_MIGRATE") != "false" {
+		applied, err := migrations.Up(config.DB, 0)
+		if err != nil {
+			log.Fatal("Failed to migrate: ", err)
+		}
+		log.Printf("✅ Migration completed (%d applied)", len(applied))
+	}
+	repos := repositories.NewGormRepositories(config.DB)
+	seedAdminUser(repos.Users)
+	startJobs(repos)
 
 	// Inisialisasi Gin
 	app := gin.Default()
@@ -38,7 +70,7 @@ func main() {
 	}))
 
 	// Routes
-	routes.RegisterRoutes(app)
+	routes.RegisterRoutes(app, repos)
 
 	// Jalankan server
 	port := os.Getenv("APP_PORT")
@@ -51,90 +83,82 @@ func main() {
 	}
 }
 
-func migrateTables() {
-	db := config.DB
-
-	// ===========================
-	// Department
-	// ===========================
-	deptSQL := `
-	CREATE TABLE IF NOT EXISTS departments (
-		id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
-		department_name VARCHAR(255) NOT NULL,
-		max_clock_in_time TIME NOT NULL,
-		max_clock_out_time TIME NOT NULL,
-		created_at DATETIME(3),
-		updated_at DATETIME(3)
-	) ENGINE=InnoDB;
-	`
-	if err := db.Exec(deptSQL).Error; err != nil {
-		log.Fatal("Failed to migrate departments:", err)
-	}
-
-	// ===========================
-	// Employee
-	// ===========================
-	empSQL := `
-	CREATE TABLE IF NOT EXISTS employees (
-		id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
-		employee_id VARCHAR(50) UNIQUE NOT NULL,
-		department_id BIGINT UNSIGNED NOT NULL,
-		name VARCHAR(255),
-		address TEXT,
-		created_at DATETIME(3),
-		updated_at DATETIME(3),
-		FOREIGN KEY (department_id) REFERENCES departments(id)
-		ON UPDATE CASCADE
-		ON DELETE CASCADE
-	) ENGINE=InnoDB;
-	`
-	if err := db.Exec(empSQL).Error; err != nil {
-		log.Fatal("Failed to migrate employees:", err)
-	}
-
-	// ===========================
-	// Attendance
-	// ===========================
-	attSQL := `
-	CREATE TABLE IF NOT EXISTS attendances (
-		id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
-		employee_id VARCHAR(50) NOT NULL,
-		attendance_id VARCHAR(100) NOT NULL UNIQUE,
-		clock_in DATETIME(3),
-		clock_out DATETIME(3),
-		created_at DATETIME(3),
-		updated_at DATETIME(3),
-		FOREIGN KEY (employee_id) REFERENCES employees(employee_id)
-		ON UPDATE CASCADE
-		ON DELETE CASCADE
-	) ENGINE=InnoDB;
-	`
-	if err := db.Exec(attSQL).Error; err != nil {
-		log.Fatal("Failed to migrate attendances:", err)
-	}
-
-	// ===========================
-	// AttendanceHistory
-	// ===========================
-	histSQL := `
-	CREATE TABLE IF NOT EXISTS attendance_histories (
-		id BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY,
-		employee_id VARCHAR(50) NOT NULL,
-		attendance_id VARCHAR(100) NOT NULL,
-		date_attendance DATETIME(3),
-		attendance_type TINYINT,
-		description TEXT,
-		created_at DATETIME(3),
-		updated_at DATETIME(3),
-		FOREIGN KEY (employee_id) REFERENCES employees(employee_id),
-		FOREIGN KEY (attendance_id) REFERENCES attendances(attendance_id)
-		ON UPDATE CASCADE
-		ON DELETE CASCADE
-	) ENGINE=InnoDB;
-	`
-	if err := db.Exec(histSQL).Error; err != nil {
-		log.Fatal("Failed to migrate attendance_histories:", err)
-	}
-
-	log.Println("✅ Manual migration completed")
+// startJobs menjadwalkan job background, masing-masing bisa dimatikan lewat
+// ABSENCE_JOB_ENABLED=false / AUTO_CLOSE_JOB_ENABLED=false
+func startJobs(repos repositories.Repositories) {
+	if os.Getenv("ABSENCE_JOB_ENABLED") != "false" {
+		startAbsenceJob(repos)
+	}
+	if os.Getenv("AUTO_CLOSE_JOB_ENABLED") != "false" {
+		startAutoCloseJob(repos)
+	}
+}
+
+func startAbsenceJob(repos repositories.Repositories) {
+	at := os.Getenv("ABSENCE_JOB_TIME")
+	if at == "" {
+		at = "01:00"
+	}
+	err := jobs.Daily("detect-absences", at, func(now time.Time) {
+		result, err := jobs.DetectAbsences(repos, now.AddDate(0, 0, -1).Format(schedule.DateLayout), false)
+		if err != nil {
+			log.Println("detect-absences failed:", err)
+			return
+		}
+		logAbsenceResult(result, false)
+	})
+	if err != nil {
+		log.Fatal("Failed to schedule jobs: ", err)
+	}
+}
+
+func startAutoCloseJob(repos repositories.Repositories) {
+	after, err := autoCloseAfter()
+	if err != nil {
+		log.Fatal(err)
+	}
+	interval := 15 * time.Minute
+	if value := os.Getenv("AUTO_CLOSE_JOB_INTERVAL"); value != "" {
+		if interval, err = time.ParseDuration(value); err != nil {
+			log.Fatalf("invalid AUTO_CLOSE_JOB_INTERVAL %q, expected Go duration (e.g. 15m)", value)
+		}
+	}
+	err = jobs.Every("auto-close", interval, func(now time.Time) {
+		result, err := jobs.AutoCloseAttendances(repos, now, after, false)
+		if err != nil {
+			log.Println("auto-close failed:", err)
+			return
+		}
+		logAutoCloseResult(result, false)
+	})
+	if err != nil {
+		log.Fatal("Failed to schedule jobs: ", err)
+	}
+}
+
+// seedAdminUser membuat user pertama dari ADMIN_USERNAME/ADMIN_PASSWORD
+// kalau tabel users masih kosong
+func seedAdminUser(users repositories.UserRepository) {
+	username := os.Getenv("ADMIN_USERNAME")
+	password := os.Getenv("ADMIN_PASSWORD")
+	if username == "" || password == "" {
+		return
+	}
+
+	count, err := users.Count()
+	if err != nil {
+		log.Fatal("Failed to count users:", err)
+	}
+	if count > 0 {
+		return
+	}
+
+	hash, err := auth.HashPassword(password)
+	if err != nil {
+		log.Fatal("Failed to hash admin password:", err)
+	}
+	if err := users.Create(&models.User{Username: username, PasswordHash: hash, Role: models.RoleAdmin}); err != nil {
+		log.Fatal("Failed to seed admin user:", err)
+	}
+	log.Println("✅ Admin user created:", username)
 }
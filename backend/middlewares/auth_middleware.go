@@ -0,0 +1,42 @@
+package middlewares
+
+import (
+	"fleetify-backend/auth"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AuthRequired menolak request tanpa access token yang valid (401)
+func AuthRequired() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		header := c.GetHeader("Authorization")
+		tokenStr, found := strings.CutPrefix(header, "Bearer ")
+		if !found || strings.TrimSpace(tokenStr) == "" {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+			return
+		}
+
+		claims, err := auth.ParseToken(strings.TrimSpace(tokenStr), auth.TokenTypeAccess)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Invalid or expired token"})
+			return
+		}
+
+		c.Set("user_id", claims.UserID)
+		c.Set("username", claims.Username)
+		c.Set("auth_claims", claims)
+		c.Next()
+	}
+}
+
+// CurrentUser mengambil claims user yang sedang login dari context
+func CurrentUser(c *gin.Context) *auth.Claims {
+	if v, ok := c.Get("auth_claims"); ok {
+		if claims, ok := v.(*auth.Claims); ok {
+			return claims
+		}
+	}
+	return nil
+}
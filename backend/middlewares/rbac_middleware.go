@@ -0,0 +1,47 @@
+package middlewares
+
+import (
+	"fleetify-backend/models"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Kode alasan 403 yang bisa dibaca mesin
+const (
+	ReasonInsufficientRole = "insufficient_role"
+	ReasonNotOwnAttendance = "not_own_attendance"
+	ReasonNotOwnDepartment = "not_own_department"
+	ReasonNoEmployeeLinked = "no_employee_linked"
+	ReasonNotOwnLeave      = "not_own_leave"
+	ReasonOwnLeave         = "own_leave"      // approve / reject cuti milik sendiri
+	ReasonOwnCorrection    = "own_correction" // approve / reject koreksi milik sendiri
+	ReasonOwnOvertime      = "own_overtime"   // approve / reject lembur milik sendiri
+)
+
+// RequireRoles hanya mengizinkan role yang disebut. Admin selalu diizinkan.
+func RequireRoles(roles ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		user := CurrentUser(c)
+		if user == nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
+			return
+		}
+		if user.Role == models.RoleAdmin {
+			c.Next()
+			return
+		}
+		for _, role := range roles {
+			if user.Role == role {
+				c.Next()
+				return
+			}
+		}
+		Forbid(c, ReasonInsufficientRole, "Your role is not allowed to access this resource")
+	}
+}
+
+// Forbid menghentikan request dengan 403 dan kode alasan
+func Forbid(c *gin.Context, reason string, message string) {
+	c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": message, "code": reason})
+}
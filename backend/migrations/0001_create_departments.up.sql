@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS departments (
+	id {{AUTO_ID}},
+	department_name VARCHAR(255) NOT NULL,
+	max_clock_in_time TIME NOT NULL,
+	max_clock_out_time TIME NOT NULL,
+	created_at {{DATETIME}},
+	updated_at {{DATETIME}}
+) {{TABLE_OPTIONS}};
@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS employees (
+	id {{AUTO_ID}},
+	employee_id VARCHAR(50) UNIQUE NOT NULL,
+	department_id {{ID_REF}} NOT NULL,
+	name VARCHAR(255),
+	address TEXT,
+	created_at {{DATETIME}},
+	updated_at {{DATETIME}},
+	FOREIGN KEY (department_id) REFERENCES departments(id)
+	ON UPDATE CASCADE
+	ON DELETE CASCADE
+) {{TABLE_OPTIONS}};
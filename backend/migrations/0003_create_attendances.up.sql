@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS attendances (
+	id {{AUTO_ID}},
+	employee_id VARCHAR(50) NOT NULL,
+	attendance_id VARCHAR(100) NOT NULL UNIQUE,
+	clock_in {{DATETIME}},
+	clock_out {{DATETIME}},
+	created_at {{DATETIME}},
+	updated_at {{DATETIME}},
+	FOREIGN KEY (employee_id) REFERENCES employees(employee_id)
+	ON UPDATE CASCADE
+	ON DELETE CASCADE
+) {{TABLE_OPTIONS}};
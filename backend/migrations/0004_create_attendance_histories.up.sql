@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS attendance_histories (
+	id {{AUTO_ID}},
+	employee_id VARCHAR(50) NOT NULL,
+	attendance_id VARCHAR(100) NOT NULL,
+	date_attendance {{DATETIME}},
+	attendance_type SMALLINT,
+	description TEXT,
+	created_at {{DATETIME}},
+	updated_at {{DATETIME}},
+	FOREIGN KEY (employee_id) REFERENCES employees(employee_id),
+	FOREIGN KEY (attendance_id) REFERENCES attendances(attendance_id)
+	ON UPDATE CASCADE
+	ON DELETE CASCADE
+) {{TABLE_OPTIONS}};
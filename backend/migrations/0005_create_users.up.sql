@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS users (
+	id {{AUTO_ID}},
+	username VARCHAR(100) UNIQUE NOT NULL,
+	password_hash VARCHAR(255) NOT NULL,
+	role VARCHAR(20) NOT NULL DEFAULT 'employee',
+	employee_id VARCHAR(50) NULL,
+	created_at {{DATETIME}},
+	updated_at {{DATETIME}},
+	FOREIGN KEY (employee_id) REFERENCES employees(employee_id)
+	ON UPDATE CASCADE
+	ON DELETE SET NULL
+) {{TABLE_OPTIONS}};
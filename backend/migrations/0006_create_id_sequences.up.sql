@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS id_sequences (
+	name VARCHAR(50) NOT NULL,
+	period VARCHAR(10) NOT NULL DEFAULT '',
+	value BIGINT NOT NULL DEFAULT 0,
+	created_at {{DATETIME}},
+	updated_at {{DATETIME}},
+	PRIMARY KEY (name, period)
+) {{TABLE_OPTIONS}};
@@ -0,0 +1,4 @@
+ALTER TABLE employees DROP COLUMN shift_id;
+ALTER TABLE departments DROP COLUMN shift_id;
+DROP TABLE IF EXISTS shift_days;
+DROP TABLE IF EXISTS shifts;
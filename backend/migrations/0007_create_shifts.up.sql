@@ -0,0 +1,23 @@
+CREATE TABLE IF NOT EXISTS shifts (
+	id {{AUTO_ID}},
+	name VARCHAR(255) NOT NULL,
+	created_at {{DATETIME}},
+	updated_at {{DATETIME}}
+) {{TABLE_OPTIONS}};
+
+CREATE TABLE IF NOT EXISTS shift_days (
+	id {{AUTO_ID}},
+	shift_id {{ID_REF}} NOT NULL,
+	weekday SMALLINT NOT NULL,
+	is_working_day BOOLEAN NOT NULL,
+	start_time TIME NULL,
+	end_time TIME NULL,
+	UNIQUE (shift_id, weekday),
+	FOREIGN KEY (shift_id) REFERENCES shifts(id)
+	ON UPDATE CASCADE
+	ON DELETE CASCADE
+) {{TABLE_OPTIONS}};
+
+-- Tanpa foreign key supaya bisa di-drop di SQLite; dicek di handler
+ALTER TABLE departments ADD COLUMN shift_id {{ID_REF}} NULL;
+ALTER TABLE employees ADD COLUMN shift_id {{ID_REF}} NULL;
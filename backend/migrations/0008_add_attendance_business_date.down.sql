@@ -0,0 +1 @@
+ALTER TABLE attendances DROP COLUMN business_date;
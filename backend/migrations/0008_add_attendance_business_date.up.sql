@@ -0,0 +1,6 @@
+-- Tanggal bisnis (YYYY-MM-DD) = tanggal mulai shift, bisa beda dengan tanggal clock in
+-- untuk shift malam yang melewati tengah malam
+ALTER TABLE attendances ADD COLUMN business_date VARCHAR(10) NULL;
+
+-- Data lama: pakai tanggal clock in
+UPDATE attendances SET business_date = {{DATE_OF(clock_in)}};
@@ -0,0 +1,4 @@
+ALTER TABLE departments DROP COLUMN early_leave_tolerance_minutes;
+ALTER TABLE departments DROP COLUMN late_minutes;
+ALTER TABLE departments DROP COLUMN slightly_late_minutes;
+ALTER TABLE departments DROP COLUMN grace_period_minutes;
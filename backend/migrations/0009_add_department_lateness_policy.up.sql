@@ -0,0 +1,5 @@
+-- Toleransi keterlambatan per department (menit)
+ALTER TABLE departments ADD COLUMN grace_period_minutes INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE departments ADD COLUMN slightly_late_minutes INTEGER NOT NULL DEFAULT 15;
+ALTER TABLE departments ADD COLUMN late_minutes INTEGER NOT NULL DEFAULT 60;
+ALTER TABLE departments ADD COLUMN early_leave_tolerance_minutes INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,4 @@
+ALTER TABLE attendance_histories DROP COLUMN rule_snapshot;
+ALTER TABLE attendance_histories DROP COLUMN minutes_early;
+ALTER TABLE attendance_histories DROP COLUMN minutes_late;
+ALTER TABLE attendance_histories DROP COLUMN status;
@@ -0,0 +1,6 @@
+-- Status ketepatan waktu disimpan saat punch dicatat.
+-- Data lama tetap kosong sampai `recompute-attendance` dijalankan.
+ALTER TABLE attendance_histories ADD COLUMN status VARCHAR(20) NULL;
+ALTER TABLE attendance_histories ADD COLUMN minutes_late INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE attendance_histories ADD COLUMN minutes_early INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE attendance_histories ADD COLUMN rule_snapshot TEXT NULL;
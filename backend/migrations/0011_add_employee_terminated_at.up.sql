@@ -0,0 +1,2 @@
+-- Employee nonaktif tidak boleh clock in setelah tanggal ini
+ALTER TABLE employees ADD COLUMN terminated_at {{DATETIME}} NULL;
@@ -0,0 +1,2 @@
+ALTER TABLE employees DROP COLUMN deleted_at;
+ALTER TABLE departments DROP COLUMN deleted_at;
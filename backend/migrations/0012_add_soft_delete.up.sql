@@ -0,0 +1,3 @@
+-- Soft delete: data attendance tidak pernah ikut terhapus
+ALTER TABLE departments ADD COLUMN deleted_at {{DATETIME}} NULL;
+ALTER TABLE employees ADD COLUMN deleted_at {{DATETIME}} NULL;
@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS leave_requests;
+DROP TABLE IF EXISTS leave_types;
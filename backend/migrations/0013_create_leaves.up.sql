@@ -0,0 +1,47 @@
+CREATE TABLE IF NOT EXISTS leave_types (
+	id {{AUTO_ID}},
+	code VARCHAR(30) NOT NULL UNIQUE,
+	name VARCHAR(100) NOT NULL,
+	yearly_quota INTEGER NOT NULL DEFAULT 0,
+	paid BOOLEAN NOT NULL,
+	created_at {{DATETIME}},
+	updated_at {{DATETIME}}
+) {{TABLE_OPTIONS}};
+
+-- Jenis cuti bawaan, sama dengan models.DefaultLeaveTypes. Dilewati kalau sudah ada,
+-- supaya migrasi bisa dijalankan ulang di MySQL (CREATE TABLE berikutnya meng-commit insert ini).
+INSERT INTO leave_types (code, name, yearly_quota, paid, created_at, updated_at)
+	SELECT 'annual', 'Annual Leave', 12, TRUE, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP FROM (SELECT 1 AS one) seed
+	WHERE NOT EXISTS (SELECT 1 FROM leave_types WHERE code = 'annual');
+INSERT INTO leave_types (code, name, yearly_quota, paid, created_at, updated_at)
+	SELECT 'sick', 'Sick Leave', 0, TRUE, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP FROM (SELECT 1 AS one) seed
+	WHERE NOT EXISTS (SELECT 1 FROM leave_types WHERE code = 'sick');
+INSERT INTO leave_types (code, name, yearly_quota, paid, created_at, updated_at)
+	SELECT 'unpaid', 'Unpaid Leave', 0, FALSE, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP FROM (SELECT 1 AS one) seed
+	WHERE NOT EXISTS (SELECT 1 FROM leave_types WHERE code = 'unpaid');
+INSERT INTO leave_types (code, name, yearly_quota, paid, created_at, updated_at)
+	SELECT 'permission', 'Permission', 3, TRUE, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP FROM (SELECT 1 AS one) seed
+	WHERE NOT EXISTS (SELECT 1 FROM leave_types WHERE code = 'permission');
+
+CREATE TABLE IF NOT EXISTS leave_requests (
+	id {{AUTO_ID}},
+	employee_id VARCHAR(50) NOT NULL,
+	leave_type_id {{ID_REF}} NOT NULL,
+	start_date VARCHAR(10) NOT NULL,
+	end_date VARCHAR(10) NOT NULL,
+	days INTEGER NOT NULL,
+	reason TEXT,
+	attachment VARCHAR(255),
+	status VARCHAR(20) NOT NULL,
+	reviewed_by {{ID_REF}} NULL,
+	reviewed_at {{DATETIME}} NULL,
+	review_note TEXT,
+	created_at {{DATETIME}},
+	updated_at {{DATETIME}},
+	FOREIGN KEY (employee_id) REFERENCES employees(employee_id)
+	ON UPDATE CASCADE
+	ON DELETE CASCADE,
+	FOREIGN KEY (leave_type_id) REFERENCES leave_types(id)
+) {{TABLE_OPTIONS}};
+
+CREATE INDEX idx_leave_requests_employee_dates ON leave_requests (employee_id, start_date, end_date);
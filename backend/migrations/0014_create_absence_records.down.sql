@@ -0,0 +1 @@
+DROP TABLE IF EXISTS absence_records;
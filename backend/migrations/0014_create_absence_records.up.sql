@@ -0,0 +1,13 @@
+-- Hasil job harian deteksi employee yang tidak masuk
+CREATE TABLE IF NOT EXISTS absence_records (
+	id {{AUTO_ID}},
+	employee_id VARCHAR(50) NOT NULL,
+	business_date VARCHAR(10) NOT NULL,
+	rule_snapshot TEXT,
+	created_at {{DATETIME}},
+	updated_at {{DATETIME}},
+	UNIQUE (employee_id, business_date),
+	FOREIGN KEY (employee_id) REFERENCES employees(employee_id)
+	ON UPDATE CASCADE
+	ON DELETE CASCADE
+) {{TABLE_OPTIONS}};
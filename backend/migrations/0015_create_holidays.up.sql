@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS holidays (
+	id {{AUTO_ID}},
+	date VARCHAR(10) NOT NULL,
+	name VARCHAR(255) NOT NULL,
+	type VARCHAR(20) NOT NULL,
+	department_id {{ID_REF}} NULL,
+	created_at {{DATETIME}},
+	updated_at {{DATETIME}},
+	FOREIGN KEY (department_id) REFERENCES departments(id)
+	ON UPDATE CASCADE
+	ON DELETE CASCADE
+) {{TABLE_OPTIONS}};
+
+CREATE INDEX idx_holidays_date ON holidays (date);
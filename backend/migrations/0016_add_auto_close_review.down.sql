@@ -0,0 +1,4 @@
+ALTER TABLE attendance_histories DROP COLUMN reviewed_at;
+ALTER TABLE attendance_histories DROP COLUMN reviewed_by;
+ALTER TABLE attendance_histories DROP COLUMN review_status;
+ALTER TABLE attendance_histories DROP COLUMN auto_closed;
@@ -0,0 +1,5 @@
+-- Clock out otomatis oleh job auto-close ditandai dan masuk antrian review HR
+ALTER TABLE attendance_histories ADD COLUMN auto_closed BOOLEAN NOT NULL DEFAULT FALSE;
+ALTER TABLE attendance_histories ADD COLUMN review_status VARCHAR(20) NULL;
+ALTER TABLE attendance_histories ADD COLUMN reviewed_by {{ID_REF}} NULL;
+ALTER TABLE attendance_histories ADD COLUMN reviewed_at {{DATETIME}} NULL;
@@ -0,0 +1,4 @@
+ALTER TABLE attendance_histories DROP COLUMN new_value;
+ALTER TABLE attendance_histories DROP COLUMN old_value;
+ALTER TABLE attendance_histories DROP COLUMN correction_id;
+DROP TABLE IF EXISTS attendance_corrections;
@@ -0,0 +1,30 @@
+CREATE TABLE IF NOT EXISTS attendance_corrections (
+	id {{AUTO_ID}},
+	attendance_id VARCHAR(100) NOT NULL,
+	employee_id VARCHAR(50) NOT NULL,
+	clock_in {{DATETIME}} NULL,
+	clock_out {{DATETIME}} NULL,
+	old_clock_in {{DATETIME}} NULL,
+	old_clock_out {{DATETIME}} NULL,
+	reason TEXT NOT NULL,
+	status VARCHAR(20) NOT NULL,
+	requested_by {{ID_REF}} NULL,
+	reviewed_by {{ID_REF}} NULL,
+	reviewed_at {{DATETIME}} NULL,
+	review_note TEXT,
+	created_at {{DATETIME}},
+	updated_at {{DATETIME}},
+	FOREIGN KEY (attendance_id) REFERENCES attendances(attendance_id)
+	ON UPDATE CASCADE
+	ON DELETE CASCADE,
+	FOREIGN KEY (employee_id) REFERENCES employees(employee_id)
+	ON UPDATE CASCADE
+	ON DELETE CASCADE
+) {{TABLE_OPTIONS}};
+
+CREATE INDEX idx_attendance_corrections_attendance ON attendance_corrections (attendance_id, status);
+
+-- Baris koreksi di attendance_histories menyimpan jam lama / baru dan pengajuannya
+ALTER TABLE attendance_histories ADD COLUMN correction_id {{ID_REF}} NULL;
+ALTER TABLE attendance_histories ADD COLUMN old_value TEXT NULL;
+ALTER TABLE attendance_histories ADD COLUMN new_value TEXT NULL;
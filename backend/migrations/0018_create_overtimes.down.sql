@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS overtimes;
+ALTER TABLE departments DROP COLUMN overtime_rounding_minutes;
+ALTER TABLE departments DROP COLUMN overtime_min_minutes;
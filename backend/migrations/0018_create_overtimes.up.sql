@@ -0,0 +1,29 @@
+-- Aturan lembur per department (menit)
+ALTER TABLE departments ADD COLUMN overtime_min_minutes INTEGER NOT NULL DEFAULT 30;
+ALTER TABLE departments ADD COLUMN overtime_rounding_minutes INTEGER NOT NULL DEFAULT 15;
+
+-- Lembur per attendance, menunggu approval manager / HR
+CREATE TABLE IF NOT EXISTS overtimes (
+	id {{AUTO_ID}},
+	attendance_id VARCHAR(100) NOT NULL UNIQUE,
+	employee_id VARCHAR(50) NOT NULL,
+	business_date VARCHAR(10) NOT NULL,
+	type VARCHAR(20) NOT NULL,
+	worked_minutes INTEGER NOT NULL,
+	regular_minutes INTEGER NOT NULL,
+	minutes INTEGER NOT NULL,
+	status VARCHAR(20) NOT NULL,
+	reviewed_by {{ID_REF}} NULL,
+	reviewed_at {{DATETIME}} NULL,
+	review_note TEXT,
+	created_at {{DATETIME}},
+	updated_at {{DATETIME}},
+	FOREIGN KEY (attendance_id) REFERENCES attendances(attendance_id)
+	ON UPDATE CASCADE
+	ON DELETE CASCADE,
+	FOREIGN KEY (employee_id) REFERENCES employees(employee_id)
+	ON UPDATE CASCADE
+	ON DELETE CASCADE
+) {{TABLE_OPTIONS}};
+
+CREATE INDEX idx_overtimes_employee_date ON overtimes (employee_id, business_date);
@@ -0,0 +1,4 @@
+-- History istirahat (attendance_type 6 / 7) sengaja tidak dihapus. Kalau versi lama
+-- perlu dijalankan tanpa data tersebut, hapus manual setelah backup:
+--   DELETE FROM attendance_histories WHERE attendance_type IN (6, 7);
+ALTER TABLE departments DROP COLUMN min_break_minutes;
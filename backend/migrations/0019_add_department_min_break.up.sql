@@ -0,0 +1,3 @@
+-- Minimal total istirahat per attendance (menit). Istirahat dicatat sebagai
+-- attendance_histories dengan attendance_type 6 (mulai) dan 7 (selesai).
+ALTER TABLE departments ADD COLUMN min_break_minutes INTEGER NOT NULL DEFAULT 0;
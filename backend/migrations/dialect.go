@@ -0,0 +1,75 @@
+package migrations
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// Placeholder di file migrasi yang diganti sesuai dialect database.
+// Selain placeholder ini, file migrasi hanya boleh memakai SQL standar
+// yang didukung MySQL, PostgreSQL dan SQLite.
+var placeholders = map[string]map[string]string{
+	"mysql": {
+		"{{AUTO_ID}}":       "BIGINT UNSIGNED AUTO_INCREMENT PRIMARY KEY",
+		"{{ID_REF}}":        "BIGINT UNSIGNED",
+		"{{DATETIME}}":      "DATETIME(3)",
+		"{{TABLE_OPTIONS}}": "ENGINE=InnoDB",
+	},
+	"postgres": {
+		"{{AUTO_ID}}":       "BIGSERIAL PRIMARY KEY",
+		"{{ID_REF}}":        "BIGINT",
+		"{{DATETIME}}":      "TIMESTAMP(3)",
+		"{{TABLE_OPTIONS}}": "",
+	},
+	"sqlite": {
+		"{{AUTO_ID}}":       "INTEGER PRIMARY KEY AUTOINCREMENT",
+		"{{ID_REF}}":        "INTEGER",
+		"{{DATETIME}}":      "DATETIME",
+		"{{TABLE_OPTIONS}}": "",
+	},
+}
+
+// Placeholder berbentuk fungsi, contoh {{DATE_OF(clock_in)}}; %s diganti argumennya
+var functions = map[string]map[string]string{
+	"mysql": {
+		"DATE_OF": "DATE_FORMAT(%s, '%%Y-%%m-%%d')",
+	},
+	"postgres": {
+		"DATE_OF": "TO_CHAR(%s, 'YYYY-MM-DD')",
+	},
+	"sqlite": {
+		"DATE_OF": "substr(%s, 1, 10)",
+	},
+}
+
+var functionPattern = regexp.MustCompile(`\{\{([A-Z_]+)\(([^)]*)\)\}\}`)
+
+// render mengganti placeholder sesuai dialect koneksi db
+func render(db *gorm.DB, script string) (string, error) {
+	dialect := db.Dialector.Name()
+	values, ok := placeholders[dialect]
+	if !ok {
+		return "", fmt.Errorf("unsupported database dialect %q", dialect)
+	}
+	for key, value := range values {
+		script = strings.ReplaceAll(script, key, value)
+	}
+
+	var unknown string
+	script = functionPattern.ReplaceAllStringFunc(script, func(match string) string {
+		parts := functionPattern.FindStringSubmatch(match)
+		format, ok := functions[dialect][parts[1]]
+		if !ok {
+			unknown = parts[1]
+			return match
+		}
+		return fmt.Sprintf(format, strings.TrimSpace(parts[2]))
+	})
+	if unknown != "" {
+		return "", fmt.Errorf("unknown migration function placeholder %q", unknown)
+	}
+	return script, nil
+}
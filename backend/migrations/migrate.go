@@ -0,0 +1,315 @@
+package migrations
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"gorm.io/gorm"
+)
+
+// File migrasi: NNNN_nama.up.sql dan NNNN_nama.down.sql
+//
+//go:embed *.sql
+var files embed.FS
+
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+// SchemaMigration satu baris di tabel schema_migrations
+type SchemaMigration struct {
+	Version   int64     `gorm:"primaryKey;autoIncrement:false"`
+	Name      string    `gorm:"type:varchar(255);not null"`
+	AppliedAt time.Time `gorm:"not null"`
+}
+
+func (SchemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+// MigrationAttempt dicatat sebelum migrasi dijalankan dan dihapus setelah berhasil.
+// Kalau masih ada saat dijalankan ulang, percobaan sebelumnya gagal di tengah.
+type MigrationAttempt struct {
+	Version   int64     `gorm:"primaryKey;autoIncrement:false"`
+	Direction string    `gorm:"primaryKey;type:varchar(4)"`
+	StartedAt time.Time `gorm:"not null"`
+}
+
+func (MigrationAttempt) TableName() string {
+	return "schema_migration_attempts"
+}
+
+type StatusRow struct {
+	Version   int64
+	Name      string
+	AppliedAt *time.Time
+}
+
+// Load membaca semua file migrasi yang di-embed, urut berdasarkan versi
+func Load() ([]Migration, error) {
+	entries, err := fs.Glob(files, "*.sql")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := map[int64]*Migration{}
+	for _, file := range entries {
+		base, direction, ok := splitFileName(file)
+		if !ok {
+			return nil, fmt.Errorf("invalid migration file name %q, expected NNNN_name.up.sql or NNNN_name.down.sql", file)
+		}
+		prefix, name, _ := strings.Cut(base, "_")
+		version, err := strconv.ParseInt(prefix, 10, 64)
+		if err != nil || name == "" {
+			return nil, fmt.Errorf("invalid migration file name %q, expected NNNN_name.up.sql or NNNN_name.down.sql", file)
+		}
+
+		content, err := fs.ReadFile(files, file)
+		if err != nil {
+			return nil, err
+		}
+
+		m, exists := byVersion[version]
+		if !exists {
+			m = &Migration{Version: version, Name: name}
+			byVersion[version] = m
+		} else if m.Name != name {
+			return nil, fmt.Errorf("migration %04d has two names: %q and %q", version, m.Name, name)
+		}
+		if direction == "up" {
+			m.Up = string(content)
+		} else {
+			m.Down = string(content)
+		}
+	}
+
+	var list []Migration
+	for _, m := range byVersion {
+		if m.Up == "" || m.Down == "" {
+			return nil, fmt.Errorf("migration %04d_%s must have both up and down files", m.Version, m.Name)
+		}
+		list = append(list, *m)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Version < list[j].Version })
+	return list, nil
+}
+
+func splitFileName(file string) (base string, direction string, ok bool) {
+	if b, found := strings.CutSuffix(file, ".up.sql"); found {
+		return b, "up", true
+	}
+	if b, found := strings.CutSuffix(file, ".down.sql"); found {
+		return b, "down", true
+	}
+	return "", "", false
+}
+
+// Up menjalankan migrasi yang belum diterapkan. steps <= 0 berarti semua.
+//
+// Setiap migrasi dijalankan dalam satu transaksi, tapi di MySQL DDL (CREATE / ALTER / DROP)
+// meng-commit transaksi secara implisit. Kalau statement di tengah file gagal, statement
+// sebelumnya tetap tersimpan dan versi migrasi belum tercatat; perbaiki penyebabnya lalu
+// jalankan ulang. Hanya saat menjalankan ulang itu (tercatat di schema_migration_attempts)
+// statement yang sudah diterapkan dilewati oleh execScript.
+func Up(db *gorm.DB, steps int) ([]Migration, error) {
+	list, applied, err := prepare(db)
+	if err != nil {
+		return nil, err
+	}
+
+	var done []Migration
+	for _, m := range list {
+		if steps > 0 && len(done) >= steps {
+			break
+		}
+		if _, ok := applied[m.Version]; ok {
+			continue
+		}
+		err := run(db, m, "up", m.Up, func(tx *gorm.DB) error {
+			return tx.Create(&SchemaMigration{Version: m.Version, Name: m.Name, AppliedAt: time.Now()}).Error
+		})
+		if err != nil {
+			return done, fmt.Errorf("migration %04d_%s up: %w", m.Version, m.Name, err)
+		}
+		done = append(done, m)
+	}
+	return done, nil
+}
+
+// Down membatalkan migrasi terakhir sebanyak steps (minimal 1).
+// Di MySQL berlaku batasan yang sama dengan Up: down yang gagal di tengah dijalankan ulang.
+func Down(db *gorm.DB, steps int) ([]Migration, error) {
+	list, applied, err := prepare(db)
+	if err != nil {
+		return nil, err
+	}
+	if steps <= 0 {
+		steps = 1
+	}
+
+	var done []Migration
+	for i := len(list) - 1; i >= 0 && len(done) < steps; i-- {
+		m := list[i]
+		if _, ok := applied[m.Version]; !ok {
+			continue
+		}
+		err := run(db, m, "down", m.Down, func(tx *gorm.DB) error {
+			return tx.Delete(&SchemaMigration{}, m.Version).Error
+		})
+		if err != nil {
+			return done, fmt.Errorf("migration %04d_%s down: %w", m.Version, m.Name, err)
+		}
+		done = append(done, m)
+	}
+	return done, nil
+}
+
+// Status daftar semua migrasi beserta waktu diterapkan (nil = pending)
+func Status(db *gorm.DB) ([]StatusRow, error) {
+	list, applied, err := prepare(db)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []StatusRow
+	for _, m := range list {
+		row := StatusRow{Version: m.Version, Name: m.Name}
+		if sm, ok := applied[m.Version]; ok {
+			appliedAt := sm.AppliedAt
+			row.AppliedAt = &appliedAt
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+func prepare(db *gorm.DB) ([]Migration, map[int64]SchemaMigration, error) {
+	list, err := Load()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	trackSQL, err := render(db, `
+	CREATE TABLE IF NOT EXISTS schema_migrations (
+		version BIGINT PRIMARY KEY,
+		name VARCHAR(255) NOT NULL,
+		applied_at {{DATETIME}} NOT NULL
+	) {{TABLE_OPTIONS}};
+	CREATE TABLE IF NOT EXISTS schema_migration_attempts (
+		version BIGINT NOT NULL,
+		direction VARCHAR(4) NOT NULL,
+		started_at {{DATETIME}} NOT NULL,
+		PRIMARY KEY (version, direction)
+	) {{TABLE_OPTIONS}};
+	`)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, stmt := range splitStatements(trackSQL) {
+		if err := db.Exec(stmt).Error; err != nil {
+			return nil, nil, fmt.Errorf("create migration tables: %w", err)
+		}
+	}
+
+	var rows []SchemaMigration
+	if err := db.Order("version").Find(&rows).Error; err != nil {
+		return nil, nil, err
+	}
+	applied := map[int64]SchemaMigration{}
+	for _, row := range rows {
+		applied[row.Version] = row
+	}
+	return list, applied, nil
+}
+
+// run menjalankan satu arah migrasi dalam transaksi, finish mencatat hasilnya.
+// Percobaan dicatat dulu di luar transaksi supaya tetap ada kalau migrasinya gagal.
+func run(db *gorm.DB, m Migration, direction, script string, finish func(tx *gorm.DB) error) error {
+	var attempts []MigrationAttempt
+	if err := db.Where("version = ? AND direction = ?", m.Version, direction).Limit(1).Find(&attempts).Error; err != nil {
+		return err
+	}
+	resume := len(attempts) > 0
+	if resume {
+		log.Printf("migration %04d_%s %s: resuming a previous failed run from %s", m.Version, m.Name, direction, attempts[0].StartedAt.Format(time.RFC3339))
+	} else if err := db.Create(&MigrationAttempt{Version: m.Version, Direction: direction, StartedAt: time.Now()}).Error; err != nil {
+		return err
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := execScript(tx, script, resume); err != nil {
+			return err
+		}
+		if err := finish(tx); err != nil {
+			return err
+		}
+		return tx.Delete(&MigrationAttempt{}, "version = ? AND direction = ?", m.Version, direction).Error
+	})
+}
+
+// execScript menjalankan isi file satu statement per satu,
+// karena driver MySQL tidak menerima multi statement secara default.
+// resume = percobaan sebelumnya gagal di tengah, statement yang sudah
+// diterapkan olehnya dilewati (dan dicatat di log).
+func execScript(tx *gorm.DB, script string, resume bool) error {
+	script, err := render(tx, script)
+	if err != nil {
+		return err
+	}
+	for _, stmt := range splitStatements(script) {
+		err := tx.Exec(stmt).Error
+		if err != nil && resume && alreadyApplied(err) {
+			log.Printf("migration: skipped statement already applied by the previous run (%v): %s", err, stmt)
+			continue
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Error MySQL yang berarti DDL-nya sudah diterapkan oleh percobaan sebelumnya
+// (sudah ter-commit implisit walaupun migrasinya gagal)
+var mysqlAppliedErrors = map[uint16]bool{
+	1060: true, // ER_DUP_FIELDNAME: ADD COLUMN, kolom sudah ada
+	1061: true, // ER_DUP_KEYNAME: CREATE INDEX, index sudah ada
+	1091: true, // ER_CANT_DROP_FIELD_OR_KEY: DROP COLUMN / INDEX, sudah tidak ada
+}
+
+// alreadyApplied supaya migrasi MySQL yang gagal di tengah bisa dijalankan ulang.
+// Di luar itu error ini tetap menggagalkan migrasi, bisa jadi skema tidak sesuai harapan.
+// PostgreSQL dan SQLite tidak perlu karena DDL-nya ikut di-rollback.
+func alreadyApplied(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	return errors.As(err, &mysqlErr) && mysqlAppliedErrors[mysqlErr.Number]
+}
+
+func splitStatements(script string) []string {
+	var lines []string
+	for _, line := range strings.Split(script, "\n") {
+		if strings.HasPrefix(strings.TrimSpace(line), "--") {
+			continue
+		}
+		lines = append(lines, line)
+	}
+
+	var stmts []string
+	for _, stmt := range strings.Split(strings.Join(lines, "\n"), ";") {
+		if stmt = strings.TrimSpace(stmt); stmt != "" {
+			stmts = append(stmts, stmt)
+		}
+	}
+	return stmts
+}
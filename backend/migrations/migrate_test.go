@@ -0,0 +1,68 @@
+package migrations
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/glebarez/sqlite"
+	"github.com/go-sql-driver/mysql"
+	"gorm.io/gorm"
+)
+
+func TestAlreadyApplied(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "duplicate column", err: &mysql.MySQLError{Number: 1060, Message: "Duplicate column name 'shift_id'"}, want: true},
+		{name: "duplicate index", err: &mysql.MySQLError{Number: 1061, Message: "Duplicate key name 'idx_holidays_date'"}, want: true},
+		{name: "column already dropped", err: &mysql.MySQLError{Number: 1091, Message: "Can't DROP 'shift_id'; check that column/key exists"}, want: true},
+		{name: "wrapped", err: fmt.Errorf("exec: %w", &mysql.MySQLError{Number: 1060}), want: true},
+		{name: "duplicate entry", err: &mysql.MySQLError{Number: 1062, Message: "Duplicate entry 'annual' for key 'code'"}, want: false},
+		{name: "other error", err: errors.New("duplicate column name: shift_id"), want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := alreadyApplied(tt.err); got != tt.want {
+				t.Fatalf("alreadyApplied(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRunRecordsFailedAttempt(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "migrate.db")), &gorm.Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := prepare(db); err != nil {
+		t.Fatal(err)
+	}
+	m := Migration{Version: 99, Name: "test"}
+	noop := func(tx *gorm.DB) error { return nil }
+	attempts := func() int64 {
+		var n int64
+		if err := db.Model(&MigrationAttempt{}).Where("version = ?", m.Version).Count(&n).Error; err != nil {
+			t.Fatal(err)
+		}
+		return n
+	}
+
+	// Percobaan yang gagal tetap tercatat walaupun transaksinya di-rollback
+	if err := run(db, m, "up", "CREATE TABLE t (id INTEGER); SELECT * FROM missing;", noop); err == nil {
+		t.Fatal("run() = nil, want error")
+	}
+	if got := attempts(); got != 1 {
+		t.Fatalf("attempts after failure = %d, want 1", got)
+	}
+
+	if err := run(db, m, "up", "CREATE TABLE t (id INTEGER);", noop); err != nil {
+		t.Fatal(err)
+	}
+	if got := attempts(); got != 0 {
+		t.Fatalf("attempts after success = %d, want 0", got)
+	}
+}
@@ -0,0 +1,22 @@
+package models
+
+import (
+	"time"
+)
+
+// AbsenceRecord dicatat job harian untuk employee yang sama sekali tidak clock in
+// pada hari kerjanya (tanpa cuti approved)
+type AbsenceRecord struct {
+	ID           uint      `gorm:"primaryKey" json:"id"`
+	EmployeeID   string    `gorm:"type:varchar(50);not null" json:"employee_id"`
+	BusinessDate string    `gorm:"type:varchar(10);not null" json:"business_date"` // YYYY-MM-DD
+	RuleSnapshot string    `gorm:"type:text" json:"rule_snapshot"`                 // JSON schedule.Rule hari itu
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+
+	Employee Employee `gorm:"foreignKey:EmployeeID;references:EmployeeID"`
+}
+
+func (AbsenceRecord) TableName() string {
+	return "absence_records"
+}
@@ -8,8 +8,9 @@ type Attendance struct {
 	AttendanceID string     `gorm:"type:varchar(100);not null" json:"attendance_id"`
 	ClockIn      time.Time  `gorm:"type:timestamp" json:"clock_in"`
 	ClockOut     *time.Time `gorm:"type:timestamp" json:"clock_out"`
+	BusinessDate string     `gorm:"type:varchar(10)" json:"business_date"` // YYYY-MM-DD, tanggal mulai shift
 	CreatedAt    time.Time  `json:"created_at"`
 	UpdatedAt    time.Time  `json:"updated_at"`
 
-	Employee          Employee `gorm:"foreignKey:EmployeeID;references:EmployeeID"`
+	Employee Employee `gorm:"foreignKey:EmployeeID;references:EmployeeID"`
 }
@@ -0,0 +1,46 @@
+package models
+
+import (
+	"time"
+)
+
+// Status pengajuan koreksi absensi
+const (
+	CorrectionPending  = "pending"
+	CorrectionApproved = "approved"
+	CorrectionRejected = "rejected"
+)
+
+// AttendanceCorrection pengajuan perubahan jam clock in / clock out yang baru
+// diterapkan ke attendance setelah di-approve manager / HR
+type AttendanceCorrection struct {
+	ID           uint       `gorm:"primaryKey" json:"id"`
+	AttendanceID string     `gorm:"type:varchar(100);not null" json:"attendance_id"`
+	EmployeeID   string     `gorm:"type:varchar(50);not null" json:"employee_id"`
+	ClockIn      *time.Time `gorm:"type:timestamp" json:"clock_in"`  // jam baru, nil = tidak diubah
+	ClockOut     *time.Time `gorm:"type:timestamp" json:"clock_out"` // jam baru, nil = tidak diubah
+	OldClockIn   time.Time  `gorm:"type:timestamp" json:"old_clock_in"`
+	OldClockOut  *time.Time `gorm:"type:timestamp" json:"old_clock_out"`
+	Reason       string     `gorm:"type:text;not null" json:"reason"`
+	Status       string     `gorm:"type:varchar(20);not null" json:"status"`
+	RequestedBy  *uint      `json:"requested_by"` // user yang mengajukan
+	ReviewedBy   *uint      `json:"reviewed_by"`  // user yang approve / reject
+	ReviewedAt   *time.Time `gorm:"type:timestamp" json:"reviewed_at"`
+	ReviewNote   string     `gorm:"type:text" json:"review_note"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+
+	Employee   Employee   `gorm:"foreignKey:EmployeeID;references:EmployeeID"`
+	Attendance Attendance `gorm:"foreignKey:AttendanceID;references:AttendanceID"`
+}
+
+func (AttendanceCorrection) TableName() string {
+	return "attendance_corrections"
+}
+
+// CorrectionValues jam clock in / clock out sebelum atau sesudah koreksi,
+// disimpan sebagai JSON di attendance_histories.old_value / new_value
+type CorrectionValues struct {
+	ClockIn  time.Time  `json:"clock_in"`
+	ClockOut *time.Time `json:"clock_out"`
+}
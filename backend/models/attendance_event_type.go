@@ -0,0 +1,98 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// AttendanceEventType jenis event di attendance_histories. Di database tetap disimpan
+// sebagai angka (kompatibel dengan data lama), di API ditulis dengan nama.
+type AttendanceEventType int
+
+const (
+	AttendanceTypeClockIn    AttendanceEventType = 1
+	AttendanceTypeClockOut   AttendanceEventType = 2
+	AttendanceTypeLeave      AttendanceEventType = 3 // hanya di log, cuti approved
+	AttendanceTypeAbsent     AttendanceEventType = 4 // hanya di log, dicatat job deteksi absen
+	AttendanceTypeCorrection AttendanceEventType = 5 // koreksi yang sudah di-approve
+	AttendanceTypeBreakStart AttendanceEventType = 6
+	AttendanceTypeBreakEnd   AttendanceEventType = 7
+)
+
+// Nama event di API. Event baru cukup ditambahkan di sini.
+var attendanceEventNames = map[AttendanceEventType]string{
+	AttendanceTypeClockIn:    "clock_in",
+	AttendanceTypeClockOut:   "clock_out",
+	AttendanceTypeLeave:      "leave",
+	AttendanceTypeAbsent:     "absent",
+	AttendanceTypeCorrection: "correction",
+	AttendanceTypeBreakStart: "break_start",
+	AttendanceTypeBreakEnd:   "break_end",
+}
+
+// AttendanceEventTypes semua jenis event yang dikenal, urut sesuai nilainya
+func AttendanceEventTypes() []AttendanceEventType {
+	types := make([]AttendanceEventType, 0, len(attendanceEventNames))
+	for t := range attendanceEventNames {
+		types = append(types, t)
+	}
+	slices.Sort(types)
+	return types
+}
+
+// Valid true kalau nilainya jenis event yang dikenal
+func (t AttendanceEventType) Valid() bool {
+	_, ok := attendanceEventNames[t]
+	return ok
+}
+
+// IsPunch clock in / clock out, satu-satunya event yang punya status ketepatan waktu
+func (t AttendanceEventType) IsPunch() bool {
+	return t == AttendanceTypeClockIn || t == AttendanceTypeClockOut
+}
+
+func (t AttendanceEventType) String() string {
+	if name, ok := attendanceEventNames[t]; ok {
+		return name
+	}
+	return "unknown"
+}
+
+// ParseAttendanceEventType nama event ("clock_in", ...). Angka lama ("1", "2") masih diterima.
+func ParseAttendanceEventType(value string) (AttendanceEventType, error) {
+	value = strings.TrimSpace(value)
+	for t, name := range attendanceEventNames {
+		if name == value {
+			return t, nil
+		}
+	}
+	if n, err := strconv.Atoi(value); err == nil && AttendanceEventType(n).Valid() {
+		return AttendanceEventType(n), nil
+	}
+	return 0, fmt.Errorf("unknown attendance type %q", value)
+}
+
+func (t AttendanceEventType) MarshalJSON() ([]byte, error) {
+	return json.Marshal(t.String())
+}
+
+func (t *AttendanceEventType) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err != nil {
+		// Client lama masih mengirim angka
+		var n int
+		if err := json.Unmarshal(data, &n); err != nil {
+			return fmt.Errorf("attendance type must be a name or number: %s", data)
+		}
+		name = strconv.Itoa(n)
+	}
+	parsed, err := ParseAttendanceEventType(name)
+	if err != nil {
+		return err
+	}
+	*t = parsed
+	return nil
+}
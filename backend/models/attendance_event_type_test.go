@@ -0,0 +1,56 @@
+package models
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+)
+
+func TestAttendanceEventTypes(t *testing.T) {
+	types := AttendanceEventTypes()
+	if len(types) != len(attendanceEventNames) {
+		t.Fatalf("AttendanceEventTypes() = %v, want every named type", types)
+	}
+	if !slices.IsSorted(types) {
+		t.Fatalf("AttendanceEventTypes() = %v, want sorted by value", types)
+	}
+}
+
+func TestParseAttendanceEventType(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    AttendanceEventType
+		wantErr bool
+	}{
+		{value: "clock_in", want: AttendanceTypeClockIn},
+		{value: " break_end ", want: AttendanceTypeBreakEnd},
+		{value: "2", want: AttendanceTypeClockOut}, // angka lama
+		{value: "99", wantErr: true},
+		{value: "coffee", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseAttendanceEventType(tt.value)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseAttendanceEventType(%q) = %v, %v", tt.value, got, err)
+		}
+	}
+}
+
+func TestAttendanceEventTypeJSON(t *testing.T) {
+	var payload struct {
+		Types []AttendanceEventType `json:"types"`
+	}
+	if err := json.Unmarshal([]byte(`{"types":["break_start",1]}`), &payload); err != nil {
+		t.Fatal(err)
+	}
+	out, err := json.Marshal(payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(out) != `{"types":["break_start","clock_in"]}` {
+		t.Fatalf("round trip = %s", out)
+	}
+	if err := json.Unmarshal([]byte(`{"types":[true]}`), &payload); err == nil {
+		t.Fatal("boolean accepted as attendance type")
+	}
+}
@@ -4,15 +4,40 @@ import (
 	"time"
 )
 
+// Status review clock out yang ditutup otomatis
+const (
+	ReviewPending   = "pending"
+	ReviewConfirmed = "confirmed" // jam auto-close dianggap benar
+	ReviewCorrected = "corrected" // jam clock out diganti HR
+)
+
 type AttendanceHistory struct {
-	ID             uint      `gorm:"primaryKey" json:"id"`
-	EmployeeID     string    `gorm:"type:varchar(50);not null" json:"employee_id"`
-	AttendanceID   string    `gorm:"type:varchar(100);not null" json:"attendance_id"`
-	DateAttendance time.Time `gorm:"type:timestamp" json:"date_attendance"`
-	AttendanceType int       `gorm:"type:tinyint" json:"attendance_type"` // 1=In, 2=Out
-	Description    string    `gorm:"type:text;" json:"description"`
-	CreatedAt      time.Time `json:"created_at"`
-	UpdatedAt      time.Time `json:"updated_at"`
+	ID             uint                `gorm:"primaryKey" json:"id"`
+	EmployeeID     string              `gorm:"type:varchar(50);not null" json:"employee_id"`
+	AttendanceID   string              `gorm:"type:varchar(100);not null" json:"attendance_id"`
+	DateAttendance time.Time           `gorm:"type:timestamp" json:"date_attendance"`
+	AttendanceType AttendanceEventType `gorm:"type:smallint" json:"attendance_type"` // lihat AttendanceEventType
+	Description    string              `gorm:"type:text;" json:"description"`
+
+	// Hasil evaluasi saat punch dicatat, tidak berubah kalau aturan department diedit
+	Status       string `gorm:"type:varchar(20)" json:"status"`
+	MinutesLate  int    `gorm:"not null" json:"minutes_late"`
+	MinutesEarly int    `gorm:"not null" json:"minutes_early"`
+	RuleSnapshot string `gorm:"type:text" json:"rule_snapshot"` // JSON schedule.Rule
+
+	// Clock out yang dicatat job auto-close karena employee lupa clock out
+	AutoClosed   bool       `gorm:"not null" json:"auto_closed"`
+	ReviewStatus string     `gorm:"type:varchar(20)" json:"review_status"`
+	ReviewedBy   *uint      `json:"reviewed_by"` // user HR yang confirm / correct, atau approver koreksi
+	ReviewedAt   *time.Time `gorm:"type:timestamp" json:"reviewed_at"`
+
+	// Baris koreksi (type 5): jam sebelum dan sesudah dalam JSON CorrectionValues
+	CorrectionID *uint  `json:"correction_id"`
+	OldValue     string `gorm:"type:text" json:"old_value"`
+	NewValue     string `gorm:"type:text" json:"new_value"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
 
 	Employee   Employee   `gorm:"foreignKey:EmployeeID;references:EmployeeID"`
 	Attendance Attendance `gorm:"foreignKey:AttendanceID;references:AttendanceID"`
@@ -2,17 +2,36 @@ package models
 
 import (
 	"time"
+
+	"gorm.io/gorm"
 )
 
 type Department struct {
-	ID              uint      `gorm:"primaryKey" json:"id"`
-	DepartmentName  string    `gorm:"type:varchar(255);not null" json:"department_name"`
-	MaxClockInTime  string    `gorm:"type:time;not null" json:"max_clock_in_time"`
-	MaxClockOutTime string    `gorm:"type:time;not null" json:"max_clock_out_time"`
-	CreatedAt       time.Time `json:"created_at"`
-	UpdatedAt       time.Time `json:"updated_at"`
+	ID              uint   `gorm:"primaryKey" json:"id"`
+	DepartmentName  string `gorm:"type:varchar(255);not null" json:"department_name"`
+	MaxClockInTime  string `gorm:"type:time;not null" json:"max_clock_in_time"`
+	MaxClockOutTime string `gorm:"type:time;not null" json:"max_clock_out_time"`
+	ShiftID         *uint  `json:"shift_id"`
+
+	// Toleransi keterlambatan (menit)
+	GracePeriodMinutes         int `gorm:"not null" json:"grace_period_minutes"`
+	SlightlyLateMinutes        int `gorm:"not null" json:"slightly_late_minutes"`
+	LateMinutes                int `gorm:"not null" json:"late_minutes"`
+	EarlyLeaveToleranceMinutes int `gorm:"not null" json:"early_leave_tolerance_minutes"`
+
+	// Aturan lembur (menit)
+	OvertimeMinMinutes      int `gorm:"not null" json:"overtime_min_minutes"`
+	OvertimeRoundingMinutes int `gorm:"not null" json:"overtime_rounding_minutes"`
+
+	// Minimal total istirahat per attendance (menit), 0 = tidak diwajibkan
+	MinBreakMinutes int `gorm:"not null" json:"min_break_minutes"`
+
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `json:"deleted_at"` // soft delete
 
 	Employees []Employee `gorm:"foreignKey:DepartmentID;references:ID"`
+	Shift     *Shift     `gorm:"foreignKey:ShiftID;references:ID"`
 }
 
 func (Department) TableName() string {
@@ -2,16 +2,27 @@ package models
 
 import (
 	"time"
+
+	"gorm.io/gorm"
 )
 
 type Employee struct {
-	ID           uint      `gorm:"primaryKey" json:"id"`
-	EmployeeID   string    `gorm:"unique;type:varchar(50)" json:"employee_id"`
-	DepartmentID uint      `gorm:"column:department_id;not null" json:"department_id"`
-	Name         string    `gorm:"type:varchar(255)" json:"name"`
-	Address      string    `gorm:"type:text" json:"address"`
-	CreatedAt    time.Time `json:"created_at"`
-	UpdatedAt    time.Time `json:"updated_at"`
+	ID           uint           `gorm:"primaryKey" json:"id"`
+	EmployeeID   string         `gorm:"unique;type:varchar(50)" json:"employee_id"`
+	DepartmentID uint           `gorm:"column:department_id;not null" json:"department_id"`
+	Name         string         `gorm:"type:varchar(255)" json:"name"`
+	Address      string         `gorm:"type:text" json:"address"`
+	ShiftID      *uint          `json:"shift_id"`                            // override shift department
+	TerminatedAt *time.Time     `gorm:"type:timestamp" json:"terminated_at"` // nil = masih aktif
+	CreatedAt    time.Time      `json:"created_at"`
+	UpdatedAt    time.Time      `json:"updated_at"`
+	DeletedAt    gorm.DeletedAt `json:"deleted_at"` // soft delete, attendance tetap disimpan
+
+	Department Department `gorm:"foreignKey:DepartmentID;references:ID"`
+	Shift      *Shift     `gorm:"foreignKey:ShiftID;references:ID"`
+}
 
-	Department        Department `gorm:"foreignKey:DepartmentID;references:ID"`
+// IsActiveAt false kalau employee sudah berhenti pada waktu t
+func (e Employee) IsActiveAt(t time.Time) bool {
+	return e.TerminatedAt == nil || t.Before(*e.TerminatedAt)
 }
@@ -0,0 +1,32 @@
+package models
+
+import (
+	"time"
+)
+
+// Jenis hari libur
+const (
+	HolidayNational = "national" // libur nasional
+	HolidayCompany  = "company"  // libur / cuti bersama perusahaan
+)
+
+type Holiday struct {
+	ID           uint      `gorm:"primaryKey" json:"id"`
+	Date         string    `gorm:"type:varchar(10);not null" json:"date"` // YYYY-MM-DD
+	Name         string    `gorm:"type:varchar(255);not null" json:"name"`
+	Type         string    `gorm:"type:varchar(20);not null" json:"type"`
+	DepartmentID *uint     `json:"department_id"` // nil = berlaku untuk semua department
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+
+	Department *Department `gorm:"foreignKey:DepartmentID;references:ID" json:"-"`
+}
+
+func (Holiday) TableName() string {
+	return "holidays"
+}
+
+// AppliesTo cek apakah hari libur berlaku untuk department tersebut
+func (h Holiday) AppliesTo(departmentID uint) bool {
+	return h.DepartmentID == nil || *h.DepartmentID == departmentID
+}
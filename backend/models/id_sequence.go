@@ -0,0 +1,19 @@
+package models
+
+import (
+	"time"
+)
+
+// IDSequence counter untuk kode EMP-xxx / ATT-xxx.
+// Period kosong kalau tidak reset tahunan, atau tahun (misal "2026").
+type IDSequence struct {
+	Name      string    `gorm:"primaryKey;type:varchar(50)" json:"name"`
+	Period    string    `gorm:"primaryKey;type:varchar(10)" json:"period"`
+	Value     int64     `gorm:"not null" json:"value"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+func (IDSequence) TableName() string {
+	return "id_sequences"
+}
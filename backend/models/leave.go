@@ -0,0 +1,87 @@
+package models
+
+import (
+	"time"
+)
+
+// Kode jenis cuti bawaan (di-seed lewat migrasi)
+const (
+	LeaveAnnual     = "annual"
+	LeaveSick       = "sick"
+	LeaveUnpaid     = "unpaid"
+	LeavePermission = "permission"
+)
+
+// Status pengajuan cuti
+const (
+	LeaveStatusPending   = "pending"
+	LeaveStatusApproved  = "approved"
+	LeaveStatusRejected  = "rejected"
+	LeaveStatusCancelled = "cancelled"
+)
+
+type LeaveType struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	Code        string    `gorm:"unique;type:varchar(30);not null" json:"code"`
+	Name        string    `gorm:"type:varchar(100);not null" json:"name"`
+	YearlyQuota int       `gorm:"not null" json:"yearly_quota"` // jatah hari kerja per tahun, 0 = tidak dibatasi
+	Paid        bool      `gorm:"not null" json:"paid"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+func (LeaveType) TableName() string {
+	return "leave_types"
+}
+
+// DefaultLeaveTypes sama dengan seed di migrasi 0013
+func DefaultLeaveTypes() []LeaveType {
+	return []LeaveType{
+		{Code: LeaveAnnual, Name: "Annual Leave", YearlyQuota: 12, Paid: true},
+		{Code: LeaveSick, Name: "Sick Leave", YearlyQuota: 0, Paid: true},
+		{Code: LeaveUnpaid, Name: "Unpaid Leave", YearlyQuota: 0, Paid: false},
+		{Code: LeavePermission, Name: "Permission", YearlyQuota: 3, Paid: true},
+	}
+}
+
+// EntitledDays jatah cuti pada tahun tertentu. Jatah diberikan penuh setiap
+// 1 Januari, tahun pertama dihitung proporsional dari bulan employee bergabung.
+func (t LeaveType) EntitledDays(joinedAt time.Time, year int) int {
+	switch {
+	case t.YearlyQuota == 0 || year < joinedAt.Year():
+		return 0
+	case year == joinedAt.Year():
+		months := 12 - int(joinedAt.Month()) + 1
+		return t.YearlyQuota * months / 12
+	}
+	return t.YearlyQuota
+}
+
+type LeaveRequest struct {
+	ID          uint       `gorm:"primaryKey" json:"id"`
+	EmployeeID  string     `gorm:"type:varchar(50);not null" json:"employee_id"`
+	LeaveTypeID uint       `gorm:"not null" json:"leave_type_id"`
+	StartDate   string     `gorm:"type:varchar(10);not null" json:"start_date"` // YYYY-MM-DD
+	EndDate     string     `gorm:"type:varchar(10);not null" json:"end_date"`   // YYYY-MM-DD, inklusif
+	Days        int        `gorm:"not null" json:"days"`                        // hari kerja yang terpakai
+	Reason      string     `gorm:"type:text" json:"reason"`
+	Attachment  string     `gorm:"type:varchar(255)" json:"attachment"` // referensi lampiran (URL / nama file)
+	Status      string     `gorm:"type:varchar(20);not null" json:"status"`
+	ReviewedBy  *uint      `json:"reviewed_by"` // user yang approve / reject
+	ReviewedAt  *time.Time `gorm:"type:timestamp" json:"reviewed_at"`
+	ReviewNote  string     `gorm:"type:text" json:"review_note"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+
+	Employee  Employee  `gorm:"foreignKey:EmployeeID;references:EmployeeID"`
+	LeaveType LeaveType `gorm:"foreignKey:LeaveTypeID;references:ID"`
+}
+
+func (LeaveRequest) TableName() string {
+	return "leave_requests"
+}
+
+// Covers cek apakah tanggal (YYYY-MM-DD) ada di dalam rentang cuti
+func (l LeaveRequest) Covers(date string) bool {
+	return l.StartDate <= date && date <= l.EndDate
+}
@@ -0,0 +1,43 @@
+package models
+
+import (
+	"time"
+)
+
+// Jenis lembur
+const (
+	OvertimeWeekday = "weekday" // lewat jam pulang di hari kerja
+	OvertimeHoliday = "holiday" // masuk di hari libur / hari di luar shift
+)
+
+// Status approval lembur
+const (
+	OvertimePending  = "pending"
+	OvertimeApproved = "approved"
+	OvertimeRejected = "rejected"
+)
+
+// Overtime lembur satu attendance, dihitung saat clock out dan baru masuk
+// rekap setelah di-approve manager / HR
+type Overtime struct {
+	ID             uint       `gorm:"primaryKey" json:"id"`
+	AttendanceID   string     `gorm:"type:varchar(100);uniqueIndex;not null" json:"attendance_id"`
+	EmployeeID     string     `gorm:"type:varchar(50);not null" json:"employee_id"`
+	BusinessDate   string     `gorm:"type:varchar(10);not null" json:"business_date"` // YYYY-MM-DD
+	Type           string     `gorm:"type:varchar(20);not null" json:"type"`
+	WorkedMinutes  int        `gorm:"not null" json:"worked_minutes"`
+	RegularMinutes int        `gorm:"not null" json:"regular_minutes"`
+	Minutes        int        `gorm:"not null" json:"minutes"` // lembur setelah pembulatan
+	Status         string     `gorm:"type:varchar(20);not null" json:"status"`
+	ReviewedBy     *uint      `json:"reviewed_by"` // user yang approve / reject
+	ReviewedAt     *time.Time `gorm:"type:timestamp" json:"reviewed_at"`
+	ReviewNote     string     `gorm:"type:text" json:"review_note"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+
+	Employee Employee `gorm:"foreignKey:EmployeeID;references:EmployeeID"`
+}
+
+func (Overtime) TableName() string {
+	return "overtimes"
+}
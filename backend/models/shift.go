@@ -0,0 +1,35 @@
+package models
+
+import (
+	"time"
+)
+
+// Shift jadwal kerja per hari (Senin-Minggu), bisa dipasang ke department
+// atau langsung ke employee sebagai override.
+type Shift struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	Name      string    `gorm:"type:varchar(255);not null" json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+
+	Days []ShiftDay `gorm:"foreignKey:ShiftID;references:ID" json:"days"`
+}
+
+type ShiftDay struct {
+	ID           uint    `gorm:"primaryKey" json:"id"`
+	ShiftID      uint    `gorm:"not null" json:"shift_id"`
+	Weekday      int     `gorm:"type:smallint;not null" json:"weekday"` // 0=Minggu ... 6=Sabtu (time.Weekday)
+	IsWorkingDay bool    `gorm:"not null" json:"is_working_day"`
+	StartTime    *string `gorm:"type:time" json:"start_time"` // HH:mm:ss, nil kalau libur
+	EndTime      *string `gorm:"type:time" json:"end_time"`
+}
+
+// Day aturan shift untuk hari tertentu
+func (s Shift) Day(weekday time.Weekday) (ShiftDay, bool) {
+	for _, day := range s.Days {
+		if day.Weekday == int(weekday) {
+			return day, true
+		}
+	}
+	return ShiftDay{}, false
+}
@@ -0,0 +1,34 @@
+package models
+
+import (
+	"time"
+)
+
+// Role user untuk RBAC
+const (
+	RoleAdmin    = "admin"
+	RoleHR       = "hr"
+	RoleManager  = "manager"
+	RoleEmployee = "employee"
+)
+
+type User struct {
+	ID           uint      `gorm:"primaryKey" json:"id"`
+	Username     string    `gorm:"unique;type:varchar(100);not null" json:"username"`
+	PasswordHash string    `gorm:"type:varchar(255);not null" json:"-"`
+	Role         string    `gorm:"type:varchar(20);not null;default:employee" json:"role"`
+	EmployeeID   *string   `gorm:"type:varchar(50)" json:"employee_id"` // employee yang terhubung dengan user
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+
+	Employee *Employee `gorm:"foreignKey:EmployeeID;references:EmployeeID"`
+}
+
+// IsValidRole cek apakah role dikenal
+func IsValidRole(role string) bool {
+	switch role {
+	case RoleAdmin, RoleHR, RoleManager, RoleEmployee:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,293 @@
+package repositories
+
+import (
+	"fleetify-backend/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// HistoryFilter filter untuk log absensi, field kosong berarti tidak difilter
+type HistoryFilter struct {
+	DateFrom     string // attendances.business_date >= DateFrom (YYYY-MM-DD)
+	DateTo       string // attendances.business_date <= DateTo (YYYY-MM-DD)
+	EmployeeID   string
+	AttendanceID string
+	Types        []models.AttendanceEventType // attendance_type, kosong = semua
+	DepartmentID *uint
+	ReviewStatus string // hanya history auto-close dengan status review ini
+}
+
+type AttendanceRepository interface {
+	FindByAttendanceID(attendanceID string) (*models.Attendance, error)
+	// LockByAttendanceID seperti FindByAttendanceID tapi mengunci barisnya (SELECT ... FOR UPDATE)
+	// sampai transaksi selesai. Hanya berguna di dalam Transaction.
+	LockByAttendanceID(attendanceID string) (*models.Attendance, error)
+	// FindOpenByEmployee attendance employee yang belum clock out (ErrNotFound kalau tidak ada)
+	FindOpenByEmployee(employeeID string) (*models.Attendance, error)
+	// FindOpen semua attendance yang belum clock out beserta employee dan jadwalnya
+	FindOpen() ([]models.Attendance, error)
+	// CodesWithPrefix semua attendance_id yang diawali prefix + "-"
+	CodesWithPrefix(prefix string) ([]string, error)
+	Create(attendance *models.Attendance) error
+	Update(attendance *models.Attendance) error
+	CreateHistory(history *models.AttendanceHistory) error
+	UpdateHistory(history *models.AttendanceHistory) error
+	// FindHistories riwayat absensi beserta employee, department dan attendance
+	FindHistories(filter HistoryFilter) ([]models.AttendanceHistory, error)
+	FindHistoryByID(id uint) (*models.AttendanceHistory, error)
+	// LockHistoryByID seperti FindHistoryByID tapi mengunci barisnya (SELECT ... FOR UPDATE)
+	// sampai transaksi selesai. Hanya berguna di dalam Transaction.
+	LockHistoryByID(id uint) (*models.AttendanceHistory, error)
+	// FindAll attendance beserta employee dan jadwalnya, urut tanggal bisnis.
+	// Hanya DateFrom, DateTo, EmployeeID dan DepartmentID yang dipakai.
+	FindAll(filter HistoryFilter) ([]models.Attendance, error)
+	// FindByBusinessDate semua attendance pada satu tanggal bisnis
+	FindByBusinessDate(businessDate string) ([]models.Attendance, error)
+	// FindAbsences catatan tidak masuk beserta employee dan department
+	FindAbsences(filter HistoryFilter) ([]models.AbsenceRecord, error)
+	CreateAbsence(absence *models.AbsenceRecord) error
+	// DeleteAbsences hapus catatan tidak masuk employee di rentang tanggal (inklusif)
+	DeleteAbsences(employeeID string, dateFrom, dateTo string) error
+	// DeleteAbsencesOn hapus catatan tidak masuk pada tanggal tertentu, departmentID nil = semua department
+	DeleteAbsencesOn(date string, departmentID *uint) error
+	// DeleteByEmployee hapus semua history, attendance, koreksi, lembur dan catatan tidak masuk milik employee
+	DeleteByEmployee(employeeID string) error
+}
+
+type gormAttendanceRepository struct {
+	db *gorm.DB
+}
+
+func NewGormAttendanceRepository(db *gorm.DB) AttendanceRepository {
+	return &gormAttendanceRepository{db: db}
+}
+
+func (r *gormAttendanceRepository) FindByAttendanceID(attendanceID string) (*models.Attendance, error) {
+	var attendance models.Attendance
+	if err := r.db.Where("attendance_id = ?", attendanceID).First(&attendance).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &attendance, nil
+}
+
+func (r *gormAttendanceRepository) LockByAttendanceID(attendanceID string) (*models.Attendance, error) {
+	var attendance models.Attendance
+	err := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("attendance_id = ?", attendanceID).
+		First(&attendance).Error
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return &attendance, nil
+}
+
+func (r *gormAttendanceRepository) FindOpenByEmployee(employeeID string) (*models.Attendance, error) {
+	var attendance models.Attendance
+	err := r.db.Where("employee_id = ? AND clock_out IS NULL", employeeID).
+		Order("clock_in DESC").
+		First(&attendance).Error
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return &attendance, nil
+}
+
+func (r *gormAttendanceRepository) FindOpen() ([]models.Attendance, error) {
+	var attendances []models.Attendance
+	err := r.db.
+		Preload("Employee", unscoped).
+		Preload("Employee.Department", unscoped).
+		Preload("Employee.Department.Shift.Days").
+		Preload("Employee.Shift.Days").
+		Where("clock_out IS NULL").
+		Order("clock_in, id").
+		Find(&attendances).Error
+	return attendances, err
+}
+
+func (r *gormAttendanceRepository) CodesWithPrefix(prefix string) ([]string, error) {
+	var codes []string
+	err := r.db.Model(&models.Attendance{}).Where("attendance_id LIKE ?", prefix+"-%").Pluck("attendance_id", &codes).Error
+	return codes, err
+}
+
+func (r *gormAttendanceRepository) Create(attendance *models.Attendance) error {
+	return r.db.Omit(clause.Associations).Create(attendance).Error
+}
+
+func (r *gormAttendanceRepository) Update(attendance *models.Attendance) error {
+	return r.db.Omit(clause.Associations).Save(attendance).Error
+}
+
+func (r *gormAttendanceRepository) CreateHistory(history *models.AttendanceHistory) error {
+	return r.db.Omit(clause.Associations).Create(history).Error
+}
+
+func (r *gormAttendanceRepository) UpdateHistory(history *models.AttendanceHistory) error {
+	return r.db.Omit(clause.Associations).Save(history).Error
+}
+
+func (r *gormAttendanceRepository) FindHistories(filter HistoryFilter) ([]models.AttendanceHistory, error) {
+	db := r.db.Scopes(preloadHistoryRelations)
+
+	// Filter tanggal bisnis, supaya satu shift malam tidak terpecah ke dua hari
+	if filter.DateFrom != "" || filter.DateTo != "" {
+		db = db.Joins("JOIN attendances ON attendance_histories.attendance_id = attendances.attendance_id")
+	}
+	if filter.DateFrom != "" {
+		db = db.Where("attendances.business_date >= ?", filter.DateFrom)
+	}
+	if filter.DateTo != "" {
+		db = db.Where("attendances.business_date <= ?", filter.DateTo)
+	}
+
+	if filter.EmployeeID != "" {
+		db = db.Where("attendance_histories.employee_id = ?", filter.EmployeeID)
+	}
+	if filter.AttendanceID != "" {
+		db = db.Where("attendance_histories.attendance_id = ?", filter.AttendanceID)
+	}
+	if len(filter.Types) > 0 {
+		db = db.Where("attendance_histories.attendance_type IN ?", filter.Types)
+	}
+	if filter.ReviewStatus != "" {
+		db = db.Where("attendance_histories.auto_closed = ? AND attendance_histories.review_status = ?", true, filter.ReviewStatus)
+	}
+
+	// Filter department
+	if filter.DepartmentID != nil {
+		db = db.Joins("JOIN employees ON attendance_histories.employee_id = employees.employee_id").
+			Where("employees.department_id = ?", *filter.DepartmentID)
+	}
+
+	var histories []models.AttendanceHistory
+	err := db.Find(&histories).Error
+	return histories, err
+}
+
+func (r *gormAttendanceRepository) FindHistoryByID(id uint) (*models.AttendanceHistory, error) {
+	var history models.AttendanceHistory
+	if err := r.db.Scopes(preloadHistoryRelations).First(&history, id).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &history, nil
+}
+
+func (r *gormAttendanceRepository) LockHistoryByID(id uint) (*models.AttendanceHistory, error) {
+	var history models.AttendanceHistory
+	err := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Scopes(preloadHistoryRelations).
+		First(&history, id).Error
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return &history, nil
+}
+
+func (r *gormAttendanceRepository) FindAll(filter HistoryFilter) ([]models.Attendance, error) {
+	db := r.db.
+		Preload("Employee", unscoped).
+		Preload("Employee.Department", unscoped).
+		Preload("Employee.Department.Shift.Days").
+		Preload("Employee.Shift.Days")
+
+	if filter.DateFrom != "" {
+		db = db.Where("attendances.business_date >= ?", filter.DateFrom)
+	}
+	if filter.DateTo != "" {
+		db = db.Where("attendances.business_date <= ?", filter.DateTo)
+	}
+	if filter.EmployeeID != "" {
+		db = db.Where("attendances.employee_id = ?", filter.EmployeeID)
+	}
+	if filter.DepartmentID != nil {
+		db = db.Joins("JOIN employees ON attendances.employee_id = employees.employee_id").
+			Where("employees.department_id = ?", *filter.DepartmentID)
+	}
+
+	var attendances []models.Attendance
+	err := db.Order("attendances.business_date, attendances.clock_in").Find(&attendances).Error
+	return attendances, err
+}
+
+func (r *gormAttendanceRepository) FindByBusinessDate(businessDate string) ([]models.Attendance, error) {
+	var attendances []models.Attendance
+	err := r.db.Where("business_date = ?", businessDate).Find(&attendances).Error
+	return attendances, err
+}
+
+func (r *gormAttendanceRepository) FindAbsences(filter HistoryFilter) ([]models.AbsenceRecord, error) {
+	db := r.db.
+		Preload("Employee", unscoped).
+		Preload("Employee.Department", unscoped).
+		Preload("Employee.Department.Shift.Days").
+		Preload("Employee.Shift.Days")
+
+	if filter.DateFrom != "" {
+		db = db.Where("absence_records.business_date >= ?", filter.DateFrom)
+	}
+	if filter.DateTo != "" {
+		db = db.Where("absence_records.business_date <= ?", filter.DateTo)
+	}
+	if filter.EmployeeID != "" {
+		db = db.Where("absence_records.employee_id = ?", filter.EmployeeID)
+	}
+	if filter.DepartmentID != nil {
+		db = db.Joins("JOIN employees ON absence_records.employee_id = employees.employee_id").
+			Where("employees.department_id = ?", *filter.DepartmentID)
+	}
+
+	var absences []models.AbsenceRecord
+	err := db.Order("absence_records.business_date, absence_records.id").Find(&absences).Error
+	return absences, err
+}
+
+func (r *gormAttendanceRepository) CreateAbsence(absence *models.AbsenceRecord) error {
+	return r.db.Omit(clause.Associations).Create(absence).Error
+}
+
+func (r *gormAttendanceRepository) DeleteAbsences(employeeID string, dateFrom, dateTo string) error {
+	return r.db.Where("employee_id = ? AND business_date >= ? AND business_date <= ?", employeeID, dateFrom, dateTo).
+		Delete(&models.AbsenceRecord{}).Error
+}
+
+func (r *gormAttendanceRepository) DeleteAbsencesOn(date string, departmentID *uint) error {
+	db := r.db.Where("business_date = ?", date)
+	if departmentID != nil {
+		db = db.Where("employee_id IN (?)",
+			r.db.Unscoped().Model(&models.Employee{}).Select("employee_id").Where("department_id = ?", *departmentID))
+	}
+	return db.Delete(&models.AbsenceRecord{}).Error
+}
+
+func (r *gormAttendanceRepository) DeleteByEmployee(employeeID string) error {
+	if err := r.db.Where("employee_id = ?", employeeID).Delete(&models.Overtime{}).Error; err != nil {
+		return err
+	}
+	if err := r.db.Where("employee_id = ?", employeeID).Delete(&models.AttendanceCorrection{}).Error; err != nil {
+		return err
+	}
+	if err := r.db.Where("employee_id = ?", employeeID).Delete(&models.AbsenceRecord{}).Error; err != nil {
+		return err
+	}
+	if err := r.db.Where("employee_id = ?", employeeID).Delete(&models.AttendanceHistory{}).Error; err != nil {
+		return err
+	}
+	return r.db.Where("employee_id = ?", employeeID).Delete(&models.Attendance{}).Error
+}
+
+// preloadHistoryRelations employee / department yang sudah di-soft delete tetap
+// ditampilkan lengkap di log
+func preloadHistoryRelations(db *gorm.DB) *gorm.DB {
+	return db.
+		Preload("Employee", unscoped).
+		Preload("Employee.Department", unscoped).
+		Preload("Employee.Department.Shift.Days").
+		Preload("Employee.Shift.Days").
+		Preload("Attendance")
+}
+
+func unscoped(db *gorm.DB) *gorm.DB {
+	return db.Unscoped()
+}
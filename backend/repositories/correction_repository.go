@@ -0,0 +1,95 @@
+package repositories
+
+import (
+	"fleetify-backend/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CorrectionFilter filter pengajuan koreksi absensi, field kosong berarti tidak difilter
+type CorrectionFilter struct {
+	AttendanceID string
+	EmployeeID   string
+	DepartmentID *uint
+	Statuses     []string
+}
+
+type CorrectionRepository interface {
+	// FindAll pengajuan koreksi beserta employee dan attendance, terbaru dulu
+	FindAll(filter CorrectionFilter) ([]models.AttendanceCorrection, error)
+	FindByID(id uint) (*models.AttendanceCorrection, error)
+	// LockByID seperti FindByID tapi mengunci barisnya (SELECT ... FOR UPDATE) sampai
+	// transaksi selesai. Hanya berguna di dalam Transaction.
+	LockByID(id uint) (*models.AttendanceCorrection, error)
+	Create(correction *models.AttendanceCorrection) error
+	Update(correction *models.AttendanceCorrection) error
+}
+
+type gormCorrectionRepository struct {
+	db *gorm.DB
+}
+
+func NewGormCorrectionRepository(db *gorm.DB) CorrectionRepository {
+	return &gormCorrectionRepository{db: db}
+}
+
+func (r *gormCorrectionRepository) FindAll(filter CorrectionFilter) ([]models.AttendanceCorrection, error) {
+	db := r.db.Scopes(preloadCorrectionRelations)
+
+	if filter.AttendanceID != "" {
+		db = db.Where("attendance_corrections.attendance_id = ?", filter.AttendanceID)
+	}
+	if filter.EmployeeID != "" {
+		db = db.Where("attendance_corrections.employee_id = ?", filter.EmployeeID)
+	}
+	if len(filter.Statuses) > 0 {
+		db = db.Where("attendance_corrections.status IN ?", filter.Statuses)
+	}
+	if filter.DepartmentID != nil {
+		db = db.Joins("JOIN employees ON attendance_corrections.employee_id = employees.employee_id").
+			Where("employees.department_id = ?", *filter.DepartmentID)
+	}
+
+	var corrections []models.AttendanceCorrection
+	err := db.Order("attendance_corrections.id DESC").Find(&corrections).Error
+	return corrections, err
+}
+
+func (r *gormCorrectionRepository) FindByID(id uint) (*models.AttendanceCorrection, error) {
+	var correction models.AttendanceCorrection
+	if err := r.db.Scopes(preloadCorrectionRelations).First(&correction, id).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &correction, nil
+}
+
+func (r *gormCorrectionRepository) LockByID(id uint) (*models.AttendanceCorrection, error) {
+	var correction models.AttendanceCorrection
+	err := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Scopes(preloadCorrectionRelations).
+		First(&correction, id).Error
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return &correction, nil
+}
+
+func (r *gormCorrectionRepository) Create(correction *models.AttendanceCorrection) error {
+	return r.db.Omit(clause.Associations).Create(correction).Error
+}
+
+func (r *gormCorrectionRepository) Update(correction *models.AttendanceCorrection) error {
+	return r.db.Omit(clause.Associations).Save(correction).Error
+}
+
+// preloadCorrectionRelations employee (termasuk yang sudah dihapus) dengan jadwalnya,
+// supaya status punch bisa dihitung ulang saat koreksi diterapkan
+func preloadCorrectionRelations(db *gorm.DB) *gorm.DB {
+	return db.
+		Preload("Employee", unscoped).
+		Preload("Employee.Department", unscoped).
+		Preload("Employee.Department.Shift.Days").
+		Preload("Employee.Shift.Days").
+		Preload("Attendance")
+}
@@ -0,0 +1,92 @@
+package repositories
+
+import (
+	"fleetify-backend/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type DepartmentRepository interface {
+	// WithDeleted repository yang ikut membaca department yang sudah di-soft delete
+	WithDeleted() DepartmentRepository
+	// FindAll semua department beserta employees
+	FindAll() ([]models.Department, error)
+	// FindByID department beserta employees
+	FindByID(id uint) (*models.Department, error)
+	// LockByID mengunci baris department (SELECT ... FOR UPDATE) sampai transaksi
+	// selesai, dipakai delete department dan penambahan employee ke department
+	LockByID(id uint) error
+	Create(department *models.Department) error
+	Update(department *models.Department) error
+	// Delete soft delete, deleted_at hasilnya diisi ke department
+	Delete(department *models.Department) error
+	// Restore mengosongkan deleted_at
+	Restore(department *models.Department) error
+	// Purge hapus permanen dari database
+	Purge(department *models.Department) error
+}
+
+type gormDepartmentRepository struct {
+	db *gorm.DB
+}
+
+func NewGormDepartmentRepository(db *gorm.DB) DepartmentRepository {
+	return &gormDepartmentRepository{db: db}
+}
+
+func (r *gormDepartmentRepository) WithDeleted() DepartmentRepository {
+	return &gormDepartmentRepository{db: r.db.Unscoped()}
+}
+
+func (r *gormDepartmentRepository) FindAll() ([]models.Department, error) {
+	var departments []models.Department
+	err := r.db.Preload("Employees").Preload("Shift.Days").Find(&departments).Error
+	return departments, err
+}
+
+func (r *gormDepartmentRepository) FindByID(id uint) (*models.Department, error) {
+	var department models.Department
+	if err := r.db.Preload("Employees").Preload("Shift.Days").First(&department, id).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &department, nil
+}
+
+func (r *gormDepartmentRepository) LockByID(id uint) error {
+	var department models.Department
+	err := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("id").
+		First(&department, id).Error
+	return translateError(err)
+}
+
+func (r *gormDepartmentRepository) Create(department *models.Department) error {
+	return r.db.Omit(clause.Associations).Create(department).Error
+}
+
+func (r *gormDepartmentRepository) Update(department *models.Department) error {
+	return r.db.Omit(clause.Associations).Save(department).Error
+}
+
+func (r *gormDepartmentRepository) Delete(department *models.Department) error {
+	now := time.Now()
+	if err := r.db.Model(department).Omit(clause.Associations).Update("deleted_at", now).Error; err != nil {
+		return err
+	}
+	department.DeletedAt = gorm.DeletedAt{Time: now, Valid: true}
+	return nil
+}
+
+func (r *gormDepartmentRepository) Restore(department *models.Department) error {
+	if err := r.db.Unscoped().Model(department).Omit(clause.Associations).Update("deleted_at", nil).Error; err != nil {
+		return err
+	}
+	department.DeletedAt = gorm.DeletedAt{}
+	return nil
+}
+
+func (r *gormDepartmentRepository) Purge(department *models.Department) error {
+	return r.db.Unscoped().Delete(department).Error
+}
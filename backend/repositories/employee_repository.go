@@ -0,0 +1,154 @@
+package repositories
+
+import (
+	"fleetify-backend/models"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type EmployeeRepository interface {
+	// WithDeleted repository yang ikut membaca employee yang sudah di-soft delete
+	WithDeleted() EmployeeRepository
+	// FindAll semua employee beserta department
+	FindAll() ([]models.Employee, error)
+	// FindByID employee beserta department
+	FindByID(id uint) (*models.Employee, error)
+	// FindByEmployeeID cari berdasarkan kode EMP-xxx
+	FindByEmployeeID(employeeID string) (*models.Employee, error)
+	// LockByEmployeeID mengunci baris employee (SELECT ... FOR UPDATE) sampai transaksi
+	// selesai, untuk menyerialkan penulisan per employee. Hanya berguna di dalam Transaction.
+	LockByEmployeeID(employeeID string) error
+	FindByDepartment(departmentID uint) ([]models.Employee, error)
+	// CodesWithPrefix semua employee_id yang diawali prefix + "-"
+	CodesWithPrefix(prefix string) ([]string, error)
+	Create(employee *models.Employee) error
+	Update(employee *models.Employee) error
+	// Delete soft delete, attendance employee tetap tersimpan
+	Delete(employee *models.Employee) error
+	// MoveDepartment pindahkan semua employee dari satu department ke department lain
+	MoveDepartment(fromID, toID uint) (int64, error)
+	// DeleteByDepartment soft delete semua employee department dengan deleted_at yang sama
+	DeleteByDepartment(departmentID uint, deletedAt time.Time) error
+	// Restore mengosongkan deleted_at
+	Restore(employee *models.Employee) error
+	// RestoreByDepartment restore employee yang terhapus bersama department (deleted_at sama)
+	RestoreByDepartment(departmentID uint, deletedAt time.Time) (int64, error)
+	// Purge hapus permanen dari database
+	Purge(employee *models.Employee) error
+}
+
+type gormEmployeeRepository struct {
+	db *gorm.DB
+}
+
+func NewGormEmployeeRepository(db *gorm.DB) EmployeeRepository {
+	return &gormEmployeeRepository{db: db}
+}
+
+func (r *gormEmployeeRepository) WithDeleted() EmployeeRepository {
+	return &gormEmployeeRepository{db: r.db.Unscoped()}
+}
+
+func (r *gormEmployeeRepository) FindAll() ([]models.Employee, error) {
+	var employees []models.Employee
+	err := r.db.Scopes(preloadEmployeeSchedule).Find(&employees).Error
+	return employees, err
+}
+
+func (r *gormEmployeeRepository) FindByID(id uint) (*models.Employee, error) {
+	var employee models.Employee
+	if err := r.db.Scopes(preloadEmployeeSchedule).First(&employee, id).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &employee, nil
+}
+
+func (r *gormEmployeeRepository) FindByEmployeeID(employeeID string) (*models.Employee, error) {
+	var employee models.Employee
+	if err := r.db.Scopes(preloadEmployeeSchedule).Where("employee_id = ?", employeeID).First(&employee).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &employee, nil
+}
+
+func (r *gormEmployeeRepository) LockByEmployeeID(employeeID string) error {
+	var employee models.Employee
+	err := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("id").
+		Where("employee_id = ?", employeeID).
+		First(&employee).Error
+	return translateError(err)
+}
+
+func (r *gormEmployeeRepository) FindByDepartment(departmentID uint) ([]models.Employee, error) {
+	var employees []models.Employee
+	err := r.db.Where("department_id = ?", departmentID).Find(&employees).Error
+	return employees, err
+}
+
+func (r *gormEmployeeRepository) CodesWithPrefix(prefix string) ([]string, error) {
+	var codes []string
+	// Termasuk employee yang sudah dihapus, kodenya tidak boleh dipakai ulang
+	err := r.db.Unscoped().Model(&models.Employee{}).Where("employee_id LIKE ?", prefix+"-%").Pluck("employee_id", &codes).Error
+	return codes, err
+}
+
+func (r *gormEmployeeRepository) Create(employee *models.Employee) error {
+	return r.db.Omit(clause.Associations).Create(employee).Error
+}
+
+func (r *gormEmployeeRepository) Update(employee *models.Employee) error {
+	return r.db.Omit(clause.Associations).Save(employee).Error
+}
+
+func (r *gormEmployeeRepository) Delete(employee *models.Employee) error {
+	// deleted_at diisi manual supaya caller tahu nilainya
+	now := time.Now()
+	if err := r.db.Model(employee).Omit(clause.Associations).Update("deleted_at", now).Error; err != nil {
+		return err
+	}
+	employee.DeletedAt = gorm.DeletedAt{Time: now, Valid: true}
+	return nil
+}
+
+func (r *gormEmployeeRepository) MoveDepartment(fromID, toID uint) (int64, error) {
+	result := r.db.Model(&models.Employee{}).
+		Where("department_id = ?", fromID).
+		Update("department_id", toID)
+	return result.RowsAffected, result.Error
+}
+
+func (r *gormEmployeeRepository) DeleteByDepartment(departmentID uint, deletedAt time.Time) error {
+	return r.db.Model(&models.Employee{}).
+		Where("department_id = ?", departmentID).
+		Update("deleted_at", deletedAt).Error
+}
+
+func (r *gormEmployeeRepository) Restore(employee *models.Employee) error {
+	if err := r.db.Unscoped().Model(employee).Omit(clause.Associations).Update("deleted_at", nil).Error; err != nil {
+		return err
+	}
+	employee.DeletedAt = gorm.DeletedAt{}
+	return nil
+}
+
+func (r *gormEmployeeRepository) RestoreByDepartment(departmentID uint, deletedAt time.Time) (int64, error) {
+	result := r.db.Unscoped().Model(&models.Employee{}).
+		Where("department_id = ? AND deleted_at = ?", departmentID, deletedAt).
+		Update("deleted_at", nil)
+	return result.RowsAffected, result.Error
+}
+
+func (r *gormEmployeeRepository) Purge(employee *models.Employee) error {
+	return r.db.Unscoped().Delete(employee).Error
+}
+
+// preloadEmployeeSchedule preload department dan shift yang dibutuhkan schedule.Resolve
+func preloadEmployeeSchedule(db *gorm.DB) *gorm.DB {
+	return db.
+		Preload("Department").
+		Preload("Department.Shift.Days").
+		Preload("Shift.Days")
+}
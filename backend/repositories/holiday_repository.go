@@ -0,0 +1,74 @@
+package repositories
+
+import (
+	"fleetify-backend/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// HolidayFilter filter hari libur, field kosong berarti tidak difilter
+type HolidayFilter struct {
+	DateFrom string // YYYY-MM-DD, inklusif
+	DateTo   string
+	Type     string
+	// Hari libur yang berlaku untuk department ini (termasuk yang berlaku umum)
+	DepartmentID *uint
+}
+
+type HolidayRepository interface {
+	// FindAll hari libur urut tanggal
+	FindAll(filter HolidayFilter) ([]models.Holiday, error)
+	FindByID(id uint) (*models.Holiday, error)
+	Create(holiday *models.Holiday) error
+	Update(holiday *models.Holiday) error
+	Delete(holiday *models.Holiday) error
+}
+
+type gormHolidayRepository struct {
+	db *gorm.DB
+}
+
+func NewGormHolidayRepository(db *gorm.DB) HolidayRepository {
+	return &gormHolidayRepository{db: db}
+}
+
+func (r *gormHolidayRepository) FindAll(filter HolidayFilter) ([]models.Holiday, error) {
+	db := r.db
+	if filter.DateFrom != "" {
+		db = db.Where("date >= ?", filter.DateFrom)
+	}
+	if filter.DateTo != "" {
+		db = db.Where("date <= ?", filter.DateTo)
+	}
+	if filter.Type != "" {
+		db = db.Where("type = ?", filter.Type)
+	}
+	if filter.DepartmentID != nil {
+		db = db.Where("department_id IS NULL OR department_id = ?", *filter.DepartmentID)
+	}
+
+	var holidays []models.Holiday
+	err := db.Order("date, id").Find(&holidays).Error
+	return holidays, err
+}
+
+func (r *gormHolidayRepository) FindByID(id uint) (*models.Holiday, error) {
+	var holiday models.Holiday
+	if err := r.db.First(&holiday, id).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &holiday, nil
+}
+
+func (r *gormHolidayRepository) Create(holiday *models.Holiday) error {
+	return r.db.Omit(clause.Associations).Create(holiday).Error
+}
+
+func (r *gormHolidayRepository) Update(holiday *models.Holiday) error {
+	return r.db.Omit(clause.Associations).Save(holiday).Error
+}
+
+func (r *gormHolidayRepository) Delete(holiday *models.Holiday) error {
+	return r.db.Delete(holiday).Error
+}
@@ -0,0 +1,125 @@
+package repositories
+
+import (
+	"fleetify-backend/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// LeaveFilter filter pengajuan cuti, field kosong berarti tidak difilter
+type LeaveFilter struct {
+	EmployeeID   string
+	DepartmentID *uint
+	Statuses     []string
+	// Rentang tanggal (YYYY-MM-DD, inklusif), cuti yang beririsan ikut terambil
+	DateFrom string
+	DateTo   string
+}
+
+type LeaveRepository interface {
+	// FindTypes semua jenis cuti
+	FindTypes() ([]models.LeaveType, error)
+	FindTypeByID(id uint) (*models.LeaveType, error)
+	// FindAll pengajuan cuti beserta employee dan jenis cuti, urut tanggal mulai
+	FindAll(filter LeaveFilter) ([]models.LeaveRequest, error)
+	FindByID(id uint) (*models.LeaveRequest, error)
+	// LockByID seperti FindByID tapi mengunci barisnya (SELECT ... FOR UPDATE) sampai
+	// transaksi selesai. Hanya berguna di dalam Transaction.
+	LockByID(id uint) (*models.LeaveRequest, error)
+	Create(leave *models.LeaveRequest) error
+	Update(leave *models.LeaveRequest) error
+	// UpdateStatus hanya menyimpan kolom status, kolom review tidak ikut ditimpa
+	UpdateStatus(leave *models.LeaveRequest) error
+}
+
+type gormLeaveRepository struct {
+	db *gorm.DB
+}
+
+func NewGormLeaveRepository(db *gorm.DB) LeaveRepository {
+	return &gormLeaveRepository{db: db}
+}
+
+func (r *gormLeaveRepository) FindTypes() ([]models.LeaveType, error) {
+	var types []models.LeaveType
+	err := r.db.Order("id").Find(&types).Error
+	return types, err
+}
+
+func (r *gormLeaveRepository) FindTypeByID(id uint) (*models.LeaveType, error) {
+	var leaveType models.LeaveType
+	if err := r.db.First(&leaveType, id).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &leaveType, nil
+}
+
+func (r *gormLeaveRepository) FindAll(filter LeaveFilter) ([]models.LeaveRequest, error) {
+	db := r.db.Scopes(preloadLeaveRelations)
+
+	if filter.EmployeeID != "" {
+		db = db.Where("leave_requests.employee_id = ?", filter.EmployeeID)
+	}
+	if len(filter.Statuses) > 0 {
+		db = db.Where("leave_requests.status IN ?", filter.Statuses)
+	}
+	if filter.DateFrom != "" {
+		db = db.Where("leave_requests.end_date >= ?", filter.DateFrom)
+	}
+	if filter.DateTo != "" {
+		db = db.Where("leave_requests.start_date <= ?", filter.DateTo)
+	}
+	if filter.DepartmentID != nil {
+		db = db.Joins("JOIN employees ON leave_requests.employee_id = employees.employee_id").
+			Where("employees.department_id = ?", *filter.DepartmentID)
+	}
+
+	var leaves []models.LeaveRequest
+	err := db.Order("leave_requests.start_date, leave_requests.id").Find(&leaves).Error
+	return leaves, err
+}
+
+func (r *gormLeaveRepository) FindByID(id uint) (*models.LeaveRequest, error) {
+	var leave models.LeaveRequest
+	if err := r.db.Scopes(preloadLeaveRelations).First(&leave, id).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &leave, nil
+}
+
+func (r *gormLeaveRepository) LockByID(id uint) (*models.LeaveRequest, error) {
+	var leave models.LeaveRequest
+	err := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Scopes(preloadLeaveRelations).
+		First(&leave, id).Error
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return &leave, nil
+}
+
+func (r *gormLeaveRepository) Create(leave *models.LeaveRequest) error {
+	return r.db.Omit(clause.Associations).Create(leave).Error
+}
+
+func (r *gormLeaveRepository) Update(leave *models.LeaveRequest) error {
+	return r.db.Omit(clause.Associations).Save(leave).Error
+}
+
+func (r *gormLeaveRepository) UpdateStatus(leave *models.LeaveRequest) error {
+	return r.db.Model(leave).Omit(clause.Associations).
+		Select("status", "updated_at").
+		Updates(leave).Error
+}
+
+// preloadLeaveRelations employee (termasuk yang sudah dihapus) lengkap dengan
+// jadwalnya, supaya hari kerja cuti bisa dihitung
+func preloadLeaveRelations(db *gorm.DB) *gorm.DB {
+	return db.
+		Preload("Employee", unscoped).
+		Preload("Employee.Department", unscoped).
+		Preload("Employee.Department.Shift.Days").
+		Preload("Employee.Shift.Days").
+		Preload("LeaveType")
+}
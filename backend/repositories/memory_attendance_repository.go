@@ -0,0 +1,340 @@
+package repositories
+
+import (
+	"fleetify-backend/models"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+type memoryAttendanceRepository struct {
+	store *MemoryStore
+}
+
+func NewMemoryAttendanceRepository(store *MemoryStore) AttendanceRepository {
+	return &memoryAttendanceRepository{store: store}
+}
+
+func (r *memoryAttendanceRepository) FindByAttendanceID(attendanceID string) (*models.Attendance, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	att, ok := r.store.attendanceByCode(attendanceID)
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &att, nil
+}
+
+// LockByAttendanceID transaksi memory sudah berjalan satu per satu, jadi cukup dibaca ulang
+func (r *memoryAttendanceRepository) LockByAttendanceID(attendanceID string) (*models.Attendance, error) {
+	return r.FindByAttendanceID(attendanceID)
+}
+
+func (r *memoryAttendanceRepository) FindOpenByEmployee(employeeID string) (*models.Attendance, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	var open *models.Attendance
+	for _, att := range r.store.attendances {
+		if att.EmployeeID != employeeID || att.ClockOut != nil {
+			continue
+		}
+		if open == nil || att.ClockIn.After(open.ClockIn) {
+			found := att
+			open = &found
+		}
+	}
+	if open == nil {
+		return nil, ErrNotFound
+	}
+	return open, nil
+}
+
+func (r *memoryAttendanceRepository) FindOpen() ([]models.Attendance, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	var attendances []models.Attendance
+	for _, att := range sortedValues(r.store.attendances) {
+		if att.ClockOut != nil {
+			continue
+		}
+		if emp, ok := r.store.employeeByCode(att.EmployeeID); ok {
+			att.Employee = r.store.withDepartment(emp)
+		}
+		attendances = append(attendances, att)
+	}
+	sort.SliceStable(attendances, func(i, j int) bool { return attendances[i].ClockIn.Before(attendances[j].ClockIn) })
+	return attendances, nil
+}
+
+func (r *memoryAttendanceRepository) CodesWithPrefix(prefix string) ([]string, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	var codes []string
+	for _, att := range r.store.attendances {
+		if strings.HasPrefix(att.AttendanceID, prefix+"-") {
+			codes = append(codes, att.AttendanceID)
+		}
+	}
+	return codes, nil
+}
+
+func (r *memoryAttendanceRepository) Create(attendance *models.Attendance) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.employeeByCode(attendance.EmployeeID); !ok {
+		return fmt.Errorf("foreign key violation: employee %q does not exist", attendance.EmployeeID)
+	}
+	if _, ok := r.store.attendanceByCode(attendance.AttendanceID); ok {
+		return fmt.Errorf("duplicate attendance_id %q", attendance.AttendanceID)
+	}
+	attendance.ID = r.store.nextID("attendances")
+	now := time.Now()
+	attendance.CreatedAt = now
+	attendance.UpdatedAt = now
+
+	row := *attendance
+	row.Employee = models.Employee{}
+	r.store.attendances[row.ID] = row
+	return nil
+}
+
+func (r *memoryAttendanceRepository) Update(attendance *models.Attendance) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.attendances[attendance.ID]; !ok {
+		return ErrNotFound
+	}
+	attendance.UpdatedAt = time.Now()
+
+	row := *attendance
+	row.Employee = models.Employee{}
+	r.store.attendances[row.ID] = row
+	return nil
+}
+
+func (r *memoryAttendanceRepository) CreateHistory(history *models.AttendanceHistory) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.employeeByCode(history.EmployeeID); !ok {
+		return fmt.Errorf("foreign key violation: employee %q does not exist", history.EmployeeID)
+	}
+	if _, ok := r.store.attendanceByCode(history.AttendanceID); !ok {
+		return fmt.Errorf("foreign key violation: attendance %q does not exist", history.AttendanceID)
+	}
+	history.ID = r.store.nextID("attendance_histories")
+	now := time.Now()
+	history.CreatedAt = now
+	history.UpdatedAt = now
+
+	row := *history
+	row.Employee = models.Employee{}
+	row.Attendance = models.Attendance{}
+	r.store.histories[row.ID] = row
+	return nil
+}
+
+func (r *memoryAttendanceRepository) UpdateHistory(history *models.AttendanceHistory) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.histories[history.ID]; !ok {
+		return ErrNotFound
+	}
+	history.UpdatedAt = time.Now()
+
+	row := *history
+	row.Employee = models.Employee{}
+	row.Attendance = models.Attendance{}
+	r.store.histories[row.ID] = row
+	return nil
+}
+
+func (r *memoryAttendanceRepository) FindHistories(filter HistoryFilter) ([]models.AttendanceHistory, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	var histories []models.AttendanceHistory
+	for _, history := range sortedValues(r.store.histories) {
+		if filter.EmployeeID != "" && history.EmployeeID != filter.EmployeeID {
+			continue
+		}
+		if filter.AttendanceID != "" && history.AttendanceID != filter.AttendanceID {
+			continue
+		}
+		if len(filter.Types) > 0 && !slices.Contains(filter.Types, history.AttendanceType) {
+			continue
+		}
+		if filter.ReviewStatus != "" && (!history.AutoClosed || history.ReviewStatus != filter.ReviewStatus) {
+			continue
+		}
+		history = r.store.withHistoryRelations(history)
+		if filter.DateFrom != "" && history.Attendance.BusinessDate < filter.DateFrom {
+			continue
+		}
+		if filter.DateTo != "" && history.Attendance.BusinessDate > filter.DateTo {
+			continue
+		}
+		if filter.DepartmentID != nil && history.Employee.DepartmentID != *filter.DepartmentID {
+			continue
+		}
+		histories = append(histories, history)
+	}
+	return histories, nil
+}
+
+func (r *memoryAttendanceRepository) FindHistoryByID(id uint) (*models.AttendanceHistory, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	history, ok := r.store.histories[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	history = r.store.withHistoryRelations(history)
+	return &history, nil
+}
+
+// LockHistoryByID transaksi memory sudah berjalan satu per satu, jadi cukup dibaca ulang
+func (r *memoryAttendanceRepository) LockHistoryByID(id uint) (*models.AttendanceHistory, error) {
+	return r.FindHistoryByID(id)
+}
+
+func (r *memoryAttendanceRepository) FindAll(filter HistoryFilter) ([]models.Attendance, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	var attendances []models.Attendance
+	for _, att := range sortedValues(r.store.attendances) {
+		if filter.DateFrom != "" && att.BusinessDate < filter.DateFrom {
+			continue
+		}
+		if filter.DateTo != "" && att.BusinessDate > filter.DateTo {
+			continue
+		}
+		if filter.EmployeeID != "" && att.EmployeeID != filter.EmployeeID {
+			continue
+		}
+		if emp, ok := r.store.employeeByCode(att.EmployeeID); ok {
+			att.Employee = r.store.withDepartment(emp)
+		}
+		if filter.DepartmentID != nil && att.Employee.DepartmentID != *filter.DepartmentID {
+			continue
+		}
+		attendances = append(attendances, att)
+	}
+	sort.SliceStable(attendances, func(i, j int) bool {
+		if attendances[i].BusinessDate != attendances[j].BusinessDate {
+			return attendances[i].BusinessDate < attendances[j].BusinessDate
+		}
+		return attendances[i].ClockIn.Before(attendances[j].ClockIn)
+	})
+	return attendances, nil
+}
+
+func (r *memoryAttendanceRepository) FindByBusinessDate(businessDate string) ([]models.Attendance, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	var attendances []models.Attendance
+	for _, att := range sortedValues(r.store.attendances) {
+		if att.BusinessDate == businessDate {
+			attendances = append(attendances, att)
+		}
+	}
+	return attendances, nil
+}
+
+func (r *memoryAttendanceRepository) FindAbsences(filter HistoryFilter) ([]models.AbsenceRecord, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	var absences []models.AbsenceRecord
+	for _, absence := range sortedValues(r.store.absences) {
+		if filter.DateFrom != "" && absence.BusinessDate < filter.DateFrom {
+			continue
+		}
+		if filter.DateTo != "" && absence.BusinessDate > filter.DateTo {
+			continue
+		}
+		if filter.EmployeeID != "" && absence.EmployeeID != filter.EmployeeID {
+			continue
+		}
+		if emp, ok := r.store.employeeByCode(absence.EmployeeID); ok {
+			absence.Employee = r.store.withDepartment(emp)
+		}
+		if filter.DepartmentID != nil && absence.Employee.DepartmentID != *filter.DepartmentID {
+			continue
+		}
+		absences = append(absences, absence)
+	}
+	sort.SliceStable(absences, func(i, j int) bool { return absences[i].BusinessDate < absences[j].BusinessDate })
+	return absences, nil
+}
+
+func (r *memoryAttendanceRepository) CreateAbsence(absence *models.AbsenceRecord) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.employeeByCode(absence.EmployeeID); !ok {
+		return fmt.Errorf("foreign key violation: employee %q does not exist", absence.EmployeeID)
+	}
+	for _, existing := range r.store.absences {
+		if existing.EmployeeID == absence.EmployeeID && existing.BusinessDate == absence.BusinessDate {
+			return fmt.Errorf("duplicate absence for %q on %s", absence.EmployeeID, absence.BusinessDate)
+		}
+	}
+	absence.ID = r.store.nextID("absence_records")
+	now := time.Now()
+	absence.CreatedAt = now
+	absence.UpdatedAt = now
+
+	row := *absence
+	row.Employee = models.Employee{}
+	r.store.absences[row.ID] = row
+	return nil
+}
+
+func (r *memoryAttendanceRepository) DeleteAbsences(employeeID string, dateFrom, dateTo string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for id, absence := range r.store.absences {
+		if absence.EmployeeID == employeeID && absence.BusinessDate >= dateFrom && absence.BusinessDate <= dateTo {
+			delete(r.store.absences, id)
+		}
+	}
+	return nil
+}
+
+func (r *memoryAttendanceRepository) DeleteAbsencesOn(date string, departmentID *uint) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for id, absence := range r.store.absences {
+		if absence.BusinessDate != date {
+			continue
+		}
+		if emp, ok := r.store.employeeByCode(absence.EmployeeID); departmentID != nil && (!ok || emp.DepartmentID != *departmentID) {
+			continue
+		}
+		delete(r.store.absences, id)
+	}
+	return nil
+}
+
+func (r *memoryAttendanceRepository) DeleteByEmployee(employeeID string) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	r.store.deleteAttendancesOf(employeeID)
+	return nil
+}
@@ -0,0 +1,105 @@
+package repositories
+
+import (
+	"fleetify-backend/models"
+	"fmt"
+	"slices"
+	"time"
+)
+
+type memoryCorrectionRepository struct {
+	store *MemoryStore
+}
+
+func NewMemoryCorrectionRepository(store *MemoryStore) CorrectionRepository {
+	return &memoryCorrectionRepository{store: store}
+}
+
+func (r *memoryCorrectionRepository) FindAll(filter CorrectionFilter) ([]models.AttendanceCorrection, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	var corrections []models.AttendanceCorrection
+	for _, correction := range sortedValues(r.store.corrections) {
+		if filter.AttendanceID != "" && correction.AttendanceID != filter.AttendanceID {
+			continue
+		}
+		if filter.EmployeeID != "" && correction.EmployeeID != filter.EmployeeID {
+			continue
+		}
+		if len(filter.Statuses) > 0 && !slices.Contains(filter.Statuses, correction.Status) {
+			continue
+		}
+		correction = r.store.withCorrectionRelations(correction)
+		if filter.DepartmentID != nil && correction.Employee.DepartmentID != *filter.DepartmentID {
+			continue
+		}
+		corrections = append(corrections, correction)
+	}
+	slices.Reverse(corrections)
+	return corrections, nil
+}
+
+func (r *memoryCorrectionRepository) FindByID(id uint) (*models.AttendanceCorrection, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	correction, ok := r.store.corrections[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	correction = r.store.withCorrectionRelations(correction)
+	return &correction, nil
+}
+
+// LockByID transaksi memory sudah berjalan satu per satu, jadi cukup dibaca ulang
+func (r *memoryCorrectionRepository) LockByID(id uint) (*models.AttendanceCorrection, error) {
+	return r.FindByID(id)
+}
+
+func (r *memoryCorrectionRepository) Create(correction *models.AttendanceCorrection) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if err := r.validate(*correction); err != nil {
+		return err
+	}
+	correction.ID = r.store.nextID("attendance_corrections")
+	now := time.Now()
+	correction.CreatedAt = now
+	correction.UpdatedAt = now
+	r.store.corrections[correction.ID] = stripCorrectionRelations(*correction)
+	return nil
+}
+
+func (r *memoryCorrectionRepository) Update(correction *models.AttendanceCorrection) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.corrections[correction.ID]; !ok {
+		return ErrNotFound
+	}
+	if err := r.validate(*correction); err != nil {
+		return err
+	}
+	correction.UpdatedAt = time.Now()
+	r.store.corrections[correction.ID] = stripCorrectionRelations(*correction)
+	return nil
+}
+
+// validate meniru foreign key di database
+func (r *memoryCorrectionRepository) validate(correction models.AttendanceCorrection) error {
+	if _, ok := r.store.employeeByCode(correction.EmployeeID); !ok {
+		return fmt.Errorf("foreign key violation: employee %q does not exist", correction.EmployeeID)
+	}
+	if _, ok := r.store.attendanceByCode(correction.AttendanceID); !ok {
+		return fmt.Errorf("foreign key violation: attendance %q does not exist", correction.AttendanceID)
+	}
+	return nil
+}
+
+func stripCorrectionRelations(correction models.AttendanceCorrection) models.AttendanceCorrection {
+	correction.Employee = models.Employee{}
+	correction.Attendance = models.Attendance{}
+	return correction
+}
@@ -0,0 +1,143 @@
+package repositories
+
+import (
+	"fleetify-backend/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type memoryDepartmentRepository struct {
+	store       *MemoryStore
+	withDeleted bool
+}
+
+func NewMemoryDepartmentRepository(store *MemoryStore) DepartmentRepository {
+	return &memoryDepartmentRepository{store: store}
+}
+
+func (r *memoryDepartmentRepository) WithDeleted() DepartmentRepository {
+	return &memoryDepartmentRepository{store: r.store, withDeleted: true}
+}
+
+func (r *memoryDepartmentRepository) FindAll() ([]models.Department, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	var departments []models.Department
+	for _, dept := range sortedValues(r.store.departments) {
+		if !r.withDeleted && dept.DeletedAt.Valid {
+			continue
+		}
+		departments = append(departments, r.store.withEmployees(dept, r.withDeleted))
+	}
+	return departments, nil
+}
+
+func (r *memoryDepartmentRepository) FindByID(id uint) (*models.Department, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	dept, ok := r.store.departments[id]
+	if !ok || (!r.withDeleted && dept.DeletedAt.Valid) {
+		return nil, ErrNotFound
+	}
+	dept = r.store.withEmployees(dept, r.withDeleted)
+	return &dept, nil
+}
+
+// LockByID transaksi memory sudah berjalan satu per satu, cukup cek department-nya ada
+func (r *memoryDepartmentRepository) LockByID(id uint) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	dept, ok := r.store.departments[id]
+	if !ok || (!r.withDeleted && dept.DeletedAt.Valid) {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (r *memoryDepartmentRepository) Create(department *models.Department) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	department.ID = r.store.nextID("departments")
+	now := time.Now()
+	department.CreatedAt = now
+	department.UpdatedAt = now
+
+	row := *department
+	row.Employees = nil
+	r.store.departments[row.ID] = row
+	return nil
+}
+
+func (r *memoryDepartmentRepository) Update(department *models.Department) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.departments[department.ID]; !ok {
+		return ErrNotFound
+	}
+	department.UpdatedAt = time.Now()
+
+	row := *department
+	row.Employees = nil
+	r.store.departments[row.ID] = row
+	return nil
+}
+
+func (r *memoryDepartmentRepository) Delete(department *models.Department) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	row, ok := r.store.departments[department.ID]
+	if !ok || row.DeletedAt.Valid {
+		return nil
+	}
+	now := time.Now()
+	row.DeletedAt = gorm.DeletedAt{Time: now, Valid: true}
+	row.UpdatedAt = now
+	r.store.departments[row.ID] = row
+	department.DeletedAt = row.DeletedAt
+	department.UpdatedAt = now
+	return nil
+}
+
+func (r *memoryDepartmentRepository) Restore(department *models.Department) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	row, ok := r.store.departments[department.ID]
+	if !ok {
+		return nil
+	}
+	row.DeletedAt = gorm.DeletedAt{}
+	row.UpdatedAt = time.Now()
+	r.store.departments[row.ID] = row
+	department.DeletedAt = row.DeletedAt
+	department.UpdatedAt = row.UpdatedAt
+	return nil
+}
+
+func (r *memoryDepartmentRepository) Purge(department *models.Department) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	delete(r.store.departments, department.ID)
+	// ON DELETE CASCADE seperti foreign key employees.department_id dan holidays.department_id
+	for id, emp := range r.store.employees {
+		if emp.DepartmentID == department.ID {
+			r.store.deleteAttendancesOf(emp.EmployeeID)
+			r.store.deleteLeavesOf(emp.EmployeeID)
+			delete(r.store.employees, id)
+		}
+	}
+	for id, holiday := range r.store.holidays {
+		if holiday.DepartmentID != nil && *holiday.DepartmentID == department.ID {
+			delete(r.store.holidays, id)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,235 @@
+package repositories
+
+import (
+	"fleetify-backend/models"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type memoryEmployeeRepository struct {
+	store       *MemoryStore
+	withDeleted bool
+}
+
+func NewMemoryEmployeeRepository(store *MemoryStore) EmployeeRepository {
+	return &memoryEmployeeRepository{store: store}
+}
+
+func (r *memoryEmployeeRepository) WithDeleted() EmployeeRepository {
+	return &memoryEmployeeRepository{store: r.store, withDeleted: true}
+}
+
+func (r *memoryEmployeeRepository) FindAll() ([]models.Employee, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	var employees []models.Employee
+	for _, emp := range sortedValues(r.store.employees) {
+		if !r.visible(emp) {
+			continue
+		}
+		employees = append(employees, r.store.withDepartment(emp))
+	}
+	return employees, nil
+}
+
+func (r *memoryEmployeeRepository) FindByID(id uint) (*models.Employee, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	emp, ok := r.store.employees[id]
+	if !ok || !r.visible(emp) {
+		return nil, ErrNotFound
+	}
+	emp = r.store.withDepartment(emp)
+	return &emp, nil
+}
+
+func (r *memoryEmployeeRepository) FindByEmployeeID(employeeID string) (*models.Employee, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	emp, ok := r.store.employeeByCode(employeeID)
+	if !ok || !r.visible(emp) {
+		return nil, ErrNotFound
+	}
+	emp = r.store.withDepartment(emp)
+	return &emp, nil
+}
+
+// LockByEmployeeID transaksi memory sudah berjalan satu per satu, cukup cek employee-nya ada
+func (r *memoryEmployeeRepository) LockByEmployeeID(employeeID string) error {
+	_, err := r.FindByEmployeeID(employeeID)
+	return err
+}
+
+func (r *memoryEmployeeRepository) FindByDepartment(departmentID uint) ([]models.Employee, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	var employees []models.Employee
+	for _, emp := range sortedValues(r.store.employees) {
+		if emp.DepartmentID == departmentID && r.visible(emp) {
+			employees = append(employees, emp)
+		}
+	}
+	return employees, nil
+}
+
+func (r *memoryEmployeeRepository) CodesWithPrefix(prefix string) ([]string, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	var codes []string
+	for _, emp := range r.store.employees {
+		if strings.HasPrefix(emp.EmployeeID, prefix+"-") {
+			codes = append(codes, emp.EmployeeID)
+		}
+	}
+	return codes, nil
+}
+
+func (r *memoryEmployeeRepository) Create(employee *models.Employee) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if err := r.validate(*employee); err != nil {
+		return err
+	}
+	employee.ID = r.store.nextID("employees")
+	now := time.Now()
+	employee.CreatedAt = now
+	employee.UpdatedAt = now
+
+	row := *employee
+	row.Department = models.Department{}
+	r.store.employees[row.ID] = row
+	return nil
+}
+
+func (r *memoryEmployeeRepository) Update(employee *models.Employee) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.employees[employee.ID]; !ok {
+		return ErrNotFound
+	}
+	if err := r.validate(*employee); err != nil {
+		return err
+	}
+	employee.UpdatedAt = time.Now()
+
+	row := *employee
+	row.Department = models.Department{}
+	r.store.employees[row.ID] = row
+	return nil
+}
+
+func (r *memoryEmployeeRepository) Delete(employee *models.Employee) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	row, ok := r.store.employees[employee.ID]
+	if !ok || row.DeletedAt.Valid {
+		return nil
+	}
+	now := time.Now()
+	row.DeletedAt = gorm.DeletedAt{Time: now, Valid: true}
+	row.UpdatedAt = now
+	r.store.employees[row.ID] = row
+	employee.DeletedAt = row.DeletedAt
+	employee.UpdatedAt = now
+	return nil
+}
+
+func (r *memoryEmployeeRepository) MoveDepartment(fromID, toID uint) (int64, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.departments[toID]; !ok {
+		return 0, fmt.Errorf("foreign key violation: department %d does not exist", toID)
+	}
+	var moved int64
+	now := time.Now()
+	for id, emp := range r.store.employees {
+		if emp.DepartmentID == fromID && !emp.DeletedAt.Valid {
+			emp.DepartmentID = toID
+			emp.UpdatedAt = now
+			r.store.employees[id] = emp
+			moved++
+		}
+	}
+	return moved, nil
+}
+
+func (r *memoryEmployeeRepository) DeleteByDepartment(departmentID uint, deletedAt time.Time) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for id, emp := range r.store.employees {
+		if emp.DepartmentID == departmentID && !emp.DeletedAt.Valid {
+			emp.DeletedAt = gorm.DeletedAt{Time: deletedAt, Valid: true}
+			r.store.employees[id] = emp
+		}
+	}
+	return nil
+}
+
+func (r *memoryEmployeeRepository) Restore(employee *models.Employee) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	row, ok := r.store.employees[employee.ID]
+	if !ok {
+		return nil
+	}
+	row.DeletedAt = gorm.DeletedAt{}
+	row.UpdatedAt = time.Now()
+	r.store.employees[row.ID] = row
+	employee.DeletedAt = row.DeletedAt
+	employee.UpdatedAt = row.UpdatedAt
+	return nil
+}
+
+func (r *memoryEmployeeRepository) RestoreByDepartment(departmentID uint, deletedAt time.Time) (int64, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	var restored int64
+	for id, emp := range r.store.employees {
+		if emp.DepartmentID == departmentID && emp.DeletedAt.Valid && emp.DeletedAt.Time.Equal(deletedAt) {
+			emp.DeletedAt = gorm.DeletedAt{}
+			r.store.employees[id] = emp
+			restored++
+		}
+	}
+	return restored, nil
+}
+
+func (r *memoryEmployeeRepository) Purge(employee *models.Employee) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	delete(r.store.employees, employee.ID)
+	r.store.deleteLeavesOf(employee.EmployeeID)
+	return nil
+}
+
+// visible meniru scope soft delete GORM (deleted_at IS NULL)
+func (r *memoryEmployeeRepository) visible(emp models.Employee) bool {
+	return r.withDeleted || !emp.DeletedAt.Valid
+}
+
+// validate meniru constraint unique dan foreign key di database
+func (r *memoryEmployeeRepository) validate(employee models.Employee) error {
+	if existing, ok := r.store.employeeByCode(employee.EmployeeID); ok && existing.ID != employee.ID {
+		return fmt.Errorf("duplicate employee_id %q", employee.EmployeeID)
+	}
+	if _, ok := r.store.departments[employee.DepartmentID]; !ok {
+		return fmt.Errorf("foreign key violation: department %d does not exist", employee.DepartmentID)
+	}
+	return nil
+}
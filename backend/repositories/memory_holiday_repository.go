@@ -0,0 +1,100 @@
+package repositories
+
+import (
+	"fleetify-backend/models"
+	"fmt"
+	"sort"
+	"time"
+)
+
+type memoryHolidayRepository struct {
+	store *MemoryStore
+}
+
+func NewMemoryHolidayRepository(store *MemoryStore) HolidayRepository {
+	return &memoryHolidayRepository{store: store}
+}
+
+func (r *memoryHolidayRepository) FindAll(filter HolidayFilter) ([]models.Holiday, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	var holidays []models.Holiday
+	for _, holiday := range sortedValues(r.store.holidays) {
+		if filter.DateFrom != "" && holiday.Date < filter.DateFrom {
+			continue
+		}
+		if filter.DateTo != "" && holiday.Date > filter.DateTo {
+			continue
+		}
+		if filter.Type != "" && holiday.Type != filter.Type {
+			continue
+		}
+		if filter.DepartmentID != nil && !holiday.AppliesTo(*filter.DepartmentID) {
+			continue
+		}
+		holidays = append(holidays, holiday)
+	}
+	sort.SliceStable(holidays, func(i, j int) bool { return holidays[i].Date < holidays[j].Date })
+	return holidays, nil
+}
+
+func (r *memoryHolidayRepository) FindByID(id uint) (*models.Holiday, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	holiday, ok := r.store.holidays[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &holiday, nil
+}
+
+func (r *memoryHolidayRepository) Create(holiday *models.Holiday) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if err := r.validate(*holiday); err != nil {
+		return err
+	}
+	holiday.ID = r.store.nextID("holidays")
+	now := time.Now()
+	holiday.CreatedAt = now
+	holiday.UpdatedAt = now
+	r.store.holidays[holiday.ID] = *holiday
+	return nil
+}
+
+func (r *memoryHolidayRepository) Update(holiday *models.Holiday) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.holidays[holiday.ID]; !ok {
+		return ErrNotFound
+	}
+	if err := r.validate(*holiday); err != nil {
+		return err
+	}
+	holiday.UpdatedAt = time.Now()
+	r.store.holidays[holiday.ID] = *holiday
+	return nil
+}
+
+func (r *memoryHolidayRepository) Delete(holiday *models.Holiday) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	delete(r.store.holidays, holiday.ID)
+	return nil
+}
+
+// validate meniru foreign key holidays.department_id
+func (r *memoryHolidayRepository) validate(holiday models.Holiday) error {
+	if holiday.DepartmentID == nil {
+		return nil
+	}
+	if _, ok := r.store.departments[*holiday.DepartmentID]; !ok {
+		return fmt.Errorf("foreign key violation: department %d does not exist", *holiday.DepartmentID)
+	}
+	return nil
+}
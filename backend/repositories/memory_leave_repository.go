@@ -0,0 +1,142 @@
+package repositories
+
+import (
+	"fleetify-backend/models"
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+)
+
+type memoryLeaveRepository struct {
+	store *MemoryStore
+}
+
+func NewMemoryLeaveRepository(store *MemoryStore) LeaveRepository {
+	return &memoryLeaveRepository{store: store}
+}
+
+func (r *memoryLeaveRepository) FindTypes() ([]models.LeaveType, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	return sortedValues(r.store.leaveTypes), nil
+}
+
+func (r *memoryLeaveRepository) FindTypeByID(id uint) (*models.LeaveType, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	leaveType, ok := r.store.leaveTypes[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &leaveType, nil
+}
+
+func (r *memoryLeaveRepository) FindAll(filter LeaveFilter) ([]models.LeaveRequest, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	var leaves []models.LeaveRequest
+	for _, leave := range sortedValues(r.store.leaves) {
+		if filter.EmployeeID != "" && leave.EmployeeID != filter.EmployeeID {
+			continue
+		}
+		if len(filter.Statuses) > 0 && !slices.Contains(filter.Statuses, leave.Status) {
+			continue
+		}
+		if filter.DateFrom != "" && leave.EndDate < filter.DateFrom {
+			continue
+		}
+		if filter.DateTo != "" && leave.StartDate > filter.DateTo {
+			continue
+		}
+		leave = r.store.withLeaveRelations(leave)
+		if filter.DepartmentID != nil && leave.Employee.DepartmentID != *filter.DepartmentID {
+			continue
+		}
+		leaves = append(leaves, leave)
+	}
+	sort.SliceStable(leaves, func(i, j int) bool { return leaves[i].StartDate < leaves[j].StartDate })
+	return leaves, nil
+}
+
+func (r *memoryLeaveRepository) FindByID(id uint) (*models.LeaveRequest, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	leave, ok := r.store.leaves[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	leave = r.store.withLeaveRelations(leave)
+	return &leave, nil
+}
+
+// LockByID transaksi memory sudah berjalan satu per satu, jadi cukup dibaca ulang
+func (r *memoryLeaveRepository) LockByID(id uint) (*models.LeaveRequest, error) {
+	return r.FindByID(id)
+}
+
+func (r *memoryLeaveRepository) Create(leave *models.LeaveRequest) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if err := r.validate(*leave); err != nil {
+		return err
+	}
+	leave.ID = r.store.nextID("leave_requests")
+	now := time.Now()
+	leave.CreatedAt = now
+	leave.UpdatedAt = now
+	r.store.leaves[leave.ID] = stripLeaveRelations(*leave)
+	return nil
+}
+
+func (r *memoryLeaveRepository) Update(leave *models.LeaveRequest) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.leaves[leave.ID]; !ok {
+		return ErrNotFound
+	}
+	if err := r.validate(*leave); err != nil {
+		return err
+	}
+	leave.UpdatedAt = time.Now()
+	r.store.leaves[leave.ID] = stripLeaveRelations(*leave)
+	return nil
+}
+
+func (r *memoryLeaveRepository) UpdateStatus(leave *models.LeaveRequest) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	stored, ok := r.store.leaves[leave.ID]
+	if !ok {
+		return ErrNotFound
+	}
+	leave.UpdatedAt = time.Now()
+	stored.Status = leave.Status
+	stored.UpdatedAt = leave.UpdatedAt
+	r.store.leaves[leave.ID] = stored
+	return nil
+}
+
+// validate meniru foreign key di database
+func (r *memoryLeaveRepository) validate(leave models.LeaveRequest) error {
+	if _, ok := r.store.employeeByCode(leave.EmployeeID); !ok {
+		return fmt.Errorf("foreign key violation: employee %q does not exist", leave.EmployeeID)
+	}
+	if _, ok := r.store.leaveTypes[leave.LeaveTypeID]; !ok {
+		return fmt.Errorf("foreign key violation: leave type %d does not exist", leave.LeaveTypeID)
+	}
+	return nil
+}
+
+func stripLeaveRelations(leave models.LeaveRequest) models.LeaveRequest {
+	leave.Employee = models.Employee{}
+	leave.LeaveType = models.LeaveType{}
+	return leave
+}
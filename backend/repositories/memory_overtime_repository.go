@@ -0,0 +1,154 @@
+package repositories
+
+import (
+	"fleetify-backend/models"
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+)
+
+type memoryOvertimeRepository struct {
+	store *MemoryStore
+}
+
+func NewMemoryOvertimeRepository(store *MemoryStore) OvertimeRepository {
+	return &memoryOvertimeRepository{store: store}
+}
+
+func (r *memoryOvertimeRepository) FindAll(filter OvertimeFilter) ([]models.Overtime, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	var overtimes []models.Overtime
+	for _, overtime := range sortedValues(r.store.overtimes) {
+		if filter.EmployeeID != "" && overtime.EmployeeID != filter.EmployeeID {
+			continue
+		}
+		if len(filter.Statuses) > 0 && !slices.Contains(filter.Statuses, overtime.Status) {
+			continue
+		}
+		if filter.Type != "" && overtime.Type != filter.Type {
+			continue
+		}
+		if filter.DateFrom != "" && overtime.BusinessDate < filter.DateFrom {
+			continue
+		}
+		if filter.DateTo != "" && overtime.BusinessDate > filter.DateTo {
+			continue
+		}
+		overtime = r.store.withOvertimeRelations(overtime)
+		if filter.DepartmentID != nil && overtime.Employee.DepartmentID != *filter.DepartmentID {
+			continue
+		}
+		overtimes = append(overtimes, overtime)
+	}
+	sort.SliceStable(overtimes, func(i, j int) bool { return overtimes[i].BusinessDate < overtimes[j].BusinessDate })
+	return overtimes, nil
+}
+
+func (r *memoryOvertimeRepository) FindByID(id uint) (*models.Overtime, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	overtime, ok := r.store.overtimes[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	overtime = r.store.withOvertimeRelations(overtime)
+	return &overtime, nil
+}
+
+func (r *memoryOvertimeRepository) FindByAttendanceID(attendanceID string) (*models.Overtime, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for _, overtime := range sortedValues(r.store.overtimes) {
+		if overtime.AttendanceID == attendanceID {
+			return &overtime, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+// LockByID transaksi memory sudah berjalan satu per satu, jadi cukup dibaca ulang
+func (r *memoryOvertimeRepository) LockByID(id uint) (*models.Overtime, error) {
+	return r.FindByID(id)
+}
+
+func (r *memoryOvertimeRepository) Create(overtime *models.Overtime) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if err := r.validate(*overtime); err != nil {
+		return err
+	}
+	for _, other := range r.store.overtimes {
+		if other.AttendanceID == overtime.AttendanceID {
+			return fmt.Errorf("unique constraint violation: overtime for attendance %q already exists", overtime.AttendanceID)
+		}
+	}
+	overtime.ID = r.store.nextID("overtimes")
+	now := time.Now()
+	overtime.CreatedAt = now
+	overtime.UpdatedAt = now
+	r.store.overtimes[overtime.ID] = stripOvertimeRelations(*overtime)
+	return nil
+}
+
+func (r *memoryOvertimeRepository) Update(overtime *models.Overtime) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.overtimes[overtime.ID]; !ok {
+		return ErrNotFound
+	}
+	if err := r.validate(*overtime); err != nil {
+		return err
+	}
+	overtime.UpdatedAt = time.Now()
+	r.store.overtimes[overtime.ID] = stripOvertimeRelations(*overtime)
+	return nil
+}
+
+func (r *memoryOvertimeRepository) Review(overtime *models.Overtime) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	stored, ok := r.store.overtimes[overtime.ID]
+	if !ok {
+		return ErrNotFound
+	}
+	overtime.UpdatedAt = time.Now()
+	stored.Status = overtime.Status
+	stored.ReviewedBy = overtime.ReviewedBy
+	stored.ReviewedAt = overtime.ReviewedAt
+	stored.ReviewNote = overtime.ReviewNote
+	stored.UpdatedAt = overtime.UpdatedAt
+	r.store.overtimes[overtime.ID] = stored
+	return nil
+}
+
+func (r *memoryOvertimeRepository) Delete(overtime *models.Overtime) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	delete(r.store.overtimes, overtime.ID)
+	return nil
+}
+
+// validate meniru foreign key di database
+func (r *memoryOvertimeRepository) validate(overtime models.Overtime) error {
+	if _, ok := r.store.employeeByCode(overtime.EmployeeID); !ok {
+		return fmt.Errorf("foreign key violation: employee %q does not exist", overtime.EmployeeID)
+	}
+	if _, ok := r.store.attendanceByCode(overtime.AttendanceID); !ok {
+		return fmt.Errorf("foreign key violation: attendance %q does not exist", overtime.AttendanceID)
+	}
+	return nil
+}
+
+func stripOvertimeRelations(overtime models.Overtime) models.Overtime {
+	overtime.Employee = models.Employee{}
+	return overtime
+}
@@ -0,0 +1,46 @@
+package repositories
+
+type memorySequenceRepository struct {
+	store *MemoryStore
+}
+
+func NewMemorySequenceRepository(store *MemoryStore) SequenceRepository {
+	return &memorySequenceRepository{store: store}
+}
+
+func (r *memorySequenceRepository) Next(name, period string, seed func() (int64, error)) (int64, error) {
+	key := name + "/" + period
+
+	r.store.mu.Lock()
+	_, exists := r.store.sequences[key]
+	r.store.mu.Unlock()
+
+	// seed() boleh memanggil repository lain, jadi dipanggil tanpa lock
+	start := int64(0)
+	if !exists {
+		var err error
+		if start, err = seed(); err != nil {
+			return 0, err
+		}
+	}
+
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.sequences[key]; !ok {
+		r.store.sequences[key] = start
+	}
+	r.store.sequences[key]++
+	return r.store.sequences[key], nil
+}
+
+func (r *memorySequenceRepository) EnsureAtLeast(name, period string, value int64) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	key := name + "/" + period
+	if r.store.sequences[key] < value {
+		r.store.sequences[key] = value
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package repositories
+
+import (
+	"fleetify-backend/models"
+	"sort"
+	"time"
+)
+
+type memoryShiftRepository struct {
+	store *MemoryStore
+}
+
+func NewMemoryShiftRepository(store *MemoryStore) ShiftRepository {
+	return &memoryShiftRepository{store: store}
+}
+
+func (r *memoryShiftRepository) FindAll() ([]models.Shift, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	var shifts []models.Shift
+	for _, shift := range sortedValues(r.store.shifts) {
+		shifts = append(shifts, copyShift(shift))
+	}
+	return shifts, nil
+}
+
+func (r *memoryShiftRepository) FindByID(id uint) (*models.Shift, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	shift, ok := r.store.shifts[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	shift = copyShift(shift)
+	return &shift, nil
+}
+
+func (r *memoryShiftRepository) Create(shift *models.Shift) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	shift.ID = r.store.nextID("shifts")
+	now := time.Now()
+	shift.CreatedAt = now
+	shift.UpdatedAt = now
+	r.assignDayIDs(shift)
+
+	r.store.shifts[shift.ID] = copyShift(*shift)
+	return nil
+}
+
+func (r *memoryShiftRepository) Update(shift *models.Shift) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.shifts[shift.ID]; !ok {
+		return ErrNotFound
+	}
+	shift.UpdatedAt = time.Now()
+	r.assignDayIDs(shift)
+
+	r.store.shifts[shift.ID] = copyShift(*shift)
+	return nil
+}
+
+func (r *memoryShiftRepository) Delete(shift *models.Shift) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	delete(r.store.shifts, shift.ID)
+	return nil
+}
+
+func (r *memoryShiftRepository) CountAssignments(id uint) (int64, int64, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	var departments, employees int64
+	for _, dept := range r.store.departments {
+		if dept.ShiftID != nil && *dept.ShiftID == id {
+			departments++
+		}
+	}
+	for _, emp := range r.store.employees {
+		if emp.ShiftID != nil && *emp.ShiftID == id {
+			employees++
+		}
+	}
+	return departments, employees, nil
+}
+
+// assignDayIDs meniru insert ulang shift_days (caller memegang lock)
+func (r *memoryShiftRepository) assignDayIDs(shift *models.Shift) {
+	for i := range shift.Days {
+		shift.Days[i].ID = r.store.nextID("shift_days")
+		shift.Days[i].ShiftID = shift.ID
+	}
+}
+
+// copyShift salinan shift dengan slice Days sendiri, urut per weekday
+func copyShift(shift models.Shift) models.Shift {
+	days := make([]models.ShiftDay, len(shift.Days))
+	copy(days, shift.Days)
+	sort.Slice(days, func(i, j int) bool { return days[i].Weekday < days[j].Weekday })
+	shift.Days = days
+	return shift
+}
@@ -0,0 +1,171 @@
+package repositories
+
+import (
+	"fleetify-backend/models"
+	"slices"
+	"sort"
+	"time"
+)
+
+type memoryStatsRepository struct {
+	store *MemoryStore
+}
+
+func NewMemoryStatsRepository(store *MemoryStore) StatsRepository {
+	return &memoryStatsRepository{store: store}
+}
+
+func (r *memoryStatsRepository) Headcount(departmentID *uint, at time.Time) (int, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	count := 0
+	for _, emp := range r.store.employees {
+		if emp.DeletedAt.Valid || emp.CreatedAt.After(at) || !emp.IsActiveAt(at) {
+			continue
+		}
+		if departmentID != nil && emp.DepartmentID != *departmentID {
+			continue
+		}
+		count++
+	}
+	return count, nil
+}
+
+// clockIns history clock in di rentang tanggal bisnis, sudah di-preload (caller memegang lock)
+func (r *memoryStatsRepository) clockIns(filter StatsFilter) []models.AttendanceHistory {
+	var histories []models.AttendanceHistory
+	for _, history := range sortedValues(r.store.histories) {
+		if history.AttendanceType != models.AttendanceTypeClockIn {
+			continue
+		}
+		history = r.store.withHistoryRelations(history)
+		if filter.DateFrom != "" && history.Attendance.BusinessDate < filter.DateFrom {
+			continue
+		}
+		if filter.DateTo != "" && history.Attendance.BusinessDate > filter.DateTo {
+			continue
+		}
+		if filter.DepartmentID != nil && history.Employee.DepartmentID != *filter.DepartmentID {
+			continue
+		}
+		histories = append(histories, history)
+	}
+	return histories
+}
+
+func (r *memoryStatsRepository) Daily(filter StatsFilter) ([]DailyAttendanceStats, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	byDate := map[string]*DailyAttendanceStats{}
+	present := map[string]bool{}
+	late := map[string]bool{}
+	for _, history := range r.clockIns(filter) {
+		date := history.Attendance.BusinessDate
+		day, ok := byDate[date]
+		if !ok {
+			day = &DailyAttendanceStats{Date: date}
+			byDate[date] = day
+		}
+		key := date + "|" + history.EmployeeID
+		if !present[key] {
+			present[key] = true
+			day.Present++
+		}
+		isLate := slices.Contains(lateStatuses, history.Status)
+		if isLate && !late[key] {
+			late[key] = true
+			day.Late++
+		}
+		if history.Status == statusOnTime {
+			day.OnTime++
+		}
+		if isLate || history.Status == statusOnTime {
+			day.Rated++
+		}
+	}
+	var attended []DailyAttendanceStats
+	for _, day := range byDate {
+		attended = append(attended, *day)
+	}
+
+	absentByDate := map[string]int{}
+	for _, absence := range r.store.absences {
+		if filter.DateFrom != "" && absence.BusinessDate < filter.DateFrom {
+			continue
+		}
+		if filter.DateTo != "" && absence.BusinessDate > filter.DateTo {
+			continue
+		}
+		if filter.DepartmentID != nil {
+			emp, ok := r.store.employeeByCode(absence.EmployeeID)
+			if !ok || emp.DepartmentID != *filter.DepartmentID {
+				continue
+			}
+		}
+		absentByDate[absence.BusinessDate]++
+	}
+	var absent []DailyAttendanceStats
+	for date, count := range absentByDate {
+		absent = append(absent, DailyAttendanceStats{Date: date, Absent: count})
+	}
+	return mergeDaily(attended, absent), nil
+}
+
+func (r *memoryStatsRepository) AverageClockIn(filter StatsFilter) (float64, int, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	total, count := 0, 0
+	for _, history := range r.clockIns(filter) {
+		clockIn := history.Attendance.ClockIn
+		total += clockIn.Hour()*3600 + clockIn.Minute()*60 + clockIn.Second()
+		count++
+	}
+	if count == 0 {
+		return 0, 0, nil
+	}
+	return float64(total) / float64(count), count, nil
+}
+
+func (r *memoryStatsRepository) TopLate(filter StatsFilter, limit int) ([]LateEmployeeStats, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	byEmployee := map[string]*LateEmployeeStats{}
+	for _, history := range r.clockIns(filter) {
+		if !slices.Contains(lateStatuses, history.Status) {
+			continue
+		}
+		row, ok := byEmployee[history.EmployeeID]
+		if !ok {
+			row = &LateEmployeeStats{
+				EmployeeID:   history.EmployeeID,
+				Name:         history.Employee.Name,
+				DepartmentID: history.Employee.DepartmentID,
+			}
+			byEmployee[history.EmployeeID] = row
+		}
+		row.LateCount++
+		row.LateMinutes += history.MinutesLate
+	}
+
+	var rows []LateEmployeeStats
+	for _, row := range byEmployee {
+		rows = append(rows, *row)
+	}
+	sort.Slice(rows, func(i, j int) bool {
+		if rows[i].LateCount != rows[j].LateCount {
+			return rows[i].LateCount > rows[j].LateCount
+		}
+		if rows[i].LateMinutes != rows[j].LateMinutes {
+			return rows[i].LateMinutes > rows[j].LateMinutes
+		}
+		return rows[i].EmployeeID < rows[j].EmployeeID
+	})
+	if len(rows) > limit {
+		rows = rows[:limit]
+	}
+	return rows, nil
+}
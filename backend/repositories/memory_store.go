@@ -0,0 +1,253 @@
+package repositories
+
+import (
+	"fleetify-backend/models"
+	"maps"
+	"sort"
+	"sync"
+)
+
+// MemoryStore penyimpanan in-memory yang dipakai bersama oleh semua
+// repository memory, supaya relasi (employee -> department, dst) bisa
+// di-preload seperti di database. Cocok untuk unit test handler.
+type MemoryStore struct {
+	mu     sync.Mutex
+	txMu   sync.Mutex // satu transaksi pada satu waktu
+	lastID map[string]uint
+
+	departments map[uint]models.Department
+	employees   map[uint]models.Employee
+	attendances map[uint]models.Attendance
+	histories   map[uint]models.AttendanceHistory
+	users       map[uint]models.User
+	sequences   map[string]int64
+	shifts      map[uint]models.Shift
+	leaveTypes  map[uint]models.LeaveType
+	leaves      map[uint]models.LeaveRequest
+	absences    map[uint]models.AbsenceRecord
+	holidays    map[uint]models.Holiday
+	corrections map[uint]models.AttendanceCorrection
+	overtimes   map[uint]models.Overtime
+}
+
+func NewMemoryStore() *MemoryStore {
+	s := &MemoryStore{
+		lastID:      map[string]uint{},
+		departments: map[uint]models.Department{},
+		employees:   map[uint]models.Employee{},
+		attendances: map[uint]models.Attendance{},
+		histories:   map[uint]models.AttendanceHistory{},
+		users:       map[uint]models.User{},
+		sequences:   map[string]int64{},
+		shifts:      map[uint]models.Shift{},
+		leaveTypes:  map[uint]models.LeaveType{},
+		leaves:      map[uint]models.LeaveRequest{},
+		absences:    map[uint]models.AbsenceRecord{},
+		holidays:    map[uint]models.Holiday{},
+		corrections: map[uint]models.AttendanceCorrection{},
+		overtimes:   map[uint]models.Overtime{},
+	}
+	// Seed jenis cuti seperti migrasi 0013
+	for _, leaveType := range models.DefaultLeaveTypes() {
+		leaveType.ID = s.nextID("leave_types")
+		s.leaveTypes[leaveType.ID] = leaveType
+	}
+	return s
+}
+
+// transaction menjalankan fn dan mengembalikan isi store ke kondisi awal kalau
+// fn gagal. Hanya transaksi lain yang diantrikan, operasi biasa tidak diisolasi.
+func (s *MemoryStore) transaction(fn func() error) error {
+	s.txMu.Lock()
+	defer s.txMu.Unlock()
+
+	s.mu.Lock()
+	snapshot := s.clone()
+	s.mu.Unlock()
+
+	if err := fn(); err != nil {
+		s.mu.Lock()
+		s.lastID = snapshot.lastID
+		s.departments = snapshot.departments
+		s.employees = snapshot.employees
+		s.attendances = snapshot.attendances
+		s.histories = snapshot.histories
+		s.users = snapshot.users
+		s.sequences = snapshot.sequences
+		s.shifts = snapshot.shifts
+		s.leaveTypes = snapshot.leaveTypes
+		s.leaves = snapshot.leaves
+		s.absences = snapshot.absences
+		s.holidays = snapshot.holidays
+		s.corrections = snapshot.corrections
+		s.overtimes = snapshot.overtimes
+		s.mu.Unlock()
+		return err
+	}
+	return nil
+}
+
+// clone salinan semua map (caller memegang lock)
+func (s *MemoryStore) clone() *MemoryStore {
+	return &MemoryStore{
+		lastID:      maps.Clone(s.lastID),
+		departments: maps.Clone(s.departments),
+		employees:   maps.Clone(s.employees),
+		attendances: maps.Clone(s.attendances),
+		histories:   maps.Clone(s.histories),
+		users:       maps.Clone(s.users),
+		sequences:   maps.Clone(s.sequences),
+		shifts:      maps.Clone(s.shifts),
+		leaveTypes:  maps.Clone(s.leaveTypes),
+		leaves:      maps.Clone(s.leaves),
+		absences:    maps.Clone(s.absences),
+		holidays:    maps.Clone(s.holidays),
+		corrections: maps.Clone(s.corrections),
+		overtimes:   maps.Clone(s.overtimes),
+	}
+}
+
+// nextID auto increment per tabel (caller memegang lock)
+func (s *MemoryStore) nextID(table string) uint {
+	s.lastID[table]++
+	return s.lastID[table]
+}
+
+// sortedValues isi map diurutkan berdasarkan id
+func sortedValues[T any](m map[uint]T) []T {
+	ids := make([]uint, 0, len(m))
+	for id := range m {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	values := make([]T, 0, len(ids))
+	for _, id := range ids {
+		values = append(values, m[id])
+	}
+	return values
+}
+
+// Helper preload relasi (caller memegang lock)
+
+func (s *MemoryStore) employeeByCode(employeeID string) (models.Employee, bool) {
+	for _, emp := range s.employees {
+		if emp.EmployeeID == employeeID {
+			return emp, true
+		}
+	}
+	return models.Employee{}, false
+}
+
+func (s *MemoryStore) attendanceByCode(attendanceID string) (models.Attendance, bool) {
+	for _, att := range s.attendances {
+		if att.AttendanceID == attendanceID {
+			return att, true
+		}
+	}
+	return models.Attendance{}, false
+}
+
+func (s *MemoryStore) withDepartment(emp models.Employee) models.Employee {
+	emp.Department = s.departments[emp.DepartmentID]
+	emp.Department.Shift = s.shiftByID(emp.Department.ShiftID)
+	emp.Shift = s.shiftByID(emp.ShiftID)
+	return emp
+}
+
+func (s *MemoryStore) shiftByID(id *uint) *models.Shift {
+	if id == nil {
+		return nil
+	}
+	shift, ok := s.shifts[*id]
+	if !ok {
+		return nil
+	}
+	shift = copyShift(shift)
+	return &shift
+}
+
+// withEmployees preload employees department, yang sudah di-soft delete hanya ikut kalau withDeleted
+func (s *MemoryStore) withEmployees(dept models.Department, withDeleted bool) models.Department {
+	dept.Shift = s.shiftByID(dept.ShiftID)
+	dept.Employees = nil
+	for _, emp := range sortedValues(s.employees) {
+		if emp.DepartmentID == dept.ID && (withDeleted || !emp.DeletedAt.Valid) {
+			dept.Employees = append(dept.Employees, emp)
+		}
+	}
+	return dept
+}
+
+func (s *MemoryStore) withHistoryRelations(history models.AttendanceHistory) models.AttendanceHistory {
+	if emp, ok := s.employeeByCode(history.EmployeeID); ok {
+		history.Employee = s.withDepartment(emp)
+	}
+	if att, ok := s.attendanceByCode(history.AttendanceID); ok {
+		history.Attendance = att
+	}
+	return history
+}
+
+func (s *MemoryStore) withLeaveRelations(leave models.LeaveRequest) models.LeaveRequest {
+	if emp, ok := s.employeeByCode(leave.EmployeeID); ok {
+		leave.Employee = s.withDepartment(emp)
+	}
+	leave.LeaveType = s.leaveTypes[leave.LeaveTypeID]
+	return leave
+}
+
+func (s *MemoryStore) withCorrectionRelations(correction models.AttendanceCorrection) models.AttendanceCorrection {
+	if emp, ok := s.employeeByCode(correction.EmployeeID); ok {
+		correction.Employee = s.withDepartment(emp)
+	}
+	if att, ok := s.attendanceByCode(correction.AttendanceID); ok {
+		correction.Attendance = att
+	}
+	return correction
+}
+
+func (s *MemoryStore) withOvertimeRelations(overtime models.Overtime) models.Overtime {
+	if emp, ok := s.employeeByCode(overtime.EmployeeID); ok {
+		overtime.Employee = s.withDepartment(emp)
+	}
+	return overtime
+}
+
+// deleteLeavesOf meniru ON DELETE CASCADE leave_requests.employee_id (caller memegang lock)
+func (s *MemoryStore) deleteLeavesOf(employeeID string) {
+	for id, leave := range s.leaves {
+		if leave.EmployeeID == employeeID {
+			delete(s.leaves, id)
+		}
+	}
+}
+
+// deleteAttendancesOf hapus attendance, history, koreksi, lembur dan catatan tidak masuk milik employee (caller memegang lock)
+func (s *MemoryStore) deleteAttendancesOf(employeeID string) {
+	for id, overtime := range s.overtimes {
+		if overtime.EmployeeID == employeeID {
+			delete(s.overtimes, id)
+		}
+	}
+	for id, correction := range s.corrections {
+		if correction.EmployeeID == employeeID {
+			delete(s.corrections, id)
+		}
+	}
+	for id, absence := range s.absences {
+		if absence.EmployeeID == employeeID {
+			delete(s.absences, id)
+		}
+	}
+	for id, history := range s.histories {
+		if history.EmployeeID == employeeID {
+			delete(s.histories, id)
+		}
+	}
+	for id, att := range s.attendances {
+		if att.EmployeeID == employeeID {
+			delete(s.attendances, id)
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package repositories
+
+import (
+	"fleetify-backend/models"
+	"fmt"
+	"time"
+)
+
+type memoryUserRepository struct {
+	store *MemoryStore
+}
+
+func NewMemoryUserRepository(store *MemoryStore) UserRepository {
+	return &memoryUserRepository{store: store}
+}
+
+func (r *memoryUserRepository) FindAll() ([]models.User, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	return sortedValues(r.store.users), nil
+}
+
+func (r *memoryUserRepository) FindByID(id uint) (*models.User, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	user, ok := r.store.users[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &user, nil
+}
+
+func (r *memoryUserRepository) FindByUsername(username string) (*models.User, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	for _, user := range r.store.users {
+		if user.Username == username {
+			return &user, nil
+		}
+	}
+	return nil, ErrNotFound
+}
+
+func (r *memoryUserRepository) Count() (int64, error) {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	return int64(len(r.store.users)), nil
+}
+
+func (r *memoryUserRepository) Create(user *models.User) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if err := r.validate(*user); err != nil {
+		return err
+	}
+	user.ID = r.store.nextID("users")
+	now := time.Now()
+	user.CreatedAt = now
+	user.UpdatedAt = now
+
+	row := *user
+	row.Employee = nil
+	r.store.users[row.ID] = row
+	return nil
+}
+
+func (r *memoryUserRepository) Update(user *models.User) error {
+	r.store.mu.Lock()
+	defer r.store.mu.Unlock()
+
+	if _, ok := r.store.users[user.ID]; !ok {
+		return ErrNotFound
+	}
+	if err := r.validate(*user); err != nil {
+		return err
+	}
+	user.UpdatedAt = time.Now()
+
+	row := *user
+	row.Employee = nil
+	r.store.users[row.ID] = row
+	return nil
+}
+
+// validate meniru constraint unique dan foreign key di database
+func (r *memoryUserRepository) validate(user models.User) error {
+	for _, existing := range r.store.users {
+		if existing.Username == user.Username && existing.ID != user.ID {
+			return fmt.Errorf("duplicate username %q", user.Username)
+		}
+	}
+	if user.EmployeeID != nil {
+		if _, ok := r.store.employeeByCode(*user.EmployeeID); !ok {
+			return fmt.Errorf("foreign key violation: employee %q does not exist", *user.EmployeeID)
+		}
+	}
+	return nil
+}
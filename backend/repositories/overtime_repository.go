@@ -0,0 +1,123 @@
+package repositories
+
+import (
+	"fleetify-backend/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// OvertimeFilter filter lembur, field kosong berarti tidak difilter
+type OvertimeFilter struct {
+	EmployeeID   string
+	DepartmentID *uint
+	Statuses     []string
+	Type         string
+	DateFrom     string // business_date >= DateFrom (YYYY-MM-DD)
+	DateTo       string // business_date <= DateTo (YYYY-MM-DD)
+}
+
+type OvertimeRepository interface {
+	// FindAll lembur beserta employee, urut tanggal bisnis
+	FindAll(filter OvertimeFilter) ([]models.Overtime, error)
+	FindByID(id uint) (*models.Overtime, error)
+	// FindByAttendanceID lembur milik satu attendance (ErrNotFound kalau tidak ada)
+	FindByAttendanceID(attendanceID string) (*models.Overtime, error)
+	// LockByID seperti FindByID tapi mengunci barisnya (SELECT ... FOR UPDATE) sampai
+	// transaksi selesai. Hanya berguna di dalam Transaction.
+	LockByID(id uint) (*models.Overtime, error)
+	Create(overtime *models.Overtime) error
+	Update(overtime *models.Overtime) error
+	// Review hanya menyimpan kolom approval (status, reviewed_by, reviewed_at, review_note),
+	// hasil hitung ulang lembur di kolom lain tidak ikut ditimpa
+	Review(overtime *models.Overtime) error
+	Delete(overtime *models.Overtime) error
+}
+
+type gormOvertimeRepository struct {
+	db *gorm.DB
+}
+
+func NewGormOvertimeRepository(db *gorm.DB) OvertimeRepository {
+	return &gormOvertimeRepository{db: db}
+}
+
+func (r *gormOvertimeRepository) FindAll(filter OvertimeFilter) ([]models.Overtime, error) {
+	db := r.db.Scopes(preloadOvertimeRelations)
+
+	if filter.EmployeeID != "" {
+		db = db.Where("overtimes.employee_id = ?", filter.EmployeeID)
+	}
+	if len(filter.Statuses) > 0 {
+		db = db.Where("overtimes.status IN ?", filter.Statuses)
+	}
+	if filter.Type != "" {
+		db = db.Where("overtimes.type = ?", filter.Type)
+	}
+	if filter.DateFrom != "" {
+		db = db.Where("overtimes.business_date >= ?", filter.DateFrom)
+	}
+	if filter.DateTo != "" {
+		db = db.Where("overtimes.business_date <= ?", filter.DateTo)
+	}
+	if filter.DepartmentID != nil {
+		db = db.Joins("JOIN employees ON overtimes.employee_id = employees.employee_id").
+			Where("employees.department_id = ?", *filter.DepartmentID)
+	}
+
+	var overtimes []models.Overtime
+	err := db.Order("overtimes.business_date, overtimes.id").Find(&overtimes).Error
+	return overtimes, err
+}
+
+func (r *gormOvertimeRepository) FindByID(id uint) (*models.Overtime, error) {
+	var overtime models.Overtime
+	if err := r.db.Scopes(preloadOvertimeRelations).First(&overtime, id).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &overtime, nil
+}
+
+func (r *gormOvertimeRepository) FindByAttendanceID(attendanceID string) (*models.Overtime, error) {
+	var overtime models.Overtime
+	if err := r.db.Where("attendance_id = ?", attendanceID).First(&overtime).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &overtime, nil
+}
+
+func (r *gormOvertimeRepository) LockByID(id uint) (*models.Overtime, error) {
+	var overtime models.Overtime
+	err := r.db.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Scopes(preloadOvertimeRelations).
+		First(&overtime, id).Error
+	if err != nil {
+		return nil, translateError(err)
+	}
+	return &overtime, nil
+}
+
+func (r *gormOvertimeRepository) Create(overtime *models.Overtime) error {
+	return r.db.Omit(clause.Associations).Create(overtime).Error
+}
+
+func (r *gormOvertimeRepository) Update(overtime *models.Overtime) error {
+	return r.db.Omit(clause.Associations).Save(overtime).Error
+}
+
+func (r *gormOvertimeRepository) Review(overtime *models.Overtime) error {
+	return r.db.Model(overtime).Omit(clause.Associations).
+		Select("status", "reviewed_by", "reviewed_at", "review_note", "updated_at").
+		Updates(overtime).Error
+}
+
+func (r *gormOvertimeRepository) Delete(overtime *models.Overtime) error {
+	return r.db.Delete(&models.Overtime{}, overtime.ID).Error
+}
+
+// preloadOvertimeRelations employee (termasuk yang sudah dihapus) beserta department
+func preloadOvertimeRelations(db *gorm.DB) *gorm.DB {
+	return db.
+		Preload("Employee", unscoped).
+		Preload("Employee.Department", unscoped)
+}
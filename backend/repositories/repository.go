@@ -0,0 +1,97 @@
+package repositories
+
+import (
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+// ErrNotFound dikembalikan semua repository kalau data tidak ditemukan
+var ErrNotFound = errors.New("record not found")
+
+// Transactor menjalankan fn di dalam satu transaksi. Semua repository di tx
+// memakai transaksi yang sama; error dari fn membatalkan semua perubahan.
+type Transactor interface {
+	Transaction(fn func(tx Repositories) error) error
+}
+
+// Repositories kumpulan repository yang dipakai untuk wiring handler
+type Repositories struct {
+	Employees   EmployeeRepository
+	Departments DepartmentRepository
+	Attendances AttendanceRepository
+	Users       UserRepository
+	Sequences   SequenceRepository
+	Shifts      ShiftRepository
+	Leaves      LeaveRepository
+	Holidays    HolidayRepository
+	Corrections CorrectionRepository
+	Overtimes   OvertimeRepository
+	Stats       StatsRepository
+
+	transact func(fn func(tx Repositories) error) error
+}
+
+// Transaction implementasi Transactor
+func (r Repositories) Transaction(fn func(tx Repositories) error) error {
+	return r.transact(fn)
+}
+
+// NewGormRepositories membuat semua repository di atas koneksi GORM
+func NewGormRepositories(db *gorm.DB) Repositories {
+	return Repositories{
+		Employees:   NewGormEmployeeRepository(db),
+		Departments: NewGormDepartmentRepository(db),
+		Attendances: NewGormAttendanceRepository(db),
+		Users:       NewGormUserRepository(db),
+		Sequences:   NewGormSequenceRepository(db),
+		Shifts:      NewGormShiftRepository(db),
+		Leaves:      NewGormLeaveRepository(db),
+		Holidays:    NewGormHolidayRepository(db),
+		Corrections: NewGormCorrectionRepository(db),
+		Overtimes:   NewGormOvertimeRepository(db),
+		Stats:       NewGormStatsRepository(db),
+		// Transaksi bersarang otomatis memakai SAVEPOINT
+		transact: func(fn func(tx Repositories) error) error {
+			return db.Transaction(func(tx *gorm.DB) error {
+				return fn(NewGormRepositories(tx))
+			})
+		},
+	}
+}
+
+// NewMemoryRepositories membuat semua repository in-memory (untuk test)
+func NewMemoryRepositories() Repositories {
+	store := NewMemoryStore()
+	repos := Repositories{
+		Employees:   NewMemoryEmployeeRepository(store),
+		Departments: NewMemoryDepartmentRepository(store),
+		Attendances: NewMemoryAttendanceRepository(store),
+		Users:       NewMemoryUserRepository(store),
+		Sequences:   NewMemorySequenceRepository(store),
+		Shifts:      NewMemoryShiftRepository(store),
+		Leaves:      NewMemoryLeaveRepository(store),
+		Holidays:    NewMemoryHolidayRepository(store),
+		Corrections: NewMemoryCorrectionRepository(store),
+		Overtimes:   NewMemoryOvertimeRepository(store),
+		Stats:       NewMemoryStatsRepository(store),
+	}
+
+	// Di dalam transaksi, transaksi bersarang langsung dijalankan (ikut rollback luar)
+	inTx := repos
+	inTx.transact = func(fn func(tx Repositories) error) error {
+		return fn(inTx)
+	}
+	repos.transact = func(fn func(tx Repositories) error) error {
+		return store.transaction(func() error { return fn(inTx) })
+	}
+	return repos
+}
+
+// translateError mengubah gorm.ErrRecordNotFound menjadi ErrNotFound
+func translateError(err error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return ErrNotFound
+	}
+	return err
+}
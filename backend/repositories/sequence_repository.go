@@ -0,0 +1,94 @@
+package repositories
+
+import (
+	"errors"
+	"fleetify-backend/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type SequenceRepository interface {
+	// Next menaikkan counter name+period di dalam transaksi lalu mengembalikan
+	// nilai barunya. Kalau counter belum ada, dibuat mulai dari seed().
+	Next(name, period string, seed func() (int64, error)) (int64, error)
+	// EnsureAtLeast memastikan counter tidak lebih kecil dari value
+	EnsureAtLeast(name, period string, value int64) error
+}
+
+var errSequenceMissing = errors.New("sequence does not exist")
+
+type gormSequenceRepository struct {
+	db *gorm.DB
+}
+
+func NewGormSequenceRepository(db *gorm.DB) SequenceRepository {
+	return &gormSequenceRepository{db: db}
+}
+
+func (r *gormSequenceRepository) Next(name, period string, seed func() (int64, error)) (int64, error) {
+	var lastErr error
+	for attempt := 0; attempt < 3; attempt++ {
+		var value int64
+		err := r.db.Transaction(func(tx *gorm.DB) error {
+			// UPDATE mengunci baris counter, jadi request paralel antre di sini
+			res := tx.Model(&models.IDSequence{}).
+				Where("name = ? AND period = ?", name, period).
+				Updates(map[string]interface{}{"value": gorm.Expr("value + 1"), "updated_at": time.Now()})
+			if res.Error != nil {
+				return res.Error
+			}
+			if res.RowsAffected == 0 {
+				return errSequenceMissing
+			}
+			return tx.Model(&models.IDSequence{}).
+				Where("name = ? AND period = ?", name, period).
+				Pluck("value", &value).Error
+		})
+		if err == nil {
+			return value, nil
+		}
+		if !errors.Is(err, errSequenceMissing) {
+			return 0, err
+		}
+
+		// Counter belum ada: backfill dari data lama. Kalau request lain
+		// lebih dulu membuatnya, insert gagal dan kita ulang dari UPDATE.
+		start, err := seed()
+		if err != nil {
+			return 0, err
+		}
+		// Insert di transaksi sendiri (SAVEPOINT kalau dipanggil di dalam transaksi lain),
+		// supaya insert yang gagal tidak membatalkan transaksi pemanggil di PostgreSQL
+		lastErr = r.db.Transaction(func(tx *gorm.DB) error {
+			return tx.Create(&models.IDSequence{Name: name, Period: period, Value: start}).Error
+		})
+	}
+	if lastErr == nil {
+		lastErr = errSequenceMissing
+	}
+	return 0, lastErr
+}
+
+func (r *gormSequenceRepository) EnsureAtLeast(name, period string, value int64) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&models.IDSequence{}).
+			Where("name = ? AND period = ? AND value < ?", name, period, value).
+			Updates(map[string]interface{}{"value": value, "updated_at": time.Now()})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected > 0 {
+			return nil
+		}
+
+		var count int64
+		if err := tx.Model(&models.IDSequence{}).Where("name = ? AND period = ?", name, period).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return nil
+		}
+		return tx.Create(&models.IDSequence{Name: name, Period: period, Value: value}).Error
+	})
+}
@@ -0,0 +1,90 @@
+package repositories
+
+import (
+	"fleetify-backend/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ShiftRepository interface {
+	// FindAll semua shift beserta hari-harinya
+	FindAll() ([]models.Shift, error)
+	FindByID(id uint) (*models.Shift, error)
+	// Create menyimpan shift beserta Days
+	Create(shift *models.Shift) error
+	// Update menyimpan shift dan mengganti seluruh Days
+	Update(shift *models.Shift) error
+	Delete(shift *models.Shift) error
+	// CountAssignments jumlah department dan employee yang memakai shift
+	CountAssignments(id uint) (departments int64, employees int64, err error)
+}
+
+type gormShiftRepository struct {
+	db *gorm.DB
+}
+
+func NewGormShiftRepository(db *gorm.DB) ShiftRepository {
+	return &gormShiftRepository{db: db}
+}
+
+func (r *gormShiftRepository) FindAll() ([]models.Shift, error) {
+	var shifts []models.Shift
+	err := r.db.Preload("Days", orderByWeekday).Find(&shifts).Error
+	return shifts, err
+}
+
+func (r *gormShiftRepository) FindByID(id uint) (*models.Shift, error) {
+	var shift models.Shift
+	if err := r.db.Preload("Days", orderByWeekday).First(&shift, id).Error; err != nil {
+		return nil, translateError(err)
+	}
+	return &shift, nil
+}
+
+func (r *gormShiftRepository) Create(shift *models.Shift) error {
+	return r.db.Create(shift).Error
+}
+
+func (r *gormShiftRepository) Update(shift *models.Shift) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations).Save(shift).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("shift_id = ?", shift.ID).Delete(&models.ShiftDay{}).Error; err != nil {
+			return err
+		}
+		for i := range shift.Days {
+			shift.Days[i].ID = 0
+			shift.Days[i].ShiftID = shift.ID
+		}
+		if len(shift.Days) == 0 {
+			return nil
+		}
+		return tx.Create(&shift.Days).Error
+	})
+}
+
+func (r *gormShiftRepository) Delete(shift *models.Shift) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("shift_id = ?", shift.ID).Delete(&models.ShiftDay{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(shift).Error
+	})
+}
+
+func (r *gormShiftRepository) CountAssignments(id uint) (int64, int64, error) {
+	var departments, employees int64
+	if err := r.db.Model(&models.Department{}).Where("shift_id = ?", id).Count(&departments).Error; err != nil {
+		return 0, 0, err
+	}
+	if err := r.db.Model(&models.Employee{}).Where("shift_id = ?", id).Count(&employees).Error; err != nil {
+		return 0, 0, err
+	}
+	return departments, employees, nil
+}
+
+func orderByWeekday(db *gorm.DB) *gorm.DB {
+	return db.Order("weekday")
+}
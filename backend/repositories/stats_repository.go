@@ -0,0 +1,193 @@
+package repositories
+
+import (
+	"fleetify-backend/models"
+	"fmt"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// StatsFilter rentang tanggal bisnis (YYYY-MM-DD, inklusif) dan department untuk statistik
+type StatsFilter struct {
+	DateFrom     string
+	DateTo       string
+	DepartmentID *uint
+}
+
+// DailyAttendanceStats jumlah employee per tanggal bisnis
+type DailyAttendanceStats struct {
+	Date    string `json:"date"`
+	Present int    `json:"present"` // employee yang clock in
+	Late    int    `json:"late"`    // employee dengan clock in berstatus telat
+	Absent  int    `json:"absent"`  // absence_records dari job deteksi absen
+	OnTime  int    `json:"-"`       // clock in berstatus on_time
+	Rated   int    `json:"-"`       // clock in yang dinilai ketepatan waktunya (on_time / telat)
+}
+
+// LateEmployeeStats employee yang paling sering telat
+type LateEmployeeStats struct {
+	EmployeeID   string `json:"employee_id"`
+	Name         string `json:"name"`
+	DepartmentID uint   `json:"department_id"`
+	LateCount    int    `json:"late_count"`
+	LateMinutes  int    `json:"late_minutes"`
+}
+
+// Status clock in yang dihitung telat (sama dengan schedule.StatusSlightlyLate, dst)
+var lateStatuses = []string{"slightly_late", "late", "very_late"}
+
+// statusOnTime sama dengan schedule.StatusOnTime
+const statusOnTime = "on_time"
+
+// StatsRepository agregasi absensi untuk dashboard, dihitung di database
+type StatsRepository interface {
+	// Headcount employee aktif (belum dihapus, sudah bergabung dan belum berhenti) pada waktu at
+	Headcount(departmentID *uint, at time.Time) (int, error)
+	// Daily hadir / telat / absen per tanggal bisnis, urut tanggal
+	Daily(filter StatsFilter) ([]DailyAttendanceStats, error)
+	// AverageClockIn rata-rata jam clock in dalam detik sejak tengah malam, count = jumlah clock in
+	AverageClockIn(filter StatsFilter) (seconds float64, count int, err error)
+	// TopLate limit employee dengan clock in telat terbanyak
+	TopLate(filter StatsFilter, limit int) ([]LateEmployeeStats, error)
+}
+
+// timeOfDaySeconds ekspresi detik sejak tengah malam per dialect, %[1]s diganti nama kolom.
+// SQLite menyimpan waktu sebagai teks "YYYY-MM-DD HH:MM:SS...", jadi cukup substr.
+var timeOfDaySeconds = map[string]string{
+	"mysql":    "TIME_TO_SEC(%[1]s)",
+	"postgres": "EXTRACT(EPOCH FROM CAST(%[1]s AS TIME))",
+	"sqlite":   "(CAST(substr(%[1]s, 12, 2) AS INTEGER) * 3600 + CAST(substr(%[1]s, 15, 2) AS INTEGER) * 60 + CAST(substr(%[1]s, 18, 2) AS INTEGER))",
+}
+
+type gormStatsRepository struct {
+	db *gorm.DB
+}
+
+func NewGormStatsRepository(db *gorm.DB) StatsRepository {
+	return &gormStatsRepository{db: db}
+}
+
+func (r *gormStatsRepository) Headcount(departmentID *uint, at time.Time) (int, error) {
+	db := r.db.Model(&models.Employee{}).
+		Where("created_at <= ?", at).
+		Where("terminated_at IS NULL OR terminated_at > ?", at)
+	if departmentID != nil {
+		db = db.Where("department_id = ?", *departmentID)
+	}
+	var count int64
+	err := db.Count(&count).Error
+	return int(count), err
+}
+
+// clockIns history clock in di rentang tanggal bisnis, join attendances (h, a) dan employees (e)
+func (r *gormStatsRepository) clockIns(filter StatsFilter) *gorm.DB {
+	db := r.db.Table("attendance_histories AS h").
+		Joins("JOIN attendances AS a ON a.attendance_id = h.attendance_id").
+		Joins("JOIN employees AS e ON e.employee_id = h.employee_id").
+		Where("h.attendance_type = ?", models.AttendanceTypeClockIn)
+	if filter.DateFrom != "" {
+		db = db.Where("a.business_date >= ?", filter.DateFrom)
+	}
+	if filter.DateTo != "" {
+		db = db.Where("a.business_date <= ?", filter.DateTo)
+	}
+	if filter.DepartmentID != nil {
+		db = db.Where("e.department_id = ?", *filter.DepartmentID)
+	}
+	return db
+}
+
+func (r *gormStatsRepository) Daily(filter StatsFilter) ([]DailyAttendanceStats, error) {
+	var attended []DailyAttendanceStats
+	err := r.clockIns(filter).
+		Select(`a.business_date AS date,
+			COUNT(DISTINCT h.employee_id) AS present,
+			COUNT(DISTINCT CASE WHEN h.status IN ? THEN h.employee_id END) AS late,
+			SUM(CASE WHEN h.status = ? THEN 1 ELSE 0 END) AS on_time,
+			SUM(CASE WHEN h.status = ? OR h.status IN ? THEN 1 ELSE 0 END) AS rated`,
+			lateStatuses, statusOnTime, statusOnTime, lateStatuses).
+		Group("a.business_date").
+		Scan(&attended).Error
+	if err != nil {
+		return nil, err
+	}
+
+	absentQuery := r.db.Table("absence_records AS r").
+		Joins("JOIN employees AS e ON e.employee_id = r.employee_id")
+	if filter.DateFrom != "" {
+		absentQuery = absentQuery.Where("r.business_date >= ?", filter.DateFrom)
+	}
+	if filter.DateTo != "" {
+		absentQuery = absentQuery.Where("r.business_date <= ?", filter.DateTo)
+	}
+	if filter.DepartmentID != nil {
+		absentQuery = absentQuery.Where("e.department_id = ?", *filter.DepartmentID)
+	}
+	var absent []DailyAttendanceStats
+	err = absentQuery.
+		Select("r.business_date AS date, COUNT(*) AS absent").
+		Group("r.business_date").
+		Scan(&absent).Error
+	if err != nil {
+		return nil, err
+	}
+	return mergeDaily(attended, absent), nil
+}
+
+func (r *gormStatsRepository) AverageClockIn(filter StatsFilter) (float64, int, error) {
+	expression, ok := timeOfDaySeconds[r.db.Dialector.Name()]
+	if !ok {
+		return 0, 0, fmt.Errorf("unsupported database dialect %q", r.db.Dialector.Name())
+	}
+	var row struct {
+		Seconds *float64
+		Count   int
+	}
+	err := r.clockIns(filter).
+		Select(fmt.Sprintf("AVG(%s) AS seconds, COUNT(*) AS count", fmt.Sprintf(expression, "a.clock_in"))).
+		Scan(&row).Error
+	if err != nil || row.Seconds == nil {
+		return 0, row.Count, err
+	}
+	return *row.Seconds, row.Count, nil
+}
+
+func (r *gormStatsRepository) TopLate(filter StatsFilter, limit int) ([]LateEmployeeStats, error) {
+	var rows []LateEmployeeStats
+	err := r.clockIns(filter).
+		Where("h.status IN ?", lateStatuses).
+		Select("h.employee_id, e.name, e.department_id, COUNT(*) AS late_count, SUM(h.minutes_late) AS late_minutes").
+		Group("h.employee_id, e.name, e.department_id").
+		Order("late_count DESC, late_minutes DESC, h.employee_id").
+		Limit(limit).
+		Scan(&rows).Error
+	return rows, err
+}
+
+// mergeDaily gabungkan hasil hadir dan absen per tanggal, urut tanggal
+func mergeDaily(attended, absent []DailyAttendanceStats) []DailyAttendanceStats {
+	byDate := map[string]*DailyAttendanceStats{}
+	var dates []string
+	row := func(date string) *DailyAttendanceStats {
+		if _, ok := byDate[date]; !ok {
+			byDate[date] = &DailyAttendanceStats{Date: date}
+			dates = append(dates, date)
+		}
+		return byDate[date]
+	}
+	for _, day := range attended {
+		*row(day.Date) = day
+	}
+	for _, day := range absent {
+		row(day.Date).Absent = day.Absent
+	}
+	sort.Strings(dates)
+
+	daily := make([]DailyAttendanceStats, 0, len(dates))
+	for _, date := range dates {
+		daily = append(daily, *byDate[date])
+	}
+	return daily
+}
@@ -2,30 +2,122 @@ package routes
 
 import (
 	"fleetify-backend/controllers"
+	"fleetify-backend/middlewares"
+	"fleetify-backend/models"
+	"fleetify-backend/repositories"
 
 	"github.com/gin-gonic/gin"
 )
 
 // RegisterRoutes untuk registrasi semua route
-func RegisterRoutes(app *gin.Engine) {
+func RegisterRoutes(app *gin.Engine, repos repositories.Repositories) {
 	api := app.Group("/api") // semua route pakai prefix /api
 
+	// Handler
+	authController := controllers.NewAuthController(repos.Users)
+	userController := controllers.NewUserController(repos.Users, repos.Employees)
+	employeeController := controllers.NewEmployeeController(repos.Employees, repos.Departments, repos.Shifts, repos)
+	departmentController := controllers.NewDepartmentController(repos.Departments, repos.Employees, repos.Shifts, repos)
+	attendanceController := controllers.NewAttendanceController(repos.Attendances, repos.Employees, repos.Leaves, repos.Holidays, repos)
+	shiftController := controllers.NewShiftController(repos.Shifts)
+	leaveController := controllers.NewLeaveController(repos.Leaves, repos.Employees, repos.Holidays, repos)
+	holidayController := controllers.NewHolidayController(repos.Holidays, repos.Departments, repos)
+	correctionController := controllers.NewCorrectionController(repos.Corrections, repos.Attendances, repos.Employees, repos)
+	overtimeController := controllers.NewOvertimeController(repos.Overtimes, repos.Attendances, repos.Employees, repos.Holidays, repos)
+	timesheetController := controllers.NewTimesheetController(repos.Attendances, repos.Employees, repos.Leaves, repos.Holidays, repos.Overtimes)
+	statsController := controllers.NewStatsController(repos.Stats, repos.Employees)
+
+	// Auth routes (public)
+	api.POST("/auth/login", authController.Login)
+	api.POST("/auth/refresh", authController.RefreshToken)
+
+	// Route di bawah ini wajib pakai access token
+	protected := api.Group("", middlewares.AuthRequired())
+
+	// Policy per route (admin selalu diizinkan)
+	adminOnly := middlewares.RequireRoles()
+	hrOnly := middlewares.RequireRoles(models.RoleHR)
+	// Manager & employee hanya untuk dirinya sendiri, dicek di handler
+	canPunch := middlewares.RequireRoles(models.RoleHR, models.RoleManager, models.RoleEmployee)
+	// Manager hanya department sendiri, dicek di handler
+	canReadLogs := middlewares.RequireRoles(models.RoleHR, models.RoleManager)
+
+	// User routes
+	protected.GET("/users", adminOnly, userController.GetAllUsers)
+	protected.POST("/user", adminOnly, userController.CreateUser)
+	protected.PATCH("/user/:id", adminOnly, userController.UpdateUser)
+
 	// Employee routes
-	api.GET("/employees", controllers.GetAllEmployees)
-	api.GET("/employee/:id", controllers.GetEmployeeDetail)
-	api.POST("/employee", controllers.CreateEmployee)
-	api.PATCH("/employee/:id", controllers.UpdateEmployee)
-	api.DELETE("/employee/:id", controllers.DeleteEmployee)
+	protected.GET("/employees", employeeController.GetAllEmployees)
+	protected.GET("/employee/:id", employeeController.GetEmployeeDetail)
+	protected.POST("/employee", hrOnly, employeeController.CreateEmployee)
+	protected.PATCH("/employee/:id", hrOnly, employeeController.UpdateEmployee)
+	protected.DELETE("/employee/:id", hrOnly, employeeController.DeleteEmployee)
+	protected.PUT("/employee/:id/shift", hrOnly, employeeController.AssignShift)
+	protected.PUT("/employee/:id/terminate", hrOnly, employeeController.TerminateEmployee)
+	protected.DELETE("/employee/:id/terminate", hrOnly, employeeController.ReactivateEmployee)
+	protected.POST("/employee/:id/restore", hrOnly, employeeController.RestoreEmployee)
+	protected.DELETE("/employee/:id/purge", adminOnly, employeeController.PurgeEmployee)
+	protected.GET("/employee/:id/timesheet", timesheetController.GetTimesheet)
 
 	// Departement routes
-	api.GET("/departements", controllers.GetAllDepartments)
-	api.GET("/departement/:id", controllers.GetDepartmentDetail)
-	api.POST("/departement", controllers.CreateDepartment)
-	api.PATCH("/departement/:id", controllers.UpdateDepartment)
-	api.DELETE("/departement/:id", controllers.DeleteDepartment)
+	protected.GET("/departements", departmentController.GetAllDepartments)
+	protected.GET("/departement/:id", departmentController.GetDepartmentDetail)
+	protected.POST("/departement", adminOnly, departmentController.CreateDepartment)
+	protected.PATCH("/departement/:id", adminOnly, departmentController.UpdateDepartment)
+	protected.DELETE("/departement/:id", adminOnly, departmentController.DeleteDepartment)
+	protected.PUT("/departement/:id/shift", adminOnly, departmentController.AssignShift)
+	protected.POST("/departement/:id/restore", adminOnly, departmentController.RestoreDepartment)
+	protected.DELETE("/departement/:id/purge", adminOnly, departmentController.PurgeDepartment)
+
+	// Shift routes
+	protected.GET("/shifts", shiftController.GetAllShifts)
+	protected.GET("/shift/:id", shiftController.GetShiftDetail)
+	protected.POST("/shift", adminOnly, shiftController.CreateShift)
+	protected.PATCH("/shift/:id", adminOnly, shiftController.UpdateShift)
+	protected.DELETE("/shift/:id", adminOnly, shiftController.DeleteShift)
 
 	// Attendance routes
-	api.POST("/attendance", controllers.CreateAttendance)
-	api.PUT("/attendance/:id", controllers.UpdateAttendance)
-	api.GET("/attendance/logs", controllers.GetAttendanceLogs)
+	protected.POST("/attendance", canPunch, attendanceController.CreateAttendance)
+	protected.PUT("/attendance/:id", canPunch, attendanceController.UpdateAttendance)
+	protected.POST("/attendance/:id/break", canPunch, attendanceController.StartBreak)
+	protected.PUT("/attendance/:id/break", canPunch, attendanceController.EndBreak)
+	protected.GET("/attendance/logs", canReadLogs, attendanceController.GetAttendanceLogs)
+	protected.GET("/attendance/summary", overtimeController.GetAttendanceSummary)
+	protected.GET("/attendance/reviews", canReadLogs, attendanceController.GetAutoClosedReviews)
+	protected.PUT("/attendance/review/:id/confirm", hrOnly, attendanceController.ConfirmAutoClosed)
+	protected.PUT("/attendance/review/:id/correct", hrOnly, attendanceController.CorrectAutoClosed)
+
+	// Correction routes, employee hanya untuk dirinya sendiri dan manager hanya department sendiri (dicek di handler)
+	protected.GET("/attendance/corrections", correctionController.GetCorrections)
+	protected.GET("/attendance/correction/:id", correctionController.GetCorrectionDetail)
+	protected.POST("/attendance/correction", canPunch, correctionController.CreateCorrection)
+	protected.PUT("/attendance/correction/:id/approve", canReadLogs, correctionController.ApproveCorrection)
+	protected.PUT("/attendance/correction/:id/reject", canReadLogs, correctionController.RejectCorrection)
+
+	// Overtime routes, employee hanya miliknya sendiri dan manager hanya department sendiri (dicek di handler)
+	protected.GET("/overtimes", overtimeController.GetOvertimes)
+	protected.PUT("/overtime/:id/approve", canReadLogs, overtimeController.ApproveOvertime)
+	protected.PUT("/overtime/:id/reject", canReadLogs, overtimeController.RejectOvertime)
+
+	// Stats routes, manager hanya department sendiri (dicek di handler)
+	protected.GET("/stats/attendance", canReadLogs, statsController.GetAttendanceStats)
+
+	// Leave routes, employee hanya untuk dirinya sendiri (dicek di handler)
+	protected.GET("/leave-types", leaveController.GetLeaveTypes)
+	protected.GET("/leaves", leaveController.GetLeaves)
+	protected.GET("/leave/:id", leaveController.GetLeaveDetail)
+	protected.POST("/leave", leaveController.CreateLeave)
+	protected.PUT("/leave/:id/approve", hrOnly, leaveController.ApproveLeave)
+	protected.PUT("/leave/:id/reject", hrOnly, leaveController.RejectLeave)
+	protected.PUT("/leave/:id/cancel", leaveController.CancelLeave)
+	protected.GET("/employee/:id/leave-balance", leaveController.GetLeaveBalance)
+
+	// Holiday routes
+	protected.GET("/holidays", holidayController.GetAllHolidays)
+	protected.GET("/holiday/:id", holidayController.GetHolidayDetail)
+	protected.POST("/holiday", hrOnly, holidayController.CreateHoliday)
+	protected.PATCH("/holiday/:id", hrOnly, holidayController.UpdateHoliday)
+	protected.DELETE("/holiday/:id", hrOnly, holidayController.DeleteHoliday)
+	protected.POST("/holidays/import", hrOnly, holidayController.ImportHolidays)
 }
@@ -0,0 +1,49 @@
+package schedule
+
+import (
+	"encoding/json"
+	"fleetify-backend/models"
+	"time"
+)
+
+// Apply menghitung status, menit telat / pulang cepat dan snapshot aturan untuk
+// satu history (punch = DateAttendance) lalu menyimpannya di history.
+// Employee harus sudah di-preload seperti pada Resolve.
+func Apply(history *models.AttendanceHistory, employee models.Employee, attendance models.Attendance, holidays Calendar) {
+	businessDate := BusinessDateOf(attendance)
+	rule := Resolve(employee, businessDate, holidays)
+	result := Evaluate(rule, businessDate, history.AttendanceType, history.DateAttendance)
+
+	history.Status = result.Status
+	history.MinutesLate = result.MinutesLate
+	history.MinutesEarly = result.MinutesEarly
+	history.Description = Description(result.Status, history.AttendanceType)
+	history.RuleSnapshot = rule.Snapshot()
+}
+
+// BusinessDateOf tanggal bisnis attendance, data lama tanpa business_date
+// pakai tanggal clock in
+func BusinessDateOf(attendance models.Attendance) time.Time {
+	if date, err := ParseDate(attendance.BusinessDate, attendance.ClockIn.Location()); err == nil {
+		return date
+	}
+	return dateOf(attendance.ClockIn)
+}
+
+// Snapshot aturan dalam bentuk JSON untuk disimpan di attendance_histories
+func (r Rule) Snapshot() string {
+	data, err := json.Marshal(r)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+// ParseSnapshot kebalikan dari Snapshot
+func ParseSnapshot(snapshot string) (Rule, bool) {
+	var rule Rule
+	if snapshot == "" || json.Unmarshal([]byte(snapshot), &rule) != nil {
+		return Rule{}, false
+	}
+	return rule, true
+}
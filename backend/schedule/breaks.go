@@ -0,0 +1,67 @@
+package schedule
+
+import (
+	"fleetify-backend/models"
+	"sort"
+	"time"
+)
+
+// Break satu jeda istirahat, End nil = masih istirahat
+type Break struct {
+	Start time.Time  `json:"start"`
+	End   *time.Time `json:"end"`
+}
+
+// Breaks pasangan mulai / selesai istirahat dari history satu attendance.
+// History selain type 6 / 7 diabaikan.
+func Breaks(histories []models.AttendanceHistory) []Break {
+	sorted := make([]models.AttendanceHistory, 0, len(histories))
+	for _, history := range histories {
+		if history.AttendanceType == models.AttendanceTypeBreakStart || history.AttendanceType == models.AttendanceTypeBreakEnd {
+			sorted = append(sorted, history)
+		}
+	}
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].DateAttendance.Before(sorted[j].DateAttendance) })
+
+	var breaks []Break
+	for _, history := range sorted {
+		switch {
+		case history.AttendanceType == models.AttendanceTypeBreakStart:
+			breaks = append(breaks, Break{Start: history.DateAttendance})
+		case len(breaks) > 0 && breaks[len(breaks)-1].End == nil:
+			end := history.DateAttendance
+			breaks[len(breaks)-1].End = &end
+		}
+	}
+	return breaks
+}
+
+// OpenBreak istirahat yang belum selesai
+func OpenBreak(breaks []Break) (Break, bool) {
+	if len(breaks) > 0 && breaks[len(breaks)-1].End == nil {
+		return breaks[len(breaks)-1], true
+	}
+	return Break{}, false
+}
+
+// breakTime total istirahat yang beririsan dengan rentang from - to.
+// Istirahat yang belum selesai dihitung sampai to.
+func breakTime(breaks []Break, from, to time.Time) time.Duration {
+	var total time.Duration
+	for _, b := range breaks {
+		start, end := b.Start, to
+		if b.End != nil {
+			end = *b.End
+		}
+		if start.Before(from) {
+			start = from
+		}
+		if end.After(to) {
+			end = to
+		}
+		if end.After(start) {
+			total += end.Sub(start)
+		}
+	}
+	return total
+}
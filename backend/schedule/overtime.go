@@ -0,0 +1,66 @@
+package schedule
+
+import (
+	"fleetify-backend/models"
+	"time"
+)
+
+// WorkedTime jam kerja satu attendance dalam menit
+type WorkedTime struct {
+	WorkedMinutes     int    `json:"worked_minutes"`   // bersih, tanpa istirahat
+	RegularMinutes    int    `json:"regular_minutes"`  // di dalam jam shift
+	OvertimeMinutes   int    `json:"overtime_minutes"` // setelah batas minimum dan pembulatan
+	OvertimeType      string `json:"overtime_type,omitempty"`
+	BreakMinutes      int    `json:"break_minutes"`
+	BreakShortMinutes int    `json:"break_short_minutes"` // kekurangan dari MinBreakMinutes department
+}
+
+// Worked menghitung jam kerja bersih (dikurangi istirahat) attendance yang dimulai pada
+// businessDate. Di hari kerja lembur dihitung dari jam pulang shift, di hari libur / di
+// luar shift seluruh jam kerja dianggap lembur. Lembur di bawah OvertimeMinMinutes tidak
+// dihitung, sisanya dibulatkan ke bawah per OvertimeRoundingMinutes.
+func Worked(rule Rule, businessDate time.Time, clockIn, clockOut time.Time, breaks []Break) WorkedTime {
+	breakDuration := breakTime(breaks, clockIn, clockOut)
+	worked := floorMinutes(clockOut.Sub(clockIn) - breakDuration)
+	result := WorkedTime{WorkedMinutes: worked, RegularMinutes: worked, BreakMinutes: floorMinutes(breakDuration)}
+	if short := rule.Policy.MinBreakMinutes - result.BreakMinutes; short > 0 {
+		result.BreakShortMinutes = short
+	}
+
+	overtime := worked
+	result.OvertimeType = models.OvertimeHoliday
+	if _, end, ok := rule.Window(businessDate); ok && rule.IsWorkday() {
+		if clockIn.After(end) {
+			end = clockIn
+		}
+		overtime = floorMinutes(clockOut.Sub(end) - breakTime(breaks, end, clockOut))
+		result.OvertimeType = models.OvertimeWeekday
+	}
+	result.RegularMinutes = worked - overtime
+
+	overtime = rule.Policy.roundOvertime(overtime)
+	result.OvertimeMinutes = overtime
+	if overtime == 0 {
+		result.OvertimeType = ""
+	}
+	return result
+}
+
+// roundOvertime terapkan batas minimum dan pembulatan ke bawah
+func (p Policy) roundOvertime(minutes int) int {
+	if minutes <= 0 || minutes < p.OvertimeMinMinutes {
+		return 0
+	}
+	if p.OvertimeRoundingMinutes > 0 {
+		minutes -= minutes % p.OvertimeRoundingMinutes
+	}
+	return minutes
+}
+
+// floorMinutes durasi dalam menit dibulatkan ke bawah, negatif dianggap 0
+func floorMinutes(d time.Duration) int {
+	if d <= 0 {
+		return 0
+	}
+	return int(d.Minutes())
+}
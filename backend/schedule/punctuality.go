@@ -0,0 +1,142 @@
+package schedule
+
+import (
+	"fleetify-backend/models"
+	"math"
+	"time"
+)
+
+// Status ketepatan waktu absensi
+const (
+	StatusOnTime        = "on_time"
+	StatusSlightlyLate  = "slightly_late"
+	StatusLate          = "late"
+	StatusVeryLate      = "very_late"
+	StatusEarlyLeave    = "early_leave"
+	StatusNonWorkingDay = "non_working_day"
+	StatusOnLeave       = "on_leave"     // cuti yang sudah di-approve
+	StatusAbsent        = "absent"       // hari kerja tanpa attendance
+	StatusHolidayWork   = "holiday_work" // masuk di hari libur
+	StatusAutoClosed    = "auto_closed"  // clock out diisi job auto-close, menunggu review HR
+)
+
+// Default toleransi untuk department baru
+const (
+	DefaultGracePeriodMinutes         = 0
+	DefaultSlightlyLateMinutes        = 15
+	DefaultLateMinutes                = 60
+	DefaultEarlyLeaveToleranceMinutes = 0
+	DefaultOvertimeMinMinutes         = 30
+	DefaultOvertimeRoundingMinutes    = 15
+	DefaultMinBreakMinutes            = 0
+)
+
+// Policy toleransi keterlambatan per department (dalam menit)
+type Policy struct {
+	GracePeriodMinutes         int `json:"grace_period_minutes"`          // telat <= grace masih on time
+	SlightlyLateMinutes        int `json:"slightly_late_minutes"`         // telat <= ini = slightly late
+	LateMinutes                int `json:"late_minutes"`                  // telat <= ini = late, lebih = very late
+	EarlyLeaveToleranceMinutes int `json:"early_leave_tolerance_minutes"` // pulang cepat <= ini masih on time
+	OvertimeMinMinutes         int `json:"overtime_min_minutes"`          // lembur < ini tidak dihitung
+	OvertimeRoundingMinutes    int `json:"overtime_rounding_minutes"`     // lembur dibulatkan ke bawah per kelipatan ini
+	MinBreakMinutes            int `json:"min_break_minutes"`             // minimal total istirahat per attendance
+}
+
+// PolicyOf ambil policy dari department
+func PolicyOf(department models.Department) Policy {
+	return Policy{
+		GracePeriodMinutes:         department.GracePeriodMinutes,
+		SlightlyLateMinutes:        department.SlightlyLateMinutes,
+		LateMinutes:                department.LateMinutes,
+		EarlyLeaveToleranceMinutes: department.EarlyLeaveToleranceMinutes,
+		OvertimeMinMinutes:         department.OvertimeMinMinutes,
+		OvertimeRoundingMinutes:    department.OvertimeRoundingMinutes,
+		MinBreakMinutes:            department.MinBreakMinutes,
+	}
+}
+
+// Result hasil evaluasi satu clock in / clock out
+type Result struct {
+	Status       string `json:"status"`
+	MinutesLate  int    `json:"minutes_late"`
+	MinutesEarly int    `json:"minutes_early"`
+}
+
+// CheckIn menilai clock in terhadap jam mulai shift. Menit dihitung dari jam mulai
+// (dibulatkan ke atas), grace period hanya menentukan kapan mulai dianggap telat.
+func (p Policy) CheckIn(start, clockIn time.Time) Result {
+	minutes := ceilMinutes(clockIn.Sub(start))
+	result := Result{Status: StatusOnTime, MinutesLate: minutes}
+	switch {
+	case minutes <= p.GracePeriodMinutes:
+		result.Status = StatusOnTime
+	case minutes <= p.SlightlyLateMinutes:
+		result.Status = StatusSlightlyLate
+	case minutes <= p.LateMinutes:
+		result.Status = StatusLate
+	default:
+		result.Status = StatusVeryLate
+	}
+	return result
+}
+
+// CheckOut menilai clock out terhadap jam selesai shift
+func (p Policy) CheckOut(end, clockOut time.Time) Result {
+	minutes := ceilMinutes(end.Sub(clockOut))
+	result := Result{Status: StatusOnTime, MinutesEarly: minutes}
+	if minutes > p.EarlyLeaveToleranceMinutes {
+		result.Status = StatusEarlyLeave
+	}
+	return result
+}
+
+// Evaluate menilai satu punch (clock in / clock out) untuk shift yang
+// dimulai pada businessDate
+func Evaluate(rule Rule, businessDate time.Time, attendanceType models.AttendanceEventType, punch time.Time) Result {
+	if rule.Holiday != "" {
+		return Result{Status: StatusHolidayWork}
+	}
+	start, end, ok := rule.Window(businessDate)
+	if !ok {
+		return Result{Status: StatusNonWorkingDay}
+	}
+	if attendanceType == models.AttendanceTypeClockOut {
+		return rule.Policy.CheckOut(end, punch)
+	}
+	return rule.Policy.CheckIn(start, punch)
+}
+
+// Description teks status untuk ditampilkan (clock in / clock out)
+func Description(status string, attendanceType models.AttendanceEventType) string {
+	suffix := " (Check-in)"
+	if attendanceType == models.AttendanceTypeClockOut {
+		suffix = " (Check-out)"
+	}
+	switch status {
+	case StatusOnTime:
+		return "On Time" + suffix
+	case StatusSlightlyLate:
+		return "Slightly Late" + suffix
+	case StatusLate:
+		return "Late" + suffix
+	case StatusVeryLate:
+		return "Very Late" + suffix
+	case StatusEarlyLeave:
+		return "Early Leave"
+	case StatusNonWorkingDay:
+		return "Non-working Day" + suffix
+	case StatusHolidayWork:
+		return "Holiday Work" + suffix
+	case StatusAutoClosed:
+		return "Auto-closed" + suffix
+	}
+	return ""
+}
+
+// ceilMinutes durasi dalam menit dibulatkan ke atas, negatif dianggap 0
+func ceilMinutes(d time.Duration) int {
+	if d <= 0 {
+		return 0
+	}
+	return int(math.Ceil(d.Minutes()))
+}
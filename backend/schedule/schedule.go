@@ -0,0 +1,156 @@
+package schedule
+
+import (
+	"fleetify-backend/models"
+	"time"
+)
+
+// Format tanggal bisnis (attendances.business_date)
+const DateLayout = "2006-01-02"
+
+// Sumber aturan jam kerja
+const (
+	SourceEmployeeShift   = "employee_shift"
+	SourceDepartmentShift = "department_shift"
+	SourceDepartment      = "department"
+)
+
+// Rule aturan jam kerja yang berlaku untuk satu employee pada satu tanggal
+type Rule struct {
+	Source     string `json:"source"`
+	ShiftID    *uint  `json:"shift_id,omitempty"`
+	ShiftName  string `json:"shift_name,omitempty"`
+	WorkingDay bool   `json:"working_day"`
+	StartTime  string `json:"start_time"`        // HH:mm:ss
+	EndTime    string `json:"end_time"`          // HH:mm:ss
+	Overnight  bool   `json:"overnight"`         // EndTime jatuh di hari berikutnya
+	Holiday    string `json:"holiday,omitempty"` // nama hari libur yang jatuh pada tanggal ini
+	Policy     Policy `json:"policy"`            // toleransi keterlambatan department
+}
+
+// Calendar daftar hari libur yang dipakai saat mencari aturan, nil = tanpa hari libur
+type Calendar []models.Holiday
+
+// On hari libur yang berlaku untuk department pada tanggal tersebut
+func (c Calendar) On(date time.Time, departmentID uint) (models.Holiday, bool) {
+	day := date.Format(DateLayout)
+	for _, holiday := range c {
+		if holiday.Date == day && holiday.AppliesTo(departmentID) {
+			return holiday, true
+		}
+	}
+	return models.Holiday{}, false
+}
+
+// Resolve mencari aturan yang berlaku: shift employee, lalu shift department,
+// terakhir MaxClockInTime/MaxClockOutTime department (berlaku setiap hari).
+// Hari libur di holidays ditandai di Rule.Holiday tanpa mengubah jam shift.
+// Employee harus sudah di-preload dengan Shift.Days, Department dan Department.Shift.Days.
+func Resolve(employee models.Employee, date time.Time, holidays Calendar) Rule {
+	var rule Rule
+	switch {
+	case employee.Shift != nil:
+		rule = fromShift(*employee.Shift, SourceEmployeeShift, date)
+	case employee.Department.Shift != nil:
+		rule = fromShift(*employee.Department.Shift, SourceDepartmentShift, date)
+	default:
+		rule = Rule{
+			Source:     SourceDepartment,
+			WorkingDay: true,
+			StartTime:  employee.Department.MaxClockInTime,
+			EndTime:    employee.Department.MaxClockOutTime,
+			Overnight:  IsOvernight(employee.Department.MaxClockInTime, employee.Department.MaxClockOutTime),
+		}
+	}
+	rule.Policy = PolicyOf(employee.Department)
+	if holiday, ok := holidays.On(date, employee.DepartmentID); ok {
+		rule.Holiday = holiday.Name
+	}
+	return rule
+}
+
+// IsWorkday hari kerja menurut shift dan bukan hari libur
+func (r Rule) IsWorkday() bool {
+	return r.WorkingDay && r.Holiday == ""
+}
+
+func fromShift(shift models.Shift, source string, date time.Time) Rule {
+	shiftID := shift.ID
+	rule := Rule{Source: source, ShiftID: &shiftID, ShiftName: shift.Name}
+
+	day, ok := shift.Day(date.Weekday())
+	if !ok || !day.IsWorkingDay || day.StartTime == nil || day.EndTime == nil {
+		return rule
+	}
+	rule.WorkingDay = true
+	rule.StartTime = *day.StartTime
+	rule.EndTime = *day.EndTime
+	rule.Overnight = IsOvernight(rule.StartTime, rule.EndTime)
+	return rule
+}
+
+// IsOvernight true kalau jam selesai tidak lebih besar dari jam mulai (format HH:mm:ss)
+func IsOvernight(start, end string) bool {
+	return start != "" && end != "" && end <= start
+}
+
+// Window jam mulai dan selesai lengkap dengan tanggal untuk shift yang dimulai pada
+// businessDate. Zona waktu mengikuti businessDate. ok=false kalau hari libur.
+func (r Rule) Window(businessDate time.Time) (start, end time.Time, ok bool) {
+	if !r.WorkingDay {
+		return time.Time{}, time.Time{}, false
+	}
+	start, errStart := atTime(businessDate, r.StartTime)
+	end, errEnd := atTime(businessDate, r.EndTime)
+	if errStart != nil || errEnd != nil {
+		return time.Time{}, time.Time{}, false
+	}
+	if r.Overnight {
+		end = end.AddDate(0, 0, 1)
+	}
+	return start, end, true
+}
+
+// BusinessDate tanggal mulai shift untuk sebuah clock in. Clock in setelah tengah malam
+// yang masih berada di dalam shift malam hari sebelumnya dihitung ke hari sebelumnya.
+func BusinessDate(employee models.Employee, clockIn time.Time) time.Time {
+	today := dateOf(clockIn)
+	yesterday := today.AddDate(0, 0, -1)
+
+	rule := Resolve(employee, yesterday, nil)
+	if rule.Overnight {
+		if _, end, ok := rule.Window(yesterday); ok && clockIn.Before(end) {
+			return yesterday
+		}
+	}
+	return today
+}
+
+// WorkingDays tanggal kerja employee dari from sampai to (inklusif), hari libur tidak dihitung
+func WorkingDays(employee models.Employee, from, to time.Time, holidays Calendar) []time.Time {
+	var days []time.Time
+	for date := dateOf(from); !date.After(to); date = date.AddDate(0, 0, 1) {
+		if Resolve(employee, date, holidays).IsWorkday() {
+			days = append(days, date)
+		}
+	}
+	return days
+}
+
+// ParseDate membaca business_date (YYYY-MM-DD) di zona waktu loc
+func ParseDate(value string, loc *time.Location) (time.Time, error) {
+	return time.ParseInLocation(DateLayout, value, loc)
+}
+
+func dateOf(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+func atTime(date time.Time, clock string) (time.Time, error) {
+	parsed, err := time.Parse("15:04:05", clock)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Date(date.Year(), date.Month(), date.Day(),
+		parsed.Hour(), parsed.Minute(), parsed.Second(), 0, date.Location()), nil
+}
@@ -0,0 +1,356 @@
+package schedule
+
+import (
+	"fleetify-backend/models"
+	"testing"
+	"time"
+)
+
+func clock(s string) *string { return &s }
+
+// weekdayShift shift Senin - Jumat start - end, Sabtu & Minggu libur
+func weekdayShift(id uint, name, start, end string) *models.Shift {
+	shift := &models.Shift{ID: id, Name: name}
+	for weekday := time.Sunday; weekday <= time.Saturday; weekday++ {
+		day := models.ShiftDay{ShiftID: id, Weekday: int(weekday)}
+		if weekday != time.Saturday && weekday != time.Sunday {
+			day.IsWorkingDay = true
+			day.StartTime = clock(start)
+			day.EndTime = clock(end)
+		}
+		shift.Days = append(shift.Days, day)
+	}
+	return shift
+}
+
+func date(s string) time.Time {
+	d, err := time.Parse(DateLayout, s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+func dateTime(s string) time.Time {
+	t, err := time.Parse("2006-01-02 15:04:05", s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func TestResolve(t *testing.T) {
+	department := models.Department{ID: 1, MaxClockInTime: "08:00:00", MaxClockOutTime: "17:00:00", LateMinutes: 60}
+	withDepartmentShift := department
+	withDepartmentShift.Shift = weekdayShift(1, "Office", "09:00:00", "18:00:00")
+	otherDepartment := uint(2)
+	holidays := Calendar{
+		{Date: "2026-10-19", Name: "Company Day", DepartmentID: &otherDepartment},
+		{Date: "2026-10-20", Name: "National Day"},
+	}
+
+	tests := []struct {
+		name        string
+		employee    models.Employee
+		date        string
+		wantSource  string
+		wantWorking bool
+		wantStart   string
+		wantEnd     string
+		wantNight   bool
+		wantHoliday string
+	}{
+		{
+			name:        "department hours apply every day",
+			employee:    models.Employee{DepartmentID: 1, Department: department},
+			date:        "2026-10-17", // Sabtu
+			wantSource:  SourceDepartment,
+			wantWorking: true,
+			wantStart:   "08:00:00",
+			wantEnd:     "17:00:00",
+		},
+		{
+			name:        "department shift on a weekday",
+			employee:    models.Employee{DepartmentID: 1, Department: withDepartmentShift},
+			date:        "2026-10-16", // Jumat
+			wantSource:  SourceDepartmentShift,
+			wantWorking: true,
+			wantStart:   "09:00:00",
+			wantEnd:     "18:00:00",
+		},
+		{
+			name:       "department shift on the weekend",
+			employee:   models.Employee{DepartmentID: 1, Department: withDepartmentShift},
+			date:       "2026-10-18", // Minggu
+			wantSource: SourceDepartmentShift,
+		},
+		{
+			name:        "employee shift overrides department shift",
+			employee:    models.Employee{DepartmentID: 1, Department: withDepartmentShift, Shift: weekdayShift(2, "Night", "22:00:00", "06:00:00")},
+			date:        "2026-10-16",
+			wantSource:  SourceEmployeeShift,
+			wantWorking: true,
+			wantStart:   "22:00:00",
+			wantEnd:     "06:00:00",
+			wantNight:   true,
+		},
+		{
+			name:        "holiday of another department is ignored",
+			employee:    models.Employee{DepartmentID: 1, Department: department},
+			date:        "2026-10-19",
+			wantSource:  SourceDepartment,
+			wantWorking: true,
+			wantStart:   "08:00:00",
+			wantEnd:     "17:00:00",
+		},
+		{
+			name:        "holiday for all departments keeps the shift hours",
+			employee:    models.Employee{DepartmentID: 1, Department: department},
+			date:        "2026-10-20",
+			wantSource:  SourceDepartment,
+			wantWorking: true,
+			wantStart:   "08:00:00",
+			wantEnd:     "17:00:00",
+			wantHoliday: "National Day",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := Resolve(tt.employee, date(tt.date), holidays)
+			if rule.Source != tt.wantSource || rule.WorkingDay != tt.wantWorking || rule.StartTime != tt.wantStart ||
+				rule.EndTime != tt.wantEnd || rule.Overnight != tt.wantNight || rule.Holiday != tt.wantHoliday {
+				t.Fatalf("Resolve() = %+v", rule)
+			}
+			if rule.IsWorkday() != (tt.wantWorking && tt.wantHoliday == "") {
+				t.Fatalf("IsWorkday() = %v", rule.IsWorkday())
+			}
+			if rule.Policy.LateMinutes != 60 {
+				t.Fatalf("policy not taken from the department: %+v", rule.Policy)
+			}
+		})
+	}
+}
+
+func TestWindow(t *testing.T) {
+	tests := []struct {
+		name      string
+		rule      Rule
+		wantStart string
+		wantEnd   string
+		wantOK    bool
+	}{
+		{name: "day shift", rule: Rule{WorkingDay: true, StartTime: "08:00:00", EndTime: "17:00:00"}, wantStart: "2026-10-16 08:00:00", wantEnd: "2026-10-16 17:00:00", wantOK: true},
+		{name: "overnight shift ends the next day", rule: Rule{WorkingDay: true, StartTime: "22:00:00", EndTime: "06:00:00", Overnight: true}, wantStart: "2026-10-16 22:00:00", wantEnd: "2026-10-17 06:00:00", wantOK: true},
+		{name: "day off", rule: Rule{}, wantOK: false},
+		{name: "invalid clock", rule: Rule{WorkingDay: true, StartTime: "8am", EndTime: "17:00:00"}, wantOK: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end, ok := tt.rule.Window(date("2026-10-16"))
+			if ok != tt.wantOK {
+				t.Fatalf("ok = %v, want %v", ok, tt.wantOK)
+			}
+			if !ok {
+				return
+			}
+			if got := start.Format("2006-01-02 15:04:05"); got != tt.wantStart {
+				t.Fatalf("start = %s, want %s", got, tt.wantStart)
+			}
+			if got := end.Format("2006-01-02 15:04:05"); got != tt.wantEnd {
+				t.Fatalf("end = %s, want %s", got, tt.wantEnd)
+			}
+		})
+	}
+}
+
+func TestIsOvernight(t *testing.T) {
+	tests := []struct {
+		start, end string
+		want       bool
+	}{
+		{"08:00:00", "17:00:00", false},
+		{"22:00:00", "06:00:00", true},
+		{"08:00:00", "08:00:00", true}, // shift 24 jam
+		{"", "06:00:00", false},
+	}
+	for _, tt := range tests {
+		if got := IsOvernight(tt.start, tt.end); got != tt.want {
+			t.Errorf("IsOvernight(%q, %q) = %v, want %v", tt.start, tt.end, got, tt.want)
+		}
+	}
+}
+
+func TestBusinessDate(t *testing.T) {
+	night := models.Employee{Department: models.Department{MaxClockInTime: "22:00:00", MaxClockOutTime: "06:00:00"}}
+	day := models.Employee{Department: models.Department{MaxClockInTime: "08:00:00", MaxClockOutTime: "17:00:00"}}
+
+	tests := []struct {
+		name     string
+		employee models.Employee
+		clockIn  string
+		want     string
+	}{
+		{name: "night shift before midnight", employee: night, clockIn: "2026-10-16 21:55:00", want: "2026-10-16"},
+		{name: "night shift after midnight", employee: night, clockIn: "2026-10-17 01:30:00", want: "2026-10-16"},
+		{name: "night shift after it ended", employee: night, clockIn: "2026-10-17 07:00:00", want: "2026-10-17"},
+		{name: "day shift after midnight", employee: day, clockIn: "2026-10-17 01:30:00", want: "2026-10-17"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := BusinessDate(tt.employee, dateTime(tt.clockIn)).Format(DateLayout); got != tt.want {
+				t.Fatalf("BusinessDate() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPolicyCheckIn(t *testing.T) {
+	policy := Policy{GracePeriodMinutes: 5, SlightlyLateMinutes: 15, LateMinutes: 60}
+	start := dateTime("2026-10-16 08:00:00")
+
+	tests := []struct {
+		clockIn     string
+		wantStatus  string
+		wantMinutes int
+	}{
+		{"2026-10-16 07:45:00", StatusOnTime, 0},
+		{"2026-10-16 08:05:00", StatusOnTime, 5},
+		{"2026-10-16 08:05:01", StatusSlightlyLate, 6}, // menit dibulatkan ke atas
+		{"2026-10-16 08:15:00", StatusSlightlyLate, 15},
+		{"2026-10-16 08:16:00", StatusLate, 16},
+		{"2026-10-16 09:00:00", StatusLate, 60},
+		{"2026-10-16 09:01:00", StatusVeryLate, 61},
+	}
+	for _, tt := range tests {
+		t.Run(tt.clockIn, func(t *testing.T) {
+			result := policy.CheckIn(start, dateTime(tt.clockIn))
+			if result.Status != tt.wantStatus || result.MinutesLate != tt.wantMinutes {
+				t.Fatalf("CheckIn() = %+v, want %s / %d minutes", result, tt.wantStatus, tt.wantMinutes)
+			}
+		})
+	}
+}
+
+func TestPolicyCheckOut(t *testing.T) {
+	policy := Policy{EarlyLeaveToleranceMinutes: 10}
+	end := dateTime("2026-10-16 17:00:00")
+
+	tests := []struct {
+		clockOut   string
+		wantStatus string
+	}{
+		{"2026-10-16 17:30:00", StatusOnTime},
+		{"2026-10-16 16:50:00", StatusOnTime},
+		{"2026-10-16 16:49:00", StatusEarlyLeave},
+	}
+	for _, tt := range tests {
+		if got := policy.CheckOut(end, dateTime(tt.clockOut)).Status; got != tt.wantStatus {
+			t.Errorf("CheckOut(%s) = %s, want %s", tt.clockOut, got, tt.wantStatus)
+		}
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	rule := Rule{WorkingDay: true, StartTime: "08:00:00", EndTime: "17:00:00", Policy: Policy{SlightlyLateMinutes: 15, LateMinutes: 60}}
+	holiday := rule
+	holiday.Holiday = "National Day"
+	businessDate := date("2026-10-16")
+
+	tests := []struct {
+		name  string
+		rule  Rule
+		event models.AttendanceEventType
+		punch string
+		want  string
+	}{
+		{name: "late clock in", rule: rule, event: models.AttendanceTypeClockIn, punch: "2026-10-16 08:20:00", want: StatusLate},
+		{name: "early clock out", rule: rule, event: models.AttendanceTypeClockOut, punch: "2026-10-16 16:00:00", want: StatusEarlyLeave},
+		{name: "holiday", rule: holiday, event: models.AttendanceTypeClockIn, punch: "2026-10-16 10:00:00", want: StatusHolidayWork},
+		{name: "day off", rule: Rule{}, event: models.AttendanceTypeClockIn, punch: "2026-10-16 10:00:00", want: StatusNonWorkingDay},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Evaluate(tt.rule, businessDate, tt.event, dateTime(tt.punch)).Status; got != tt.want {
+				t.Fatalf("Evaluate() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRoundOvertime(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  Policy
+		minutes int
+		want    int
+	}{
+		{name: "below minimum", policy: Policy{OvertimeMinMinutes: 30, OvertimeRoundingMinutes: 15}, minutes: 29, want: 0},
+		{name: "exactly minimum", policy: Policy{OvertimeMinMinutes: 30, OvertimeRoundingMinutes: 15}, minutes: 30, want: 30},
+		{name: "rounded down", policy: Policy{OvertimeMinMinutes: 30, OvertimeRoundingMinutes: 15}, minutes: 74, want: 60},
+		{name: "no rounding", policy: Policy{OvertimeMinMinutes: 30}, minutes: 74, want: 74},
+		{name: "negative", policy: Policy{}, minutes: -10, want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.policy.roundOvertime(tt.minutes); got != tt.want {
+				t.Fatalf("roundOvertime(%d) = %d, want %d", tt.minutes, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWorked(t *testing.T) {
+	rule := Rule{WorkingDay: true, StartTime: "08:00:00", EndTime: "17:00:00", Policy: Policy{OvertimeMinMinutes: 30, OvertimeRoundingMinutes: 15, MinBreakMinutes: 60}}
+	holiday := rule
+	holiday.Holiday = "National Day"
+	lunchEnd := dateTime("2026-10-16 12:30:00")
+	lunch := []Break{{Start: dateTime("2026-10-16 12:00:00"), End: &lunchEnd}}
+	lateBreakEnd := dateTime("2026-10-16 18:30:00")
+	lateBreak := []Break{{Start: dateTime("2026-10-16 18:00:00"), End: &lateBreakEnd}}
+
+	tests := []struct {
+		name         string
+		rule         Rule
+		clockIn      string
+		clockOut     string
+		breaks       []Break
+		wantWorked   int
+		wantOvertime int
+		wantType     string
+		wantShort    int
+	}{
+		{name: "regular day with lunch", rule: rule, clockIn: "2026-10-16 08:00:00", clockOut: "2026-10-16 17:00:00", breaks: lunch, wantWorked: 510, wantShort: 30},
+		{name: "overtime rounded down", rule: rule, clockIn: "2026-10-16 08:00:00", clockOut: "2026-10-16 18:20:00", breaks: lunch, wantWorked: 590, wantOvertime: 75, wantType: models.OvertimeWeekday, wantShort: 30},
+		{name: "break after shift is not overtime", rule: rule, clockIn: "2026-10-16 08:00:00", clockOut: "2026-10-16 18:30:00", breaks: lateBreak, wantWorked: 600, wantOvertime: 60, wantType: models.OvertimeWeekday, wantShort: 30},
+		{name: "holiday counts everything", rule: holiday, clockIn: "2026-10-16 09:00:00", clockOut: "2026-10-16 13:00:00", wantWorked: 240, wantOvertime: 240, wantType: models.OvertimeHoliday, wantShort: 60},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Worked(tt.rule, date("2026-10-16"), dateTime(tt.clockIn), dateTime(tt.clockOut), tt.breaks)
+			if got.WorkedMinutes != tt.wantWorked || got.OvertimeMinutes != tt.wantOvertime || got.OvertimeType != tt.wantType || got.BreakShortMinutes != tt.wantShort {
+				t.Fatalf("Worked() = %+v", got)
+			}
+		})
+	}
+}
+
+func TestBreaks(t *testing.T) {
+	histories := []models.AttendanceHistory{
+		{AttendanceType: models.AttendanceTypeBreakEnd, DateAttendance: dateTime("2026-10-16 12:45:00")},
+		{AttendanceType: models.AttendanceTypeClockIn, DateAttendance: dateTime("2026-10-16 08:00:00")},
+		{AttendanceType: models.AttendanceTypeBreakStart, DateAttendance: dateTime("2026-10-16 12:00:00")},
+		{AttendanceType: models.AttendanceTypeBreakStart, DateAttendance: dateTime("2026-10-16 15:00:00")},
+	}
+	breaks := Breaks(histories)
+	if len(breaks) != 2 || breaks[0].End == nil || !breaks[0].End.Equal(dateTime("2026-10-16 12:45:00")) {
+		t.Fatalf("Breaks() = %+v", breaks)
+	}
+	open, ok := OpenBreak(breaks)
+	if !ok || !open.Start.Equal(dateTime("2026-10-16 15:00:00")) {
+		t.Fatalf("OpenBreak() = %+v, %v", open, ok)
+	}
+	// Istirahat yang belum selesai dihitung sampai batas rentang
+	if got := breakTime(breaks, dateTime("2026-10-16 08:00:00"), dateTime("2026-10-16 16:00:00")); got != 105*time.Minute {
+		t.Fatalf("breakTime() = %s, want 1h45m", got)
+	}
+}
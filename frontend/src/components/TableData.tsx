@@ -144,9 +144,9 @@ export default function TableData({
                       {log.date_attendance}
                     </td>
                     <td className="p-2 text-center border">
-                      {log.attendance_type === 1
+                      {log.attendance_type === "clock_in"
                         ? "In"
-                        : log.attendance_type === 2
+                        : log.attendance_type === "clock_out"
                         ? "Out"
                         : "-"}
                     </td>
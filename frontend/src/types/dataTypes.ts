@@ -15,13 +15,22 @@ export type Employees = {
   department: Department;
 };
 
+export type AttendanceType =
+  | "clock_in"
+  | "clock_out"
+  | "leave"
+  | "absent"
+  | "correction"
+  | "break_start"
+  | "break_end";
+
 export type AttendanceLog = {
   id: number;
   employee_id: string;
   name?: string;
   attendance_id: string;
   date_attendance: string;
-  attendance_type: number;
+  attendance_type: AttendanceType;
   description: string;
   department?: string;
   clock_in?: string;